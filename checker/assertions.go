@@ -0,0 +1,246 @@
+package checker
+
+import (
+	"NetGuardServer/models"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxAssertionBodySize limits how much of a response body is read for body assertions
+const maxAssertionBodySize = 1 << 20
+
+// statusRange is an inclusive range of accepted HTTP status codes
+type statusRange struct {
+	min, max int
+}
+
+// parseStatusCodes parses a list like "200-299,301" into status ranges
+func parseStatusCodes(spec string) ([]statusRange, error) {
+	var ranges []statusRange
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		lo, hi, isRange := strings.Cut(part, "-")
+		min, err := strconv.Atoi(strings.TrimSpace(lo))
+		if err != nil {
+			return nil, fmt.Errorf("invalid status code %q", part)
+		}
+		max := min
+		if isRange {
+			if max, err = strconv.Atoi(strings.TrimSpace(hi)); err != nil {
+				return nil, fmt.Errorf("invalid status code range %q", part)
+			}
+		}
+		if min < 100 || max > 599 || min > max {
+			return nil, fmt.Errorf("invalid status code range %q", part)
+		}
+		ranges = append(ranges, statusRange{min: min, max: max})
+	}
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("no status codes in %q", spec)
+	}
+	return ranges, nil
+}
+
+// validateAssertions checks that the assertions of a server can be evaluated
+func validateAssertions(a models.HTTPAssertions) error {
+	if a.StatusCodes != "" {
+		if _, err := parseStatusCodes(a.StatusCodes); err != nil {
+			return err
+		}
+	}
+	if a.BodyRegex != "" {
+		if _, err := regexp.Compile(a.BodyRegex); err != nil {
+			return fmt.Errorf("invalid body regex: %v", err)
+		}
+	}
+	if a.JSONPath != "" {
+		if _, err := parseJSONPath(a.JSONPath); err != nil {
+			return err
+		}
+	}
+	if a.JSONPathValue != "" && a.JSONPath == "" {
+		return fmt.Errorf("json_path_value requires json_path")
+	}
+	return nil
+}
+
+// needsBody reports whether any assertion inspects the response body
+func needsBody(a models.HTTPAssertions) bool {
+	return a.BodyContains != "" || a.BodyNotContains != "" || a.BodyRegex != "" || a.JSONPath != ""
+}
+
+// evaluateAssertions returns a description of the first failing assertion, or "" if all pass
+func evaluateAssertions(a models.HTTPAssertions, resp *http.Response, body []byte, responseTime int64) string {
+	if a.StatusCodes != "" {
+		ranges, err := parseStatusCodes(a.StatusCodes)
+		if err != nil {
+			return err.Error()
+		}
+		accepted := false
+		for _, r := range ranges {
+			if resp.StatusCode >= r.min && resp.StatusCode <= r.max {
+				accepted = true
+				break
+			}
+		}
+		if !accepted {
+			return fmt.Sprintf("status code %d not in %s", resp.StatusCode, a.StatusCodes)
+		}
+	} else if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Sprintf("unexpected status code %d", resp.StatusCode)
+	}
+
+	for name, expected := range a.Headers {
+		values := resp.Header.Values(name)
+		if len(values) == 0 {
+			return fmt.Sprintf("missing response header %s", name)
+		}
+		if expected != "" && !containsFold(values, expected) {
+			return fmt.Sprintf("response header %s is %q, expected %q", name, strings.Join(values, ", "), expected)
+		}
+	}
+
+	if a.BodyContains != "" && !bytes.Contains(body, []byte(a.BodyContains)) {
+		return fmt.Sprintf("response body does not contain %q", a.BodyContains)
+	}
+	if a.BodyNotContains != "" && bytes.Contains(body, []byte(a.BodyNotContains)) {
+		return fmt.Sprintf("response body contains forbidden %q", a.BodyNotContains)
+	}
+	if a.BodyRegex != "" {
+		re, err := regexp.Compile(a.BodyRegex)
+		if err != nil {
+			return fmt.Sprintf("invalid body regex: %v", err)
+		}
+		if !re.Match(body) {
+			return fmt.Sprintf("response body does not match /%s/", a.BodyRegex)
+		}
+	}
+
+	if a.JSONPath != "" {
+		actual, err := lookupJSONPath(body, a.JSONPath)
+		if err != nil {
+			return err.Error()
+		}
+		if a.JSONPathValue != "" && actual != a.JSONPathValue {
+			return fmt.Sprintf("%s is %q, expected %q", a.JSONPath, actual, a.JSONPathValue)
+		}
+	}
+
+	if a.MaxResponseTimeMs > 0 && responseTime > a.MaxResponseTimeMs {
+		return fmt.Sprintf("response time %dms exceeds %dms", responseTime, a.MaxResponseTimeMs)
+	}
+
+	return ""
+}
+
+// containsFold reports whether any header value equals expected, ignoring case
+func containsFold(values []string, expected string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), expected) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonPathStep is one step of a JSON path: an object key or an array index
+type jsonPathStep struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parseJSONPath parses the supported JSONPath subset: $.key.nested[0].field
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("json path %q must start with $", path)
+	}
+
+	var steps []jsonPathStep
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid json path %q", path)
+			}
+			steps = append(steps, jsonPathStep{key: rest[:end]})
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid json path %q", path)
+			}
+			inner := rest[1:end]
+			if idx, err := strconv.Atoi(inner); err == nil && idx >= 0 {
+				steps = append(steps, jsonPathStep{index: idx, isIndex: true})
+			} else if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				steps = append(steps, jsonPathStep{key: inner[1 : len(inner)-1]})
+			} else {
+				return nil, fmt.Errorf("invalid json path %q", path)
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid json path %q", path)
+		}
+	}
+	return steps, nil
+}
+
+// lookupJSONPath evaluates a JSON path against a JSON document and returns the value as text.
+// Strings are returned as is; numbers, booleans, null, objects and arrays as their JSON encoding.
+func lookupJSONPath(body []byte, path string) (string, error) {
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", fmt.Errorf("response body is not valid JSON")
+	}
+
+	for _, step := range steps {
+		switch node := value.(type) {
+		case map[string]interface{}:
+			v, ok := node[step.key]
+			if step.isIndex || !ok {
+				return "", fmt.Errorf("%s not found in response body", path)
+			}
+			value = v
+		case []interface{}:
+			if !step.isIndex || step.index >= len(node) {
+				return "", fmt.Errorf("%s not found in response body", path)
+			}
+			value = node[step.index]
+		default:
+			return "", fmt.Errorf("%s not found in response body", path)
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	default:
+		encoded, _ := json.Marshal(v)
+		return string(encoded), nil
+	}
+}
@@ -0,0 +1,57 @@
+package checker
+
+import (
+	"NetGuardServer/models"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// certificateInfo describes the leaf certificate of a peer chain.
+// ExpiresAt is the earliest expiry of the whole chain, since any expired
+// certificate in it breaks verification.
+func certificateInfo(chain []*x509.Certificate) *models.CertificateInfo {
+	if len(chain) == 0 {
+		return nil
+	}
+
+	leaf := chain[0]
+	expiresAt := leaf.NotAfter
+	for _, cert := range chain[1:] {
+		if cert.NotAfter.Before(expiresAt) {
+			expiresAt = cert.NotAfter
+		}
+	}
+
+	sans := make([]string, 0, len(leaf.DNSNames)+len(leaf.IPAddresses))
+	sans = append(sans, leaf.DNSNames...)
+	for _, ip := range leaf.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+
+	now := time.Now()
+	return &models.CertificateInfo{
+		ExpiresAt: &expiresAt,
+		Issuer:    leaf.Issuer.String(),
+		Subject:   leaf.Subject.String(),
+		SANs:      sans,
+		CheckedAt: &now,
+	}
+}
+
+// certificateFailure extracts the peer chain from a TLS verification error and
+// describes the failure. It returns nil if err is not a certificate verification error.
+func certificateFailure(err error) (*models.CertificateInfo, string) {
+	var verifyErr *tls.CertificateVerificationError
+	if !errors.As(err, &verifyErr) {
+		return nil, ""
+	}
+
+	info := certificateInfo(verifyErr.UnverifiedCertificates)
+	if info != nil && time.Now().After(*info.ExpiresAt) {
+		return info, fmt.Sprintf("TLS certificate expired at %s", info.ExpiresAt.Format(time.RFC3339))
+	}
+	return info, fmt.Sprintf("TLS certificate verification failed: %v", verifyErr.Err)
+}
@@ -0,0 +1,94 @@
+// Package checker implements the server check types (HTTP, TCP, DNS, TLS) shared by the
+// backend probe scheduler and the API, so both agree on what "UP" means.
+// Heartbeat servers are not checked but ping the backend themselves.
+package checker
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/models"
+	"context"
+	"fmt"
+	"time"
+)
+
+// defaultTimeout is used when neither the server nor the configuration sets a timeout
+const defaultTimeout = 10 * time.Second
+
+// Result represents the outcome of a single check
+type Result struct {
+	Status       string                  `json:"status"`            // UP, DOWN
+	ResponseTime int64                   `json:"response_time"`     // milliseconds
+	Message      string                  `json:"message,omitempty"` // reason of a DOWN result
+	Certificate  *models.CertificateInfo `json:"certificate,omitempty"`
+}
+
+// Checker performs one type of check against a server
+type Checker interface {
+	Check(ctx context.Context, server *models.Server) Result
+}
+
+// up builds an UP result
+func up(start time.Time) Result {
+	return Result{Status: models.ServerStatusUp, ResponseTime: time.Since(start).Milliseconds()}
+}
+
+// down builds a DOWN result with a reason
+func down(start time.Time, format string, args ...interface{}) Result {
+	return Result{
+		Status:       models.ServerStatusDown,
+		ResponseTime: time.Since(start).Milliseconds(),
+		Message:      fmt.Sprintf(format, args...),
+	}
+}
+
+// Runner dispatches checks to the checker registered for the server's check type
+type Runner struct {
+	checkers map[string]Checker
+	timeout  time.Duration
+}
+
+// NewRunner creates a runner with the given checkers and default timeout
+func NewRunner(checkers map[string]Checker, timeout time.Duration) *Runner {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Runner{
+		checkers: checkers,
+		timeout:  timeout,
+	}
+}
+
+// NewDefaultRunner creates a runner with all built-in check types,
+// using the probe timeout from the configuration
+func NewDefaultRunner() *Runner {
+	return NewRunner(map[string]Checker{
+		models.CheckTypeHTTP: NewHTTPChecker(),
+		models.CheckTypeTCP:  NewTCPChecker(),
+		models.CheckTypeDNS:  NewDNSChecker(),
+		models.CheckTypeTLS:  NewTLSChecker(),
+	}, time.Duration(config.AppConfig.Probe.TimeoutSeconds)*time.Second)
+}
+
+// Check runs the check matching the server's check type.
+// The server's own timeout takes precedence over the runner default.
+func (r *Runner) Check(ctx context.Context, server *models.Server) Result {
+	checkType := server.CheckType
+	if checkType == "" {
+		checkType = models.CheckTypeHTTP
+	}
+
+	c, ok := r.checkers[checkType]
+	if !ok {
+		return Result{Status: models.ServerStatusDown, Message: fmt.Sprintf("unsupported check type %s", checkType)}
+	}
+
+	timeout := r.timeout
+	if server.TimeoutSeconds > 0 {
+		timeout = time.Duration(server.TimeoutSeconds) * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return c.Check(ctx, server)
+}
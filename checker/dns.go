@@ -0,0 +1,104 @@
+package checker
+
+import (
+	"NetGuardServer/models"
+	"context"
+	"net"
+	"strings"
+	"time"
+)
+
+// DNSChecker resolves a record of the server host and optionally
+// requires one of the answers to match an expected value
+type DNSChecker struct {
+	Resolver *net.Resolver
+}
+
+// NewDNSChecker creates a DNS resolve checker using the system resolver
+func NewDNSChecker() *DNSChecker {
+	return &DNSChecker{Resolver: net.DefaultResolver}
+}
+
+// Check performs the DNS resolve check
+func (c *DNSChecker) Check(ctx context.Context, server *models.Server) Result {
+	start := time.Now()
+
+	recordType := strings.ToUpper(server.DNSRecordType)
+	if recordType == "" {
+		recordType = "A"
+	}
+
+	answers, err := c.lookup(ctx, server.Host, recordType)
+	if err != nil {
+		return down(start, "%s lookup for %s failed: %v", recordType, server.Host, err)
+	}
+	if len(answers) == 0 {
+		return down(start, "no %s records for %s", recordType, server.Host)
+	}
+
+	if server.DNSExpected != "" {
+		expected := normalizeDNSName(server.DNSExpected)
+		for _, answer := range answers {
+			if normalizeDNSName(answer) == expected {
+				return up(start)
+			}
+		}
+		return down(start, "%s records for %s do not include %s", recordType, server.Host, server.DNSExpected)
+	}
+
+	return up(start)
+}
+
+// lookup resolves the given record type and returns the answers as strings
+func (c *DNSChecker) lookup(ctx context.Context, host, recordType string) ([]string, error) {
+	var answers []string
+
+	switch recordType {
+	case "A", "AAAA":
+		addrs, err := c.Resolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			isV4 := addr.IP.To4() != nil
+			if (recordType == "A") == isV4 {
+				answers = append(answers, addr.IP.String())
+			}
+		}
+	case "CNAME":
+		cname, err := c.Resolver.LookupCNAME(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, cname)
+	case "MX":
+		records, err := c.Resolver.LookupMX(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range records {
+			answers = append(answers, mx.Host)
+		}
+	case "NS":
+		records, err := c.Resolver.LookupNS(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range records {
+			answers = append(answers, ns.Host)
+		}
+	case "TXT":
+		records, err := c.Resolver.LookupTXT(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, records...)
+	}
+
+	return answers, nil
+}
+
+// normalizeDNSName makes DNS answers comparable regardless of case and trailing dot
+func normalizeDNSName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
@@ -0,0 +1,39 @@
+package checker
+
+import (
+	"NetGuardServer/models"
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestDNSChecker(t *testing.T) {
+	// localhost is answered from the hosts file, so no DNS server is needed
+	checker := &DNSChecker{Resolver: &net.Resolver{PreferGo: true}}
+
+	tests := []struct {
+		name     string
+		host     string
+		expected string
+		want     string
+	}{
+		{name: "record resolves", host: "localhost", want: models.ServerStatusUp},
+		{name: "expected answer", host: "localhost", expected: "127.0.0.1", want: models.ServerStatusUp},
+		{name: "unexpected answer is DOWN", host: "localhost", expected: "10.0.0.1", want: models.ServerStatusDown},
+		{name: "unknown host is DOWN", host: "netguard-test.invalid", want: models.ServerStatusDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			server := &models.Server{CheckType: models.CheckTypeDNS, Host: tt.host, DNSRecordType: "A", DNSExpected: tt.expected}
+			result := checker.Check(ctx, server)
+			if result.Status != tt.want {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.want, result.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package checker
+
+import (
+	"NetGuardServer/models"
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HTTPChecker issues a GET request to the server URL.
+// Without assertions any 2xx/3xx response counts as UP; transport errors and 4xx/5xx
+// responses count as DOWN. The server's assertions (status codes, body, JSON path,
+// headers, response time) can tighten that, and the first failing one is reported.
+type HTTPChecker struct {
+	Client *http.Client
+}
+
+// NewHTTPChecker creates an HTTP checker; timeouts come from the request context
+func NewHTTPChecker() *HTTPChecker {
+	return &HTTPChecker{Client: &http.Client{}}
+}
+
+// Check performs the HTTP check
+func (c *HTTPChecker) Check(ctx context.Context, server *models.Server) Result {
+	start := time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		return down(start, "invalid URL: %v", err)
+	}
+	req.Header.Set("User-Agent", "NetGuard-Probe/1.0")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		if cert, failure := certificateFailure(err); failure != "" {
+			result := down(start, "%s", failure)
+			result.Certificate = cert
+			return result
+		}
+		return down(start, "request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var cert *models.CertificateInfo
+	if resp.TLS != nil {
+		cert = certificateInfo(resp.TLS.PeerCertificates)
+	}
+
+	// Response time is measured up to the response headers, like without body assertions
+	responseTime := time.Since(start).Milliseconds()
+
+	var body []byte
+	if needsBody(server.Assertions) {
+		body, err = io.ReadAll(io.LimitReader(resp.Body, maxAssertionBodySize))
+		if err != nil {
+			return down(start, "failed to read response body: %v", err)
+		}
+	}
+
+	if failure := evaluateAssertions(server.Assertions, resp, body, responseTime); failure != "" {
+		return Result{Status: models.ServerStatusDown, ResponseTime: responseTime, Message: failure, Certificate: cert}
+	}
+
+	return Result{Status: models.ServerStatusUp, ResponseTime: responseTime, Certificate: cert}
+}
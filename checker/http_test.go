@@ -0,0 +1,110 @@
+package checker
+
+import (
+	"NetGuardServer/models"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPChecker(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Version", "2.1")
+		w.Write([]byte(`{"data":{"status":"ok"},"items":[{"ok":true}]}`))
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ok", http.StatusFound)
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/error", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("maintenance"))
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	tests := []struct {
+		name       string
+		path       string
+		assertions models.HTTPAssertions
+		want       string
+	}{
+		{name: "2xx is UP", path: "/ok", want: models.ServerStatusUp},
+		{name: "redirect to 2xx is UP", path: "/redirect", want: models.ServerStatusUp},
+		{name: "404 is DOWN", path: "/missing", want: models.ServerStatusDown},
+		{name: "503 is DOWN", path: "/error", want: models.ServerStatusDown},
+		{name: "accepted status code is UP", path: "/error", assertions: models.HTTPAssertions{StatusCodes: "200-299,503"}, want: models.ServerStatusUp},
+		{name: "body contains", path: "/ok", assertions: models.HTTPAssertions{BodyContains: `"status":"ok"`}, want: models.ServerStatusUp},
+		{name: "body does not contain", path: "/ok", assertions: models.HTTPAssertions{BodyNotContains: "error"}, want: models.ServerStatusUp},
+		{name: "missing body text is DOWN", path: "/ok", assertions: models.HTTPAssertions{BodyContains: "healthy"}, want: models.ServerStatusDown},
+		{name: "JSON path matches", path: "/ok", assertions: models.HTTPAssertions{JSONPath: "$.data.status", JSONPathValue: "ok"}, want: models.ServerStatusUp},
+		{name: "JSON path array element", path: "/ok", assertions: models.HTTPAssertions{JSONPath: "$.items[0].ok", JSONPathValue: "true"}, want: models.ServerStatusUp},
+		{name: "JSON path mismatch is DOWN", path: "/ok", assertions: models.HTTPAssertions{JSONPath: "$.data.status", JSONPathValue: "degraded"}, want: models.ServerStatusDown},
+		{name: "header matches", path: "/ok", assertions: models.HTTPAssertions{Headers: map[string]string{"X-Version": "2.1"}}, want: models.ServerStatusUp},
+		{name: "missing header is DOWN", path: "/ok", assertions: models.HTTPAssertions{Headers: map[string]string{"X-Region": ""}}, want: models.ServerStatusDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &models.Server{CheckType: models.CheckTypeHTTP, URL: ts.URL + tt.path, Assertions: tt.assertions}
+			if err := Validate(server); err != nil {
+				t.Fatalf("invalid server: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			result := NewHTTPChecker().Check(ctx, server)
+			if result.Status != tt.want {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.want, result.Message)
+			}
+			if tt.want == models.ServerStatusDown && result.Message == "" {
+				t.Errorf("DOWN result has no reason")
+			}
+		})
+	}
+}
+
+func TestRunnerTimeout(t *testing.T) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	runner := NewRunner(map[string]Checker{models.CheckTypeHTTP: NewHTTPChecker()}, 100*time.Millisecond)
+
+	tests := []struct {
+		name        string
+		server      models.Server
+		want        string
+		wantMessage string
+	}{
+		{name: "slow server times out", server: models.Server{URL: ts.URL}, want: models.ServerStatusDown, wantMessage: "request failed"},
+		{name: "unsupported check type", server: models.Server{CheckType: models.CheckTypeDNS, Host: "localhost"}, want: models.ServerStatusDown, wantMessage: "unsupported check type DNS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			result := runner.Check(context.Background(), &tt.server)
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("check took %s, want the runner timeout", elapsed)
+			}
+			if result.Status != tt.want || !strings.Contains(result.Message, tt.wantMessage) {
+				t.Errorf("got %s (%s), want %s (%s)", result.Status, result.Message, tt.want, tt.wantMessage)
+			}
+		})
+	}
+}
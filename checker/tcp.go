@@ -0,0 +1,33 @@
+package checker
+
+import (
+	"NetGuardServer/models"
+	"context"
+	"net"
+	"strconv"
+	"time"
+)
+
+// TCPChecker opens a TCP connection to host:port
+type TCPChecker struct {
+	Dialer *net.Dialer
+}
+
+// NewTCPChecker creates a TCP connect checker
+func NewTCPChecker() *TCPChecker {
+	return &TCPChecker{Dialer: &net.Dialer{}}
+}
+
+// Check performs the TCP connect check
+func (c *TCPChecker) Check(ctx context.Context, server *models.Server) Result {
+	start := time.Now()
+
+	address := net.JoinHostPort(server.Host, strconv.Itoa(server.Port))
+	conn, err := c.Dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return down(start, "connect to %s failed: %v", address, err)
+	}
+	conn.Close()
+
+	return up(start)
+}
@@ -0,0 +1,64 @@
+package checker
+
+import (
+	"NetGuardServer/models"
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTCPChecker(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	openPort := listener.Addr().(*net.TCPAddr).Port
+
+	// A port that was just released refuses connections
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	closedPort := closed.Addr().(*net.TCPAddr).Port
+	closed.Close()
+
+	tests := []struct {
+		name string
+		port int
+		want string
+	}{
+		{name: "listening port is UP", port: openPort, want: models.ServerStatusUp},
+		{name: "closed port is DOWN", port: closedPort, want: models.ServerStatusDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			server := &models.Server{CheckType: models.CheckTypeTCP, Host: "127.0.0.1", Port: tt.port}
+			result := NewTCPChecker().Check(ctx, server)
+			if result.Status != tt.want {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.want, result.Message)
+			}
+			if tt.want == models.ServerStatusDown && result.Message == "" {
+				t.Errorf("DOWN result has no reason")
+			}
+			if got := Target(server); got != "tcp://127.0.0.1:"+strconv.Itoa(tt.port) {
+				t.Errorf("target = %s", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package checker
+
+import (
+	"NetGuardServer/models"
+	"context"
+	"crypto/tls"
+	"net"
+	"strconv"
+	"time"
+)
+
+// defaultTLSPort is used when a TLS check has no port
+const defaultTLSPort = 443
+
+// TLSChecker performs a TLS handshake with host:port and verifies the peer certificate
+type TLSChecker struct {
+	Dialer *net.Dialer
+	// Config is cloned for every check; ServerName is set from the server host.
+	// Tests can set RootCAs here to trust a local certificate.
+	Config *tls.Config
+}
+
+// NewTLSChecker creates a TLS handshake checker using the system roots
+func NewTLSChecker() *TLSChecker {
+	return &TLSChecker{
+		Dialer: &net.Dialer{},
+		Config: &tls.Config{},
+	}
+}
+
+// Check performs the TLS handshake check
+func (c *TLSChecker) Check(ctx context.Context, server *models.Server) Result {
+	start := time.Now()
+
+	port := server.Port
+	if port == 0 {
+		port = defaultTLSPort
+	}
+	address := net.JoinHostPort(server.Host, strconv.Itoa(port))
+
+	cfg := c.Config.Clone()
+	cfg.ServerName = server.Host
+
+	dialer := &tls.Dialer{NetDialer: c.Dialer, Config: cfg}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		if cert, failure := certificateFailure(err); failure != "" {
+			result := down(start, "%s", failure)
+			result.Certificate = cert
+			return result
+		}
+		return down(start, "TLS handshake with %s failed: %v", address, err)
+	}
+	defer conn.Close()
+
+	result := up(start)
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		result.Certificate = certificateInfo(tlsConn.ConnectionState().PeerCertificates)
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+package checker
+
+import (
+	"NetGuardServer/models"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTLSChecker(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	addr := ts.Listener.Addr().(*net.TCPAddr)
+	trusted := x509.NewCertPool()
+	trusted.AddCert(ts.Certificate())
+
+	tests := []struct {
+		name        string
+		roots       *x509.CertPool
+		want        string
+		wantMessage string
+	}{
+		{name: "trusted certificate is UP", roots: trusted, want: models.ServerStatusUp},
+		{name: "untrusted certificate is DOWN", roots: x509.NewCertPool(), want: models.ServerStatusDown, wantMessage: "TLS certificate verification failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			checker := NewTLSChecker()
+			checker.Config = &tls.Config{RootCAs: tt.roots}
+			server := &models.Server{CheckType: models.CheckTypeTLS, Host: addr.IP.String(), Port: addr.Port}
+
+			result := checker.Check(ctx, server)
+			if result.Status != tt.want {
+				t.Fatalf("status = %s, want %s (%s)", result.Status, tt.want, result.Message)
+			}
+			if !strings.Contains(result.Message, tt.wantMessage) {
+				t.Errorf("message = %q, want it to contain %q", result.Message, tt.wantMessage)
+			}
+			// The certificate is reported either way, so expiry warnings work for broken chains too
+			if result.Certificate == nil || result.Certificate.ExpiresAt == nil {
+				t.Fatalf("certificate not reported")
+			}
+			if !result.Certificate.ExpiresAt.Equal(ts.Certificate().NotAfter) {
+				t.Errorf("expires at %s, want %s", result.Certificate.ExpiresAt, ts.Certificate().NotAfter)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package checker
+
+import (
+	"NetGuardServer/models"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Validate checks that a server has the settings required by its check type
+func Validate(server *models.Server) error {
+	switch server.CheckType {
+	case "", models.CheckTypeHTTP:
+		u, err := url.Parse(server.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("HTTP checks require an http(s) URL")
+		}
+		if err := validateAssertions(server.Assertions); err != nil {
+			return err
+		}
+	case models.CheckTypeTCP:
+		if server.Host == "" || server.Port == 0 {
+			return errors.New("TCP checks require host and port")
+		}
+	case models.CheckTypeDNS:
+		if server.Host == "" {
+			return errors.New("DNS checks require host")
+		}
+		if server.DNSRecordType != "" && !isSupportedRecordType(server.DNSRecordType) {
+			return errors.New("DNS record type must be A, AAAA, CNAME, MX, NS, or TXT")
+		}
+	case models.CheckTypeTLS:
+		if server.Host == "" {
+			return errors.New("TLS checks require host")
+		}
+	case models.CheckTypeHeartbeat:
+		if server.HeartbeatPeriodSeconds <= 0 {
+			return errors.New("heartbeat checks require heartbeat period")
+		}
+	default:
+		return errors.New("check type must be HTTP, TCP, DNS, TLS, or HEARTBEAT")
+	}
+	return nil
+}
+
+// Target describes what a server check connects to, e.g. "tcp://db.local:5432".
+// HTTP checks use the server URL as is; heartbeat servers describe their period.
+func Target(server *models.Server) string {
+	switch server.CheckType {
+	case models.CheckTypeTCP:
+		return "tcp://" + net.JoinHostPort(server.Host, strconv.Itoa(server.Port))
+	case models.CheckTypeDNS:
+		recordType := server.DNSRecordType
+		if recordType == "" {
+			recordType = "A"
+		}
+		return fmt.Sprintf("dns://%s?type=%s", server.Host, strings.ToUpper(recordType))
+	case models.CheckTypeTLS:
+		port := server.Port
+		if port == 0 {
+			port = defaultTLSPort
+		}
+		return "tls://" + net.JoinHostPort(server.Host, strconv.Itoa(port))
+	case models.CheckTypeHeartbeat:
+		// The token is a secret, so it is not part of the URL shown in notifications
+		return fmt.Sprintf("heartbeat://every/%ds", server.HeartbeatPeriodSeconds)
+	}
+	return server.URL
+}
+
+// isSupportedRecordType reports whether the DNS checker can resolve the record type
+func isSupportedRecordType(recordType string) bool {
+	switch strings.ToUpper(recordType) {
+	case "A", "AAAA", "CNAME", "MX", "NS", "TXT":
+		return true
+	}
+	return false
+}
@@ -3,7 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 
 	"NetGuardServer/models"
 
@@ -20,10 +22,12 @@ type DatabaseConfig struct {
 	Port     int
 }
 
-// JWTConfig holds JWT configuration
+// JWTConfig holds JWT configuration.
+// Access tokens are short-lived and renewed with a rotating refresh token.
 type JWTConfig struct {
-	Secret             string
-	AccessTokenExpDays int
+	Secret                string
+	AccessTokenExpMinutes int
+	RefreshTokenExpDays   int
 }
 
 type FirebaseConfig struct {
@@ -35,6 +39,80 @@ type ServerConfig struct {
 	Port string
 }
 
+// ProbeConfig holds the backend probe scheduler configuration
+type ProbeConfig struct {
+	Enabled         bool
+	IntervalSeconds int
+	TimeoutSeconds  int
+	Concurrency     int
+}
+
+// MetricsConfig holds check result rollup and retention configuration
+type MetricsConfig struct {
+	RollupIntervalMinutes int
+	RawRetentionDays      int
+}
+
+// HeartbeatConfig holds the heartbeat monitor configuration
+type HeartbeatConfig struct {
+	CheckIntervalSeconds int
+}
+
+// EscalationConfig holds the escalation worker configuration
+type EscalationConfig struct {
+	CheckIntervalSeconds int
+}
+
+// SMTPConfig holds the SMTP server used by email notification channels
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// NotificationConfig holds notification message and rate limit configuration.
+// Server alerts beyond UserRateLimit per user or ChannelRateLimit per notification channel
+// within RateWindowSeconds are held back and sent in a digest DigestIntervalSeconds after
+// the first of them. A limit of 0 disables it.
+type NotificationConfig struct {
+	DefaultLocale         string // locale of channel messages and of users without locale (en, id)
+	UserRateLimit         int
+	ChannelRateLimit      int
+	RateWindowSeconds     int
+	DigestIntervalSeconds int
+}
+
+// OutboxConfig holds the notification outbox worker configuration.
+// A failed delivery is retried after RetryBaseSeconds, doubling up to RetryMaxSeconds,
+// and is dead-lettered after MaxAttempts attempts.
+type OutboxConfig struct {
+	PollIntervalSeconds int
+	MaxAttempts         int
+	RetryBaseSeconds    int
+	RetryMaxSeconds     int
+}
+
+// ChannelConfig holds notification channel (webhook, email, chat) configuration
+type ChannelConfig struct {
+	TimeoutSeconds int
+}
+
+// FlapConfig holds flap detection configuration.
+// A server starts flapping at StartThreshold state changes within the window
+// and stops once it is down to StopThreshold.
+type FlapConfig struct {
+	WindowMinutes  int
+	StartThreshold int
+	StopThreshold  int
+}
+
+// CertConfig holds TLS certificate expiry warning configuration
+type CertConfig struct {
+	WarningDays []int // days before expiry at which a warning is sent, descending
+}
+
 // Config holds all application configurations
 type Config struct {
 	Database                   DatabaseConfig
@@ -42,6 +120,16 @@ type Config struct {
 	Firebase                   FirebaseConfig
 	FirebaseServiceAccountPath string
 	Server                     ServerConfig
+	Probe                      ProbeConfig
+	Metrics                    MetricsConfig
+	Cert                       CertConfig
+	Heartbeat                  HeartbeatConfig
+	Flap                       FlapConfig
+	Escalation                 EscalationConfig
+	SMTP                       SMTPConfig
+	Channels                   ChannelConfig
+	Outbox                     OutboxConfig
+	Notification               NotificationConfig
 	DB                         *gorm.DB
 }
 
@@ -60,14 +148,60 @@ func LoadConfig() error {
 
 	// Load JWT configuration from environment variables
 	AppConfig.JWT.Secret = getEnv("JWT_SECRET", "kiraraberntein_adkjdkfhdjfhudhd")
-	expStr := getEnv("ACCESS_TOKEN_EXP_DAYS", "7")
-	AppConfig.JWT.AccessTokenExpDays, _ = strconv.Atoi(expStr)
+	AppConfig.JWT.AccessTokenExpMinutes, _ = strconv.Atoi(getEnv("ACCESS_TOKEN_EXP_MINUTES", "15"))
+	AppConfig.JWT.RefreshTokenExpDays, _ = strconv.Atoi(getEnv("REFRESH_TOKEN_EXP_DAYS", "30"))
 
 	AppConfig.Firebase.FirebaseServiceAccountPath = getEnv("FIREBASE_SERVICE_ACCOUNT_PATH", "config/netguard-7b734-9c58282275ac.json")
 
 	// Load server configuration from environment variables
 	AppConfig.Server.Port = getEnv("PORT", "8080")
 
+	// Load probe scheduler configuration from environment variables
+	AppConfig.Probe.Enabled, _ = strconv.ParseBool(getEnv("PROBE_ENABLED", "true"))
+	AppConfig.Probe.IntervalSeconds, _ = strconv.Atoi(getEnv("PROBE_INTERVAL_SECONDS", "60"))
+	AppConfig.Probe.TimeoutSeconds, _ = strconv.Atoi(getEnv("PROBE_TIMEOUT_SECONDS", "10"))
+	AppConfig.Probe.Concurrency, _ = strconv.Atoi(getEnv("PROBE_CONCURRENCY", "10"))
+
+	// Load metrics rollup and retention configuration from environment variables
+	AppConfig.Metrics.RollupIntervalMinutes, _ = strconv.Atoi(getEnv("METRICS_ROLLUP_INTERVAL_MINUTES", "5"))
+	AppConfig.Metrics.RawRetentionDays, _ = strconv.Atoi(getEnv("METRICS_RAW_RETENTION_DAYS", "30"))
+
+	// Load certificate expiry warning thresholds from environment variables
+	AppConfig.Cert.WarningDays = getEnvIntList("CERT_WARNING_DAYS", "30,14,7,1")
+	sort.Sort(sort.Reverse(sort.IntSlice(AppConfig.Cert.WarningDays)))
+
+	// Load heartbeat monitor configuration from environment variables
+	AppConfig.Heartbeat.CheckIntervalSeconds, _ = strconv.Atoi(getEnv("HEARTBEAT_CHECK_INTERVAL_SECONDS", "30"))
+
+	// Load flap detection configuration from environment variables
+	AppConfig.Flap.WindowMinutes, _ = strconv.Atoi(getEnv("FLAP_WINDOW_MINUTES", "30"))
+	AppConfig.Flap.StartThreshold, _ = strconv.Atoi(getEnv("FLAP_START_THRESHOLD", "6"))
+	AppConfig.Flap.StopThreshold, _ = strconv.Atoi(getEnv("FLAP_STOP_THRESHOLD", "2"))
+
+	// Load escalation worker configuration from environment variables
+	AppConfig.Escalation.CheckIntervalSeconds, _ = strconv.Atoi(getEnv("ESCALATION_CHECK_INTERVAL_SECONDS", "30"))
+
+	// Load notification channel configuration from environment variables
+	AppConfig.Channels.TimeoutSeconds, _ = strconv.Atoi(getEnv("CHANNEL_TIMEOUT_SECONDS", "10"))
+	AppConfig.SMTP.Host = getEnv("SMTP_HOST", "")
+	AppConfig.SMTP.Port, _ = strconv.Atoi(getEnv("SMTP_PORT", "587"))
+	AppConfig.SMTP.Username = getEnv("SMTP_USERNAME", "")
+	AppConfig.SMTP.Password = getEnv("SMTP_PASSWORD", "")
+	AppConfig.SMTP.From = getEnv("SMTP_FROM", "netguard@localhost")
+
+	// Load notification message configuration from environment variables
+	AppConfig.Notification.DefaultLocale = getEnv("NOTIFICATION_LOCALE", "en")
+	AppConfig.Notification.UserRateLimit, _ = strconv.Atoi(getEnv("NOTIFICATION_USER_RATE_LIMIT", "5"))
+	AppConfig.Notification.ChannelRateLimit, _ = strconv.Atoi(getEnv("NOTIFICATION_CHANNEL_RATE_LIMIT", "10"))
+	AppConfig.Notification.RateWindowSeconds, _ = strconv.Atoi(getEnv("NOTIFICATION_RATE_WINDOW_SECONDS", "60"))
+	AppConfig.Notification.DigestIntervalSeconds, _ = strconv.Atoi(getEnv("NOTIFICATION_DIGEST_INTERVAL_SECONDS", "300"))
+
+	// Load notification outbox configuration from environment variables
+	AppConfig.Outbox.PollIntervalSeconds, _ = strconv.Atoi(getEnv("OUTBOX_POLL_INTERVAL_SECONDS", "5"))
+	AppConfig.Outbox.MaxAttempts, _ = strconv.Atoi(getEnv("OUTBOX_MAX_ATTEMPTS", "8"))
+	AppConfig.Outbox.RetryBaseSeconds, _ = strconv.Atoi(getEnv("OUTBOX_RETRY_BASE_SECONDS", "30"))
+	AppConfig.Outbox.RetryMaxSeconds, _ = strconv.Atoi(getEnv("OUTBOX_RETRY_MAX_SECONDS", "3600"))
+
 	// Load Firebase service account path
 	AppConfig.FirebaseServiceAccountPath = getEnv("FIREBASE_SERVICE_ACCOUNT_PATH", "config/netguard-7b734-9c58282275ac.json")
 
@@ -124,7 +258,29 @@ func InitDatabase() error {
 	if err := db.AutoMigrate(
 		&models.User{},
 		&models.Server{},
+		&models.ServerDependency{},
 		&models.ServerDownHistory{},
+		&models.HistoryReporter{},
+		&models.HistoryEvent{},
+		&models.CheckResult{},
+		&models.CheckRollup{},
+		&models.HeartbeatPing{},
+		&models.MaintenanceWindow{},
+		&models.EscalationPolicy{},
+		&models.EscalationLevel{},
+		&models.OnCallSchedule{},
+		&models.OnCallLayer{},
+		&models.OnCallOverride{},
+		&models.DeviceToken{},
+		&models.NotificationPreference{},
+		&models.NotificationChannel{},
+		&models.NotificationOutbox{},
+		&models.NotificationAttempt{},
+		&models.NotificationTemplate{},
+		&models.NotificationRateCounter{},
+		&models.NotificationDigestItem{},
+		&models.RefreshToken{},
+		&models.RevokedToken{},
 	); err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
@@ -140,3 +296,16 @@ func getEnv(key, defaultVal string) string {
 	}
 	return defaultVal
 }
+
+// getEnvIntList gets a comma separated list of positive integers from an environment variable,
+// skipping invalid entries
+func getEnvIntList(key, defaultVal string) []int {
+	var values []int
+	for _, part := range strings.Split(getEnv(key, defaultVal), ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(part))
+		if err == nil && value > 0 {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -33,7 +33,7 @@ func (ctrl *AuthController) Register(c *fiber.Ctx) error {
 		return utils.SendError(c, fiber.StatusBadRequest, "Validation failed: "+err.Error())
 	}
 
-	user, token, err := ctrl.authService.Register(req.Name, req.Email, req.Password, req.Division, req.Phone, "USER")
+	user, tokens, err := ctrl.authService.Register(req.Name, req.Email, req.Password, req.Division, req.Phone, "USER")
 	if err != nil {
 		if appErr, ok := err.(utils.AppError); ok {
 			switch appErr.Code {
@@ -55,12 +55,13 @@ func (ctrl *AuthController) Register(c *fiber.Ctx) error {
 		Email:     user.Email,
 		Division:  user.Division,
 		Phone:     user.Phone,
+		Locale:    user.Locale,
 		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 
 	response := dto.AuthResponse{
-		Token: token,
-		User:  userDTO,
+		TokenResponse: *tokens,
+		User:          userDTO,
 	}
 
 	return utils.SendSuccess(c, "User registered successfully", response)
@@ -78,7 +79,7 @@ func (ctrl *AuthController) Login(c *fiber.Ctx) error {
 		return utils.SendError(c, fiber.StatusBadRequest, "Validation failed: "+err.Error())
 	}
 
-	user, token, err := ctrl.authService.Login(req.Email, req.Password)
+	user, tokens, err := ctrl.authService.Login(req.Email, req.Password)
 	if err != nil {
 		if appErr, ok := err.(utils.AppError); ok {
 			switch appErr.Code {
@@ -98,17 +99,70 @@ func (ctrl *AuthController) Login(c *fiber.Ctx) error {
 		Email:     user.Email,
 		Division:  user.Division,
 		Phone:     user.Phone,
+		Locale:    user.Locale,
 		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 
 	response := dto.AuthResponse{
-		Token: token,
-		User:  userDTO,
+		TokenResponse: *tokens,
+		User:          userDTO,
 	}
 
 	return utils.SendSuccess(c, "Login successful", response)
 }
 
+// Refresh handles renewing an access token with a refresh token. The refresh token is
+// rotated: the response holds a new one and the presented one can no longer be used.
+func (ctrl *AuthController) Refresh(c *fiber.Ctx) error {
+	var req dto.RefreshTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Validate request
+	if err := utils.ValidateStruct(req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Validation failed: "+err.Error())
+	}
+
+	tokens, err := ctrl.authService.Refresh(req.RefreshToken)
+	if err != nil {
+		switch err.Error() {
+		case "invalid refresh token", "refresh token reuse detected":
+			return utils.SendError(c, fiber.StatusUnauthorized, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "Token refreshed successfully", tokens)
+}
+
+// Logout handles revoking the current access token and its refresh tokens
+func (ctrl *AuthController) Logout(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+	tokenID, ok := c.Locals("token_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	uid, err := uuid.Parse(userID)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+	tid, err := uuid.Parse(tokenID)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Invalid token ID")
+	}
+
+	if err := ctrl.authService.Logout(uid, tid); err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "Logout successful", nil)
+}
+
 // GetProfile returns the current user's profile
 func (ctrl *AuthController) GetProfile(c *fiber.Ctx) error {
 	// Get user ID from JWT claims (set by middleware)
@@ -138,6 +192,7 @@ func (ctrl *AuthController) GetProfile(c *fiber.Ctx) error {
 		Email:     user.Email,
 		Division:  user.Division,
 		Phone:     user.Phone,
+		Locale:    user.Locale,
 		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 
@@ -168,7 +223,7 @@ func (ctrl *AuthController) UpdateProfile(c *fiber.Ctx) error {
 		return utils.SendError(c, fiber.StatusBadRequest, "Validation failed: "+err.Error())
 	}
 
-	user, err := ctrl.authService.UpdateProfile(uid, req.Name, req.Division, req.Phone)
+	user, err := ctrl.authService.UpdateProfile(uid, req.Name, req.Division, req.Phone, req.Locale)
 	if err != nil {
 		if appErr, ok := err.(utils.AppError); ok && appErr.Code == "NOT_FOUND" {
 			return utils.SendError(c, fiber.StatusNotFound, appErr.Message)
@@ -183,6 +238,7 @@ func (ctrl *AuthController) UpdateProfile(c *fiber.Ctx) error {
 		Email:     user.Email,
 		Division:  user.Division,
 		Phone:     user.Phone,
+		Locale:    user.Locale,
 		CreatedAt: user.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"NetGuardServer/dto"
+	"NetGuardServer/services"
+	"NetGuardServer/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// DeviceController handles FCM device token HTTP requests
+type DeviceController struct {
+	deviceService services.DeviceService
+}
+
+// NewDeviceController creates a new device controller
+func NewDeviceController(deviceService services.DeviceService) *DeviceController {
+	return &DeviceController{
+		deviceService: deviceService,
+	}
+}
+
+// RegisterDevice handles registering the FCM token of a device of the current user
+func (ctrl *DeviceController) RegisterDevice(c *fiber.Ctx) error {
+	// Get user ID from JWT
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req dto.RegisterDeviceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Validate request
+	if err := utils.ValidateStruct(req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Validation failed: "+err.Error())
+	}
+
+	device, err := ctrl.deviceService.RegisterDevice(userID, req)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "Device registered successfully", device)
+}
+
+// GetDevices handles getting the registered devices of the current user
+func (ctrl *DeviceController) GetDevices(c *fiber.Ctx) error {
+	// Get user ID from JWT
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	devices, err := ctrl.deviceService.GetDevices(userID)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendData(c, devices)
+}
+
+// UnregisterDevice handles unregistering a device of the current user
+func (ctrl *DeviceController) UnregisterDevice(c *fiber.Ctx) error {
+	// Get user ID from JWT
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req dto.UnregisterDeviceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Validate request
+	if err := utils.ValidateStruct(req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Validation failed: "+err.Error())
+	}
+
+	if err := ctrl.deviceService.UnregisterDevice(userID, req.Token); err != nil {
+		if err.Error() == "device not found" {
+			return utils.SendError(c, fiber.StatusNotFound, "Device not found")
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "Device unregistered successfully", nil)
+}
@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"NetGuardServer/dto"
+	"NetGuardServer/services"
+	"NetGuardServer/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// EscalationController handles escalation policy HTTP requests
+type EscalationController struct {
+	escalationService services.EscalationService
+}
+
+// NewEscalationController creates a new escalation policy controller
+func NewEscalationController(escalationService services.EscalationService) *EscalationController {
+	return &EscalationController{
+		escalationService: escalationService,
+	}
+}
+
+// CreatePolicy handles escalation policy creation
+func (ctrl *EscalationController) CreatePolicy(c *fiber.Ctx) error {
+	// Get user ID from JWT
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req dto.CreateEscalationPolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Validate request
+	if err := utils.ValidateStruct(req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Validation failed: "+err.Error())
+	}
+
+	policy, err := ctrl.escalationService.CreatePolicy(userID, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok && appErr.Code == "VALIDATION_ERROR" {
+			return utils.SendError(c, fiber.StatusBadRequest, appErr.Message)
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "Escalation policy created successfully", policy)
+}
+
+// GetPolicies handles getting all escalation policies
+func (ctrl *EscalationController) GetPolicies(c *fiber.Ctx) error {
+	policies, err := ctrl.escalationService.GetPolicies()
+	if err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendData(c, policies)
+}
+
+// GetPolicy handles getting a specific escalation policy by ID
+func (ctrl *EscalationController) GetPolicy(c *fiber.Ctx) error {
+	policyID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid escalation policy ID")
+	}
+
+	policy, err := ctrl.escalationService.GetPolicyByID(policyID)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, "Escalation policy not found")
+	}
+
+	return utils.SendData(c, policy)
+}
+
+// UpdatePolicy handles escalation policy updates
+func (ctrl *EscalationController) UpdatePolicy(c *fiber.Ctx) error {
+	policyID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid escalation policy ID")
+	}
+
+	// Get user ID from JWT for authorization
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req dto.UpdateEscalationPolicyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Validate request
+	if err := utils.ValidateStruct(req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Validation failed: "+err.Error())
+	}
+
+	policy, err := ctrl.escalationService.UpdatePolicy(policyID, userID, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok && appErr.Code == "VALIDATION_ERROR" {
+			return utils.SendError(c, fiber.StatusBadRequest, appErr.Message)
+		}
+		if err.Error() == "escalation policy not found" {
+			return utils.SendError(c, fiber.StatusNotFound, err.Error())
+		}
+		if err.Error() == "access denied" {
+			return utils.SendError(c, fiber.StatusForbidden, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "Escalation policy updated successfully", policy)
+}
+
+// DeletePolicy handles escalation policy deletion
+func (ctrl *EscalationController) DeletePolicy(c *fiber.Ctx) error {
+	policyID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid escalation policy ID")
+	}
+
+	// Get user ID from JWT for authorization
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	err = ctrl.escalationService.DeletePolicy(policyID, userID)
+	if err != nil {
+		if err.Error() == "escalation policy not found" {
+			return utils.SendError(c, fiber.StatusNotFound, err.Error())
+		}
+		if err.Error() == "access denied" {
+			return utils.SendError(c, fiber.StatusForbidden, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "Escalation policy deleted successfully", nil)
+}
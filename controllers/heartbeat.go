@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"NetGuardServer/dto"
+	"NetGuardServer/models"
+	"NetGuardServer/services"
+	"NetGuardServer/utils"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// HeartbeatController handles heartbeat ping HTTP requests
+type HeartbeatController struct {
+	heartbeatService services.HeartbeatService
+	serverService    services.ServerService
+}
+
+// NewHeartbeatController creates a new heartbeat controller
+func NewHeartbeatController(heartbeatService services.HeartbeatService, serverService services.ServerService) *HeartbeatController {
+	return &HeartbeatController{
+		heartbeatService: heartbeatService,
+		serverService:    serverService,
+	}
+}
+
+// Ping handles a heartbeat ping; the secret token in the URL authenticates the caller
+func (ctrl *HeartbeatController) Ping(c *fiber.Ctx) error {
+	var req dto.HeartbeatPingRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return utils.SendError(c, fiber.StatusBadRequest, "Invalid request body")
+		}
+
+		// Validate request
+		if err := utils.ValidateStruct(req); err != nil {
+			return utils.SendError(c, fiber.StatusBadRequest, "Validation failed: "+err.Error())
+		}
+	}
+
+	ping, err := ctrl.heartbeatService.RecordPing(c.Params("token"), req, c.IP())
+	if err != nil {
+		if err.Error() == "heartbeat not found" {
+			return utils.SendError(c, fiber.StatusNotFound, "Heartbeat not found")
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "Heartbeat received", ping)
+}
+
+// GetPings handles getting the latest pings of a heartbeat server
+func (ctrl *HeartbeatController) GetPings(c *fiber.Ctx) error {
+	serverID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid server ID")
+	}
+
+	server, err := ctrl.serverService.GetServerByID(serverID)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, "Server not found")
+	}
+	if server.CheckType != models.CheckTypeHeartbeat {
+		return utils.SendError(c, fiber.StatusBadRequest, "Server is not a heartbeat monitor")
+	}
+
+	var historyID *uuid.UUID
+	if historyIDStr := c.Query("history_id"); historyIDStr != "" {
+		id, err := uuid.Parse(historyIDStr)
+		if err != nil {
+			return utils.SendError(c, fiber.StatusBadRequest, "Invalid history ID")
+		}
+		historyID = &id
+	}
+
+	// Get limit from query params (default 50)
+	limitStr := c.Query("limit", "50")
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+
+	// Limit maximum records
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	pings, err := ctrl.heartbeatService.GetPings(serverID, historyID, limit)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendData(c, pings)
+}
@@ -4,6 +4,8 @@ import (
 	"NetGuardServer/services"
 	"NetGuardServer/utils"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -63,7 +65,7 @@ func (ctrl *HistoryController) CreateHistory(c *fiber.Ctx) error {
 		return utils.SendError(c, fiber.StatusBadRequest, "Status must be DOWN for history records")
 	}
 
-	history, err := ctrl.historyService.CreateHistory(serverID, req.ServerName, req.URL, req.Status, userID)
+	history, _, err := ctrl.historyService.CreateHistory(serverID, req.ServerName, req.URL, req.Status, "", userID, nil)
 	if err != nil {
 		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
 	}
@@ -118,6 +120,195 @@ func (ctrl *HistoryController) ResolveHistory(c *fiber.Ctx) error {
 	return utils.SendSuccess(c, "History record resolved successfully", history)
 }
 
+// AcknowledgeHistory handles acknowledging a history record
+func (ctrl *HistoryController) AcknowledgeHistory(c *fiber.Ctx) error {
+	historyIDStr := c.Params("id")
+	historyID, err := uuid.Parse(historyIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid history ID")
+	}
+
+	// Get user ID from JWT
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	history, err := ctrl.historyService.AcknowledgeHistory(historyID, userID)
+	if err != nil {
+		if err.Error() == "history record not found" {
+			return utils.SendError(c, fiber.StatusNotFound, err.Error())
+		}
+		if err.Error() == "history record already resolved" || err.Error() == "history record already acknowledged" {
+			return utils.SendError(c, fiber.StatusConflict, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "History record acknowledged successfully", history)
+}
+
+// AssignHistoryRequest represents the assign history request payload
+type AssignHistoryRequest struct {
+	AssigneeID string `json:"assignee_id"`
+}
+
+// AssignHistory handles assigning a history record to a user
+func (ctrl *HistoryController) AssignHistory(c *fiber.Ctx) error {
+	historyIDStr := c.Params("id")
+	historyID, err := uuid.Parse(historyIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid history ID")
+	}
+
+	// Get user ID from JWT
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req AssignHistoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if req.AssigneeID == "" {
+		return utils.SendError(c, fiber.StatusBadRequest, "Assignee ID is required")
+	}
+
+	assigneeID, err := uuid.Parse(req.AssigneeID)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid assignee ID")
+	}
+
+	history, err := ctrl.historyService.AssignHistory(historyID, assigneeID, userID)
+	if err != nil {
+		if err.Error() == "history record not found" {
+			return utils.SendError(c, fiber.StatusNotFound, err.Error())
+		}
+		if err.Error() == "assignee not found" {
+			return utils.SendError(c, fiber.StatusBadRequest, err.Error())
+		}
+		if err.Error() == "history record already resolved" {
+			return utils.SendError(c, fiber.StatusConflict, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "History record assigned successfully", history)
+}
+
+// GetHistoryByID handles getting a single history record with its timeline
+func (ctrl *HistoryController) GetHistoryByID(c *fiber.Ctx) error {
+	historyID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid history ID")
+	}
+
+	history, err := ctrl.historyService.GetHistoryDetail(historyID)
+	if err != nil {
+		if err.Error() == "history record not found" {
+			return utils.SendError(c, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendData(c, history)
+}
+
+// GetTimeline handles getting the timeline of a history record
+func (ctrl *HistoryController) GetTimeline(c *fiber.Ctx) error {
+	historyID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid history ID")
+	}
+
+	timeline, err := ctrl.historyService.GetTimeline(historyID)
+	if err != nil {
+		if err.Error() == "history record not found" {
+			return utils.SendError(c, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendData(c, timeline)
+}
+
+// GetDeliveryLog handles getting the notifications of a history record and their delivery attempts
+func (ctrl *HistoryController) GetDeliveryLog(c *fiber.Ctx) error {
+	historyID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid history ID")
+	}
+
+	notifications, err := ctrl.historyService.GetDeliveryLog(historyID)
+	if err != nil {
+		if err.Error() == "history record not found" {
+			return utils.SendError(c, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendData(c, notifications)
+}
+
+// AddCommentRequest represents the add timeline comment request payload
+type AddCommentRequest struct {
+	Message string `json:"message"`
+}
+
+// AddComment handles adding a comment to the timeline of a history record
+func (ctrl *HistoryController) AddComment(c *fiber.Ctx) error {
+	historyID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid history ID")
+	}
+
+	// Get user ID from JWT
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req AddCommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	req.Message = strings.TrimSpace(req.Message)
+	if req.Message == "" {
+		return utils.SendError(c, fiber.StatusBadRequest, "Message is required")
+	}
+	if len(req.Message) > 2000 {
+		return utils.SendError(c, fiber.StatusBadRequest, "Message must be at most 2000 characters")
+	}
+
+	event, err := ctrl.historyService.AddComment(historyID, userID, req.Message)
+	if err != nil {
+		if err.Error() == "history record not found" {
+			return utils.SendError(c, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "Comment added successfully", event)
+}
+
 // GetMonthlyReport handles monthly report generation
 func (ctrl *HistoryController) GetMonthlyReport(c *fiber.Ctx) error {
 	yearStr := c.Query("year")
@@ -137,15 +328,21 @@ func (ctrl *HistoryController) GetMonthlyReport(c *fiber.Ctx) error {
 		return utils.SendError(c, fiber.StatusBadRequest, "Invalid month")
 	}
 
-	report, err := ctrl.historyService.GetMonthlyReport(year, month)
+	loc, err := time.LoadLocation(c.Query("timezone", "UTC"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid timezone")
+	}
+
+	report, err := ctrl.historyService.GetMonthlyReport(year, month, loc)
 	if err != nil {
 		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
 	}
 
 	return utils.SendSuccess(c, "Monthly report generated successfully", fiber.Map{
-		"year":   year,
-		"month":  month,
-		"report": report,
+		"year":     year,
+		"month":    month,
+		"timezone": loc.String(),
+		"report":   report,
 	})
 }
 
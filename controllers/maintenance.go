@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"NetGuardServer/dto"
+	"NetGuardServer/services"
+	"NetGuardServer/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// MaintenanceController handles maintenance window HTTP requests
+type MaintenanceController struct {
+	maintenanceService services.MaintenanceService
+}
+
+// NewMaintenanceController creates a new maintenance window controller
+func NewMaintenanceController(maintenanceService services.MaintenanceService) *MaintenanceController {
+	return &MaintenanceController{
+		maintenanceService: maintenanceService,
+	}
+}
+
+// CreateWindow handles maintenance window creation
+func (ctrl *MaintenanceController) CreateWindow(c *fiber.Ctx) error {
+	// Get user ID from JWT
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req dto.CreateMaintenanceWindowRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Validate request
+	if err := utils.ValidateStruct(req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Validation failed: "+err.Error())
+	}
+
+	window, err := ctrl.maintenanceService.CreateWindow(userID, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok && appErr.Code == "VALIDATION_ERROR" {
+			return utils.SendError(c, fiber.StatusBadRequest, appErr.Message)
+		}
+		if err.Error() == "access denied" {
+			return utils.SendError(c, fiber.StatusForbidden, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "Maintenance window created successfully", window)
+}
+
+// GetWindows handles getting maintenance windows, optionally of one server or in progress only
+func (ctrl *MaintenanceController) GetWindows(c *fiber.Ctx) error {
+	var serverID *uuid.UUID
+	if serverIDStr := c.Query("server_id"); serverIDStr != "" {
+		id, err := uuid.Parse(serverIDStr)
+		if err != nil {
+			return utils.SendError(c, fiber.StatusBadRequest, "Invalid server ID")
+		}
+		serverID = &id
+	}
+
+	windows, err := ctrl.maintenanceService.GetWindows(serverID, c.QueryBool("active"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendData(c, windows)
+}
+
+// GetWindow handles getting a specific maintenance window by ID
+func (ctrl *MaintenanceController) GetWindow(c *fiber.Ctx) error {
+	windowID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid maintenance window ID")
+	}
+
+	window, err := ctrl.maintenanceService.GetWindowByID(windowID)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, "Maintenance window not found")
+	}
+
+	return utils.SendData(c, window)
+}
+
+// UpdateWindow handles maintenance window updates
+func (ctrl *MaintenanceController) UpdateWindow(c *fiber.Ctx) error {
+	windowID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid maintenance window ID")
+	}
+
+	// Get user ID from JWT for authorization
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req dto.UpdateMaintenanceWindowRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Validate request
+	if err := utils.ValidateStruct(req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Validation failed: "+err.Error())
+	}
+
+	window, err := ctrl.maintenanceService.UpdateWindow(windowID, userID, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok && appErr.Code == "VALIDATION_ERROR" {
+			return utils.SendError(c, fiber.StatusBadRequest, appErr.Message)
+		}
+		if err.Error() == "maintenance window not found" {
+			return utils.SendError(c, fiber.StatusNotFound, err.Error())
+		}
+		if err.Error() == "access denied" {
+			return utils.SendError(c, fiber.StatusForbidden, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "Maintenance window updated successfully", window)
+}
+
+// DeleteWindow handles maintenance window deletion
+func (ctrl *MaintenanceController) DeleteWindow(c *fiber.Ctx) error {
+	windowID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid maintenance window ID")
+	}
+
+	// Get user ID from JWT for authorization
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	err = ctrl.maintenanceService.DeleteWindow(windowID, userID)
+	if err != nil {
+		if err.Error() == "maintenance window not found" {
+			return utils.SendError(c, fiber.StatusNotFound, err.Error())
+		}
+		if err.Error() == "access denied" {
+			return utils.SendError(c, fiber.StatusForbidden, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "Maintenance window deleted successfully", nil)
+}
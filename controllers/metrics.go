@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"NetGuardServer/models"
+	"NetGuardServer/services"
+	"NetGuardServer/utils"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// MetricsController handles server metrics HTTP requests
+type MetricsController struct {
+	metricsService services.MetricsService
+	serverService  services.ServerService
+}
+
+// NewMetricsController creates a new metrics controller
+func NewMetricsController(metricsService services.MetricsService, serverService services.ServerService) *MetricsController {
+	return &MetricsController{
+		metricsService: metricsService,
+		serverService:  serverService,
+	}
+}
+
+// GetServerMetrics handles getting the response time / availability series of a server
+func (ctrl *MetricsController) GetServerMetrics(c *fiber.Ctx) error {
+	serverID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid server ID")
+	}
+
+	if _, err := ctrl.serverService.GetServerByID(serverID); err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, "Server not found")
+	}
+
+	// Default range: last 24 hours
+	to := time.Now()
+	if toStr := c.Query("to"); toStr != "" {
+		to, err = time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return utils.SendError(c, fiber.StatusBadRequest, "Invalid to. Use RFC3339 format")
+		}
+	}
+
+	from := to.Add(-24 * time.Hour)
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err = time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return utils.SendError(c, fiber.StatusBadRequest, "Invalid from. Use RFC3339 format")
+		}
+	}
+
+	resolution := c.Query("resolution", models.ResolutionHour)
+
+	metrics, err := ctrl.metricsService.GetServerMetrics(serverID, from, to, resolution)
+	if err != nil {
+		if err.Error() == "failed to get metrics" {
+			return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return utils.SendData(c, metrics)
+}
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"NetGuardServer/services"
+	"NetGuardServer/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// NotificationController handles notification outbox HTTP requests
+type NotificationController struct {
+	notificationService services.NotificationService
+}
+
+// NewNotificationController creates a new notification controller
+func NewNotificationController(notificationService services.NotificationService) *NotificationController {
+	return &NotificationController{
+		notificationService: notificationService,
+	}
+}
+
+// GetOutbox handles getting the latest outbox notifications, e.g. the dead ones with ?status=DEAD
+func (ctrl *NotificationController) GetOutbox(c *fiber.Ctx) error {
+	limit, err := strconv.Atoi(c.Query("limit", "100"))
+	if err != nil || limit <= 0 {
+		limit = 100
+	}
+
+	// Limit maximum records
+	if limit > 1000 {
+		limit = 1000
+	}
+
+	notifications, err := ctrl.notificationService.GetOutbox(strings.ToUpper(c.Query("status")), limit)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok && appErr.Code == "VALIDATION_ERROR" {
+			return utils.SendError(c, fiber.StatusBadRequest, appErr.Message)
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendData(c, notifications)
+}
+
+// RetryNotification handles scheduling a dead notification for another delivery attempt
+func (ctrl *NotificationController) RetryNotification(c *fiber.Ctx) error {
+	notificationID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid notification ID")
+	}
+
+	notification, err := ctrl.notificationService.RetryNotification(notificationID)
+	if err != nil {
+		switch err.Error() {
+		case "notification not found":
+			return utils.SendError(c, fiber.StatusNotFound, err.Error())
+		case "only dead notifications can be retried":
+			return utils.SendError(c, fiber.StatusConflict, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "Notification scheduled for retry", notification)
+}
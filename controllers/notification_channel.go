@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"NetGuardServer/dto"
+	"NetGuardServer/services"
+	"NetGuardServer/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// NotificationChannelController handles notification channel HTTP requests
+type NotificationChannelController struct {
+	channelService services.NotificationChannelService
+}
+
+// NewNotificationChannelController creates a new notification channel controller
+func NewNotificationChannelController(channelService services.NotificationChannelService) *NotificationChannelController {
+	return &NotificationChannelController{
+		channelService: channelService,
+	}
+}
+
+// CreateChannel handles notification channel creation
+func (ctrl *NotificationChannelController) CreateChannel(c *fiber.Ctx) error {
+	// Get user ID from JWT
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req dto.CreateNotificationChannelRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Validate request
+	if err := utils.ValidateStruct(req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Validation failed: "+err.Error())
+	}
+
+	channel, err := ctrl.channelService.CreateChannel(userID, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok && appErr.Code == "VALIDATION_ERROR" {
+			return utils.SendError(c, fiber.StatusBadRequest, appErr.Message)
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "Notification channel created successfully", channel)
+}
+
+// GetChannels handles getting notification channels, optionally only those attached to a server
+func (ctrl *NotificationChannelController) GetChannels(c *fiber.Ctx) error {
+	var serverID *uuid.UUID
+	if serverIDStr := c.Query("server_id"); serverIDStr != "" {
+		id, err := uuid.Parse(serverIDStr)
+		if err != nil {
+			return utils.SendError(c, fiber.StatusBadRequest, "Invalid server ID")
+		}
+		serverID = &id
+	}
+
+	channels, err := ctrl.channelService.GetChannels(serverID)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendData(c, channels)
+}
+
+// GetChannel handles getting a specific notification channel by ID
+func (ctrl *NotificationChannelController) GetChannel(c *fiber.Ctx) error {
+	channelID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid notification channel ID")
+	}
+
+	channel, err := ctrl.channelService.GetChannelByID(channelID)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, "Notification channel not found")
+	}
+
+	return utils.SendData(c, channel)
+}
+
+// UpdateChannel handles notification channel updates
+func (ctrl *NotificationChannelController) UpdateChannel(c *fiber.Ctx) error {
+	channelID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid notification channel ID")
+	}
+
+	// Get user ID from JWT for authorization
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req dto.UpdateNotificationChannelRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Validate request
+	if err := utils.ValidateStruct(req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Validation failed: "+err.Error())
+	}
+
+	channel, err := ctrl.channelService.UpdateChannel(channelID, userID, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok && appErr.Code == "VALIDATION_ERROR" {
+			return utils.SendError(c, fiber.StatusBadRequest, appErr.Message)
+		}
+		if err.Error() == "notification channel not found" {
+			return utils.SendError(c, fiber.StatusNotFound, err.Error())
+		}
+		if err.Error() == "access denied" {
+			return utils.SendError(c, fiber.StatusForbidden, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "Notification channel updated successfully", channel)
+}
+
+// DeleteChannel handles notification channel deletion
+func (ctrl *NotificationChannelController) DeleteChannel(c *fiber.Ctx) error {
+	channelID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid notification channel ID")
+	}
+
+	// Get user ID from JWT for authorization
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	err = ctrl.channelService.DeleteChannel(channelID, userID)
+	if err != nil {
+		if err.Error() == "notification channel not found" {
+			return utils.SendError(c, fiber.StatusNotFound, err.Error())
+		}
+		if err.Error() == "access denied" {
+			return utils.SendError(c, fiber.StatusForbidden, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "Notification channel deleted successfully", nil)
+}
+
+// TestChannel handles sending a test message over a notification channel
+func (ctrl *NotificationChannelController) TestChannel(c *fiber.Ctx) error {
+	channelID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid notification channel ID")
+	}
+
+	// Get user ID from JWT for authorization
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	if err := ctrl.channelService.TestChannel(channelID, userID); err != nil {
+		if err.Error() == "notification channel not found" {
+			return utils.SendError(c, fiber.StatusNotFound, err.Error())
+		}
+		if err.Error() == "access denied" {
+			return utils.SendError(c, fiber.StatusForbidden, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusBadGateway, err.Error())
+	}
+
+	return utils.SendSuccess(c, "Test notification sent successfully", nil)
+}
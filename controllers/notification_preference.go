@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"NetGuardServer/dto"
+	"NetGuardServer/services"
+	"NetGuardServer/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// NotificationPreferenceController handles notification preference HTTP requests
+type NotificationPreferenceController struct {
+	preferenceService services.NotificationPreferenceService
+}
+
+// NewNotificationPreferenceController creates a new notification preference controller
+func NewNotificationPreferenceController(preferenceService services.NotificationPreferenceService) *NotificationPreferenceController {
+	return &NotificationPreferenceController{
+		preferenceService: preferenceService,
+	}
+}
+
+// GetPreferences handles getting the notification preferences of the current user
+func (ctrl *NotificationPreferenceController) GetPreferences(c *fiber.Ctx) error {
+	// Get user ID from JWT
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	preference, err := ctrl.preferenceService.GetPreferences(userID)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendData(c, preference)
+}
+
+// UpdatePreferences handles updating the notification preferences of the current user
+func (ctrl *NotificationPreferenceController) UpdatePreferences(c *fiber.Ctx) error {
+	// Get user ID from JWT
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req dto.UpdateNotificationPreferenceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Validate request
+	if err := utils.ValidateStruct(req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Validation failed: "+err.Error())
+	}
+
+	preference, err := ctrl.preferenceService.UpdatePreferences(userID, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok && appErr.Code == "VALIDATION_ERROR" {
+			return utils.SendError(c, fiber.StatusBadRequest, appErr.Message)
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "Notification preferences updated successfully", preference)
+}
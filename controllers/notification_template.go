@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"NetGuardServer/dto"
+	"NetGuardServer/services"
+	"NetGuardServer/utils"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// NotificationTemplateController handles notification template HTTP requests
+type NotificationTemplateController struct {
+	templateService services.NotificationTemplateService
+}
+
+// NewNotificationTemplateController creates a new notification template controller
+func NewNotificationTemplateController(templateService services.NotificationTemplateService) *NotificationTemplateController {
+	return &NotificationTemplateController{
+		templateService: templateService,
+	}
+}
+
+// GetTemplates handles getting the current notification templates, optionally filtered
+// with ?event=, ?channel= and ?locale=
+func (ctrl *NotificationTemplateController) GetTemplates(c *fiber.Ctx) error {
+	templates, err := ctrl.templateService.GetTemplates(
+		strings.ToUpper(c.Query("event")),
+		strings.ToUpper(c.Query("channel")),
+		strings.ToLower(c.Query("locale")),
+	)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendData(c, templates)
+}
+
+// UpdateTemplate handles setting the template of an event for a channel and locale
+func (ctrl *NotificationTemplateController) UpdateTemplate(c *fiber.Ctx) error {
+	// Get user ID from JWT
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req dto.UpdateNotificationTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Validate request
+	if err := utils.ValidateStruct(req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Validation failed: "+err.Error())
+	}
+
+	event, channel, locale := templateKey(c)
+	template, err := ctrl.templateService.UpdateTemplate(event, channel, locale, userID, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok && appErr.Code == "VALIDATION_ERROR" {
+			return utils.SendError(c, fiber.StatusBadRequest, appErr.Message)
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "Notification template updated successfully", template)
+}
+
+// DeleteTemplate handles resetting the template of an event for a channel and locale
+// to the built-in template
+func (ctrl *NotificationTemplateController) DeleteTemplate(c *fiber.Ctx) error {
+	event, channel, locale := templateKey(c)
+	if err := ctrl.templateService.DeleteTemplate(event, channel, locale); err != nil {
+		if appErr, ok := err.(utils.AppError); ok && appErr.Code == "VALIDATION_ERROR" {
+			return utils.SendError(c, fiber.StatusBadRequest, appErr.Message)
+		}
+		if err.Error() == "notification template not found" {
+			return utils.SendError(c, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "Notification template reset to default", nil)
+}
+
+// PreviewTemplate handles rendering a notification template against a sample incident
+func (ctrl *NotificationTemplateController) PreviewTemplate(c *fiber.Ctx) error {
+	var req dto.PreviewNotificationTemplateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Validate request
+	if err := utils.ValidateStruct(req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Validation failed: "+err.Error())
+	}
+
+	req.Event = strings.ToUpper(req.Event)
+	req.Channel = strings.ToUpper(req.Channel)
+	req.Locale = strings.ToLower(req.Locale)
+
+	preview, err := ctrl.templateService.PreviewTemplate(req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok && appErr.Code == "VALIDATION_ERROR" {
+			return utils.SendError(c, fiber.StatusBadRequest, appErr.Message)
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendData(c, preview)
+}
+
+// templateKey returns the event, channel and locale of a template from the route params
+func templateKey(c *fiber.Ctx) (string, string, string) {
+	return strings.ToUpper(c.Params("event")), strings.ToUpper(c.Params("channel")), strings.ToLower(c.Params("locale"))
+}
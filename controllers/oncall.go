@@ -0,0 +1,285 @@
+package controllers
+
+import (
+	"NetGuardServer/dto"
+	"NetGuardServer/services"
+	"NetGuardServer/utils"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// OnCallController handles on-call schedule HTTP requests
+type OnCallController struct {
+	onCallService services.OnCallService
+}
+
+// NewOnCallController creates a new on-call schedule controller
+func NewOnCallController(onCallService services.OnCallService) *OnCallController {
+	return &OnCallController{
+		onCallService: onCallService,
+	}
+}
+
+// CreateSchedule handles on-call schedule creation
+func (ctrl *OnCallController) CreateSchedule(c *fiber.Ctx) error {
+	// Get user ID from JWT
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req dto.CreateOnCallScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Validate request
+	if err := utils.ValidateStruct(req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Validation failed: "+err.Error())
+	}
+
+	schedule, err := ctrl.onCallService.CreateSchedule(userID, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok && appErr.Code == "VALIDATION_ERROR" {
+			return utils.SendError(c, fiber.StatusBadRequest, appErr.Message)
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "On-call schedule created successfully", schedule)
+}
+
+// GetSchedules handles getting all on-call schedules
+func (ctrl *OnCallController) GetSchedules(c *fiber.Ctx) error {
+	schedules, err := ctrl.onCallService.GetSchedules()
+	if err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendData(c, schedules)
+}
+
+// GetSchedule handles getting a specific on-call schedule by ID
+func (ctrl *OnCallController) GetSchedule(c *fiber.Ctx) error {
+	scheduleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid on-call schedule ID")
+	}
+
+	schedule, err := ctrl.onCallService.GetScheduleByID(scheduleID)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, "On-call schedule not found")
+	}
+
+	return utils.SendData(c, schedule)
+}
+
+// UpdateSchedule handles on-call schedule updates
+func (ctrl *OnCallController) UpdateSchedule(c *fiber.Ctx) error {
+	scheduleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid on-call schedule ID")
+	}
+
+	// Get user ID from JWT for authorization
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req dto.UpdateOnCallScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Validate request
+	if err := utils.ValidateStruct(req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Validation failed: "+err.Error())
+	}
+
+	schedule, err := ctrl.onCallService.UpdateSchedule(scheduleID, userID, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok && appErr.Code == "VALIDATION_ERROR" {
+			return utils.SendError(c, fiber.StatusBadRequest, appErr.Message)
+		}
+		if err.Error() == "on-call schedule not found" {
+			return utils.SendError(c, fiber.StatusNotFound, err.Error())
+		}
+		if err.Error() == "access denied" {
+			return utils.SendError(c, fiber.StatusForbidden, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "On-call schedule updated successfully", schedule)
+}
+
+// DeleteSchedule handles on-call schedule deletion
+func (ctrl *OnCallController) DeleteSchedule(c *fiber.Ctx) error {
+	scheduleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid on-call schedule ID")
+	}
+
+	// Get user ID from JWT for authorization
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	err = ctrl.onCallService.DeleteSchedule(scheduleID, userID)
+	if err != nil {
+		if err.Error() == "on-call schedule not found" {
+			return utils.SendError(c, fiber.StatusNotFound, err.Error())
+		}
+		if err.Error() == "access denied" {
+			return utils.SendError(c, fiber.StatusForbidden, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "On-call schedule deleted successfully", nil)
+}
+
+// CreateOverride handles adding an override to an on-call schedule
+func (ctrl *OnCallController) CreateOverride(c *fiber.Ctx) error {
+	scheduleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid on-call schedule ID")
+	}
+
+	// Get user ID from JWT
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	var req dto.CreateOnCallOverrideRequest
+	if err := c.BodyParser(&req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	// Validate request
+	if err := utils.ValidateStruct(req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Validation failed: "+err.Error())
+	}
+
+	override, err := ctrl.onCallService.CreateOverride(scheduleID, userID, req)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok && appErr.Code == "VALIDATION_ERROR" {
+			return utils.SendError(c, fiber.StatusBadRequest, appErr.Message)
+		}
+		if err.Error() == "on-call schedule not found" {
+			return utils.SendError(c, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "On-call override created successfully", override)
+}
+
+// DeleteOverride handles removing an override from an on-call schedule
+func (ctrl *OnCallController) DeleteOverride(c *fiber.Ctx) error {
+	scheduleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid on-call schedule ID")
+	}
+
+	overrideID, err := uuid.Parse(c.Params("overrideId"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid on-call override ID")
+	}
+
+	// Get user ID from JWT for authorization
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	err = ctrl.onCallService.DeleteOverride(scheduleID, overrideID, userID)
+	if err != nil {
+		if err.Error() == "on-call schedule not found" || err.Error() == "on-call override not found" {
+			return utils.SendError(c, fiber.StatusNotFound, err.Error())
+		}
+		if err.Error() == "access denied" {
+			return utils.SendError(c, fiber.StatusForbidden, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "On-call override deleted successfully", nil)
+}
+
+// GetOnCall handles "who is on call" for one schedule, now or at the time given by ?at=
+func (ctrl *OnCallController) GetOnCall(c *fiber.Ctx) error {
+	scheduleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid on-call schedule ID")
+	}
+
+	at, err := parseAt(c)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid at, must be RFC3339")
+	}
+
+	onCall, err := ctrl.onCallService.GetOnCall(scheduleID, at)
+	if err != nil {
+		if err.Error() == "on-call schedule not found" {
+			return utils.SendError(c, fiber.StatusNotFound, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendData(c, onCall)
+}
+
+// GetAllOnCall handles "who is on call" for every schedule, now or at the time given by ?at=
+func (ctrl *OnCallController) GetAllOnCall(c *fiber.Ctx) error {
+	at, err := parseAt(c)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid at, must be RFC3339")
+	}
+
+	onCall, err := ctrl.onCallService.GetAllOnCall(at)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendData(c, onCall)
+}
+
+// parseAt parses the optional ?at= query parameter, defaulting to now
+func parseAt(c *fiber.Ctx) (time.Time, error) {
+	atStr := c.Query("at")
+	if atStr == "" {
+		return time.Now(), nil
+	}
+	return time.Parse(time.RFC3339, atStr)
+}
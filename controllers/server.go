@@ -1,8 +1,8 @@
 package controllers
 
 import (
-	"log"
-	"NetGuardServer/repository"
+	"NetGuardServer/dto"
+	"NetGuardServer/models"
 	"NetGuardServer/services"
 	"NetGuardServer/utils"
 
@@ -12,30 +12,18 @@ import (
 
 // ServerController handles server-related HTTP requests
 type ServerController struct {
-	serverService       services.ServerService
-	notificationService services.NotificationService
+	serverService  services.ServerService
+	monitorService services.MonitorService
 }
 
 // NewServerController creates a new server controller
-func NewServerController(serverService services.ServerService, notificationService services.NotificationService) *ServerController {
+func NewServerController(serverService services.ServerService, monitorService services.MonitorService) *ServerController {
 	return &ServerController{
-		serverService:       serverService,
-		notificationService: notificationService,
+		serverService:  serverService,
+		monitorService: monitorService,
 	}
 }
 
-// CreateServerRequest represents the create server request payload
-type CreateServerRequest struct {
-	Name string `json:"name"`
-	URL  string `json:"url"`
-}
-
-// UpdateServerRequest represents the update server request payload
-type UpdateServerRequest struct {
-	Name string `json:"name,omitempty"`
-	URL  string `json:"url,omitempty"`
-}
-
 // CreateServer handles server creation
 func (ctrl *ServerController) CreateServer(c *fiber.Ctx) error {
 	// Get user ID from JWT
@@ -49,18 +37,21 @@ func (ctrl *ServerController) CreateServer(c *fiber.Ctx) error {
 		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
 	}
 
-	var req CreateServerRequest
+	var req dto.CreateServerRequest
 	if err := c.BodyParser(&req); err != nil {
 		return utils.SendError(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	// Validate required fields
-	if req.Name == "" || req.URL == "" {
-		return utils.SendError(c, fiber.StatusBadRequest, "Name and URL are required")
+	// Validate request
+	if err := utils.ValidateStruct(req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Validation failed: "+err.Error())
 	}
 
-	server, err := ctrl.serverService.CreateServer(userID, req.Name, req.URL)
+	server, err := ctrl.serverService.CreateServer(userID, req)
 	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok && appErr.Code == "VALIDATION_ERROR" {
+			return utils.SendError(c, fiber.StatusBadRequest, appErr.Message)
+		}
 		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
 	}
 
@@ -77,6 +68,16 @@ func (ctrl *ServerController) GetServers(c *fiber.Ctx) error {
 	return utils.SendData(c, servers)
 }
 
+// GetDependencyGraph handles getting the dependency graph of all servers
+func (ctrl *ServerController) GetDependencyGraph(c *fiber.Ctx) error {
+	graph, err := ctrl.serverService.GetDependencyGraph()
+	if err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendData(c, graph)
+}
+
 // GetServer handles getting a specific server by ID
 func (ctrl *ServerController) GetServer(c *fiber.Ctx) error {
 	serverIDStr := c.Params("id")
@@ -128,13 +129,21 @@ func (ctrl *ServerController) UpdateServer(c *fiber.Ctx) error {
 		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
 	}
 
-	var req UpdateServerRequest
+	var req dto.UpdateServerRequest
 	if err := c.BodyParser(&req); err != nil {
 		return utils.SendError(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	server, err := ctrl.serverService.UpdateServer(serverID, userID, req.Name, req.URL)
+	// Validate request
+	if err := utils.ValidateStruct(req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Validation failed: "+err.Error())
+	}
+
+	server, err := ctrl.serverService.UpdateServer(serverID, userID, req)
 	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok && appErr.Code == "VALIDATION_ERROR" {
+			return utils.SendError(c, fiber.StatusBadRequest, appErr.Message)
+		}
 		if err.Error() == "server not found" {
 			return utils.SendError(c, fiber.StatusNotFound, err.Error())
 		}
@@ -147,6 +156,42 @@ func (ctrl *ServerController) UpdateServer(c *fiber.Ctx) error {
 	return utils.SendSuccess(c, "Server updated successfully", server)
 }
 
+// RotateHeartbeatToken handles replacing the ping token of a heartbeat server
+func (ctrl *ServerController) RotateHeartbeatToken(c *fiber.Ctx) error {
+	serverIDStr := c.Params("id")
+	serverID, err := uuid.Parse(serverIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid server ID")
+	}
+
+	// Get user ID from JWT for authorization
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	server, err := ctrl.serverService.RotateHeartbeatToken(serverID, userID)
+	if err != nil {
+		if appErr, ok := err.(utils.AppError); ok && appErr.Code == "VALIDATION_ERROR" {
+			return utils.SendError(c, fiber.StatusBadRequest, appErr.Message)
+		}
+		if err.Error() == "server not found" {
+			return utils.SendError(c, fiber.StatusNotFound, err.Error())
+		}
+		if err.Error() == "access denied" {
+			return utils.SendError(c, fiber.StatusForbidden, err.Error())
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "Heartbeat token rotated successfully", server)
+}
+
 // DeleteServer handles server deletion
 func (ctrl *ServerController) DeleteServer(c *fiber.Ctx) error {
 	serverIDStr := c.Params("id")
@@ -199,22 +244,15 @@ func (ctrl *ServerController) UpdateServerStatus(c *fiber.Ctx) error {
 		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
 	}
 
-	var req struct {
-		Status       string `json:"status"`
-		ResponseTime int64  `json:"response_time"`
-	}
+	var req dto.UpdateServerStatusRequest
 
 	if err := c.BodyParser(&req); err != nil {
 		return utils.SendError(c, fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	if req.Status == "" {
-		return utils.SendError(c, fiber.StatusBadRequest, "Status is required")
-	}
-
-	// Validate status
-	if req.Status != "UP" && req.Status != "DOWN" && req.Status != "UNKNOWN" {
-		return utils.SendError(c, fiber.StatusBadRequest, "Invalid status. Must be UP, DOWN, or UNKNOWN")
+	// Validate request
+	if err := utils.ValidateStruct(req); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Validation failed: "+err.Error())
 	}
 
 	// Get server info for history
@@ -223,29 +261,56 @@ func (ctrl *ServerController) UpdateServerStatus(c *fiber.Ctx) error {
 		return utils.SendError(c, fiber.StatusNotFound, "Server not found")
 	}
 
-	// If server is DOWN, create history record and send notifications
-	if req.Status == "DOWN" {
-		// Create history record
-		historyRepo := repository.NewHistoryRepository()
-		historyService := services.NewHistoryService(historyRepo)
+	// Persist the latest status; DOWN reports also create history records and send notifications
+	err = ctrl.monitorService.ReportStatus(server, services.StatusReport{
+		Status:       req.Status,
+		ResponseTime: req.ResponseTime,
+		ReportedBy:   userID,
+		Source:       models.CheckSourceClient,
+		Message:      req.Message,
+	})
+	if err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
 
-		_, err := historyService.CreateHistory(serverID, server.Name, server.URL, "DOWN", userID)
-		if err != nil {
-			// Log error but don't fail the request
-			log.Printf("ERROR: Failed to create history record for server %s: %v", serverID, err)
-		} else {
-			log.Printf("INFO: History record created for server DOWN: %s (%s)", server.Name, server.URL)
-		}
+	return utils.SendSuccess(c, "Server status updated successfully", server)
+}
 
-		// Send FCM notification
-		err = ctrl.notificationService.SendServerDownNotification(serverID, server.Name, server.URL, userID)
-		if err != nil {
-			// Log error but don't fail the request
-			log.Printf("ERROR: Failed to send FCM notification for server %s: %v", serverID, err)
-		} else {
-			log.Printf("INFO: FCM notification sent for server DOWN: %s (%s)", server.Name, server.URL)
-		}
+// CheckServer runs the server's check from the backend right away and returns the result
+func (ctrl *ServerController) CheckServer(c *fiber.Ctx) error {
+	serverIDStr := c.Params("id")
+	serverID, err := uuid.Parse(serverIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid server ID")
 	}
 
-	return utils.SendSuccess(c, "Server status updated successfully", server)
-}
\ No newline at end of file
+	// Get user ID from JWT
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return utils.SendError(c, fiber.StatusUnauthorized, "Unauthorized")
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, "Invalid user ID")
+	}
+
+	server, err := ctrl.serverService.GetServerByID(serverID)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, "Server not found")
+	}
+
+	if server.CheckType == models.CheckTypeHeartbeat {
+		return utils.SendError(c, fiber.StatusBadRequest, "Heartbeat servers cannot be checked, they ping the backend")
+	}
+
+	result, err := ctrl.monitorService.RunCheck(c.Context(), server, userID)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return utils.SendSuccess(c, "Server checked successfully", fiber.Map{
+		"result": result,
+		"server": server,
+	})
+}
@@ -4,9 +4,12 @@
 package di
 
 import (
+	"NetGuardServer/checker"
 	"NetGuardServer/controllers"
+	"NetGuardServer/notifier"
 	"NetGuardServer/repository"
 	"NetGuardServer/services"
+	"NetGuardServer/workers"
 
 	"github.com/google/wire"
 )
@@ -16,6 +19,29 @@ var repositorySet = wire.NewSet(
 	repository.NewUserRepository,
 	repository.NewServerRepository,
 	repository.NewHistoryRepository,
+	repository.NewHistoryEventRepository,
+	repository.NewCheckResultRepository,
+	repository.NewHeartbeatRepository,
+	repository.NewMaintenanceRepository,
+	repository.NewEscalationRepository,
+	repository.NewOnCallRepository,
+	repository.NewDeviceTokenRepository,
+	repository.NewNotificationPreferenceRepository,
+	repository.NewNotificationChannelRepository,
+	repository.NewNotificationOutboxRepository,
+	repository.NewNotificationTemplateRepository,
+	repository.NewNotificationDigestRepository,
+	repository.NewTokenRepository,
+)
+
+// Provider set for server checks
+var checkerSet = wire.NewSet(
+	checker.NewDefaultRunner,
+)
+
+// Provider set for notification channels
+var notifierSet = wire.NewSet(
+	notifier.NewDefaultDispatcher,
 )
 
 // Provider set for services
@@ -24,6 +50,16 @@ var serviceSet = wire.NewSet(
 	services.NewServerService,
 	services.NewHistoryService,
 	services.NewNotificationService,
+	services.NewMonitorService,
+	services.NewMetricsService,
+	services.NewHeartbeatService,
+	services.NewMaintenanceService,
+	services.NewEscalationService,
+	services.NewOnCallService,
+	services.NewDeviceService,
+	services.NewNotificationPreferenceService,
+	services.NewNotificationChannelService,
+	services.NewNotificationTemplateService,
 )
 
 // Provider set for controllers
@@ -31,22 +67,59 @@ var controllerSet = wire.NewSet(
 	controllers.NewAuthController,
 	controllers.NewServerController,
 	controllers.NewHistoryController,
+	controllers.NewMetricsController,
+	controllers.NewHeartbeatController,
+	controllers.NewMaintenanceController,
+	controllers.NewEscalationController,
+	controllers.NewOnCallController,
+	controllers.NewDeviceController,
+	controllers.NewNotificationPreferenceController,
+	controllers.NewNotificationChannelController,
+	controllers.NewNotificationController,
+	controllers.NewNotificationTemplateController,
+)
+
+// Provider set for background workers
+var workerSet = wire.NewSet(
+	workers.NewProbeScheduler,
+	workers.NewMetricsJob,
+	workers.NewHeartbeatMonitor,
+	workers.NewEscalationWorker,
+	workers.NewNotificationWorker,
 )
 
 // App holds all application dependencies
 type App struct {
-	AuthController    *controllers.AuthController
-	ServerController  *controllers.ServerController
-	HistoryController *controllers.HistoryController
+	AuthController                   *controllers.AuthController
+	ServerController                 *controllers.ServerController
+	HistoryController                *controllers.HistoryController
+	MetricsController                *controllers.MetricsController
+	HeartbeatController              *controllers.HeartbeatController
+	MaintenanceController            *controllers.MaintenanceController
+	EscalationController             *controllers.EscalationController
+	OnCallController                 *controllers.OnCallController
+	DeviceController                 *controllers.DeviceController
+	NotificationPreferenceController *controllers.NotificationPreferenceController
+	NotificationChannelController    *controllers.NotificationChannelController
+	NotificationController           *controllers.NotificationController
+	NotificationTemplateController   *controllers.NotificationTemplateController
+	ProbeScheduler                   *workers.ProbeScheduler
+	MetricsJob                       *workers.MetricsJob
+	HeartbeatMonitor                 *workers.HeartbeatMonitor
+	EscalationWorker                 *workers.EscalationWorker
+	NotificationWorker               *workers.NotificationWorker
 }
 
 // InitializeApp initializes the entire application with dependency injection
 func InitializeApp() (*App, error) {
 	wire.Build(
 		repositorySet,
+		checkerSet,
+		notifierSet,
 		serviceSet,
 		controllerSet,
+		workerSet,
 		wire.Struct(new(App), "*"),
 	)
 	return &App{}, nil
-}
\ No newline at end of file
+}
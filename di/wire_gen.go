@@ -7,9 +7,12 @@
 package di
 
 import (
+	"NetGuardServer/checker"
 	"NetGuardServer/controllers"
+	"NetGuardServer/notifier"
 	"NetGuardServer/repository"
 	"NetGuardServer/services"
+	"NetGuardServer/workers"
 	"github.com/google/wire"
 )
 
@@ -18,19 +21,74 @@ import (
 // InitializeApp initializes the entire application with dependency injection
 func InitializeApp() (*App, error) {
 	userRepository := repository.NewUserRepository()
-	authService := services.NewAuthService(userRepository)
+	tokenRepository := repository.NewTokenRepository()
+	authService := services.NewAuthService(userRepository, tokenRepository)
 	authController := controllers.NewAuthController(authService)
 	serverRepository := repository.NewServerRepository()
-	serverService := services.NewServerService(serverRepository)
-	notificationService := services.NewNotificationService()
-	serverController := controllers.NewServerController(serverService, notificationService)
+	escalationRepository := repository.NewEscalationRepository()
+	onCallRepository := repository.NewOnCallRepository()
+	serverService := services.NewServerService(serverRepository, escalationRepository, onCallRepository)
+	checkResultRepository := repository.NewCheckResultRepository()
+	runner := checker.NewDefaultRunner()
 	historyRepository := repository.NewHistoryRepository()
-	historyService := services.NewHistoryService(historyRepository)
+	historyEventRepository := repository.NewHistoryEventRepository()
+	maintenanceRepository := repository.NewMaintenanceRepository()
+	notificationOutboxRepository := repository.NewNotificationOutboxRepository()
+	deviceTokenRepository := repository.NewDeviceTokenRepository()
+	notificationPreferenceRepository := repository.NewNotificationPreferenceRepository()
+	notificationChannelRepository := repository.NewNotificationChannelRepository()
+	notificationDigestRepository := repository.NewNotificationDigestRepository()
+	notificationTemplateRepository := repository.NewNotificationTemplateRepository()
+	notificationTemplateService := services.NewNotificationTemplateService(notificationTemplateRepository)
+	dispatcher := notifier.NewDefaultDispatcher()
+	notificationService := services.NewNotificationService(deviceTokenRepository, notificationPreferenceRepository, userRepository, notificationChannelRepository, notificationOutboxRepository, notificationDigestRepository, historyEventRepository, notificationTemplateService, dispatcher)
+	onCallService := services.NewOnCallService(onCallRepository, userRepository)
+	historyService := services.NewHistoryService(historyRepository, historyEventRepository, maintenanceRepository, userRepository, serverRepository, notificationOutboxRepository, notificationService, onCallService)
+	maintenanceService := services.NewMaintenanceService(maintenanceRepository, serverRepository, userRepository)
+	escalationService := services.NewEscalationService(escalationRepository, historyRepository, userRepository, historyService, notificationService, onCallService)
+	monitorService := services.NewMonitorService(serverRepository, checkResultRepository, runner, historyService, notificationService, maintenanceService, escalationService, onCallService)
+	serverController := controllers.NewServerController(serverService, monitorService)
 	historyController := controllers.NewHistoryController(historyService)
+	metricsService := services.NewMetricsService(checkResultRepository)
+	metricsController := controllers.NewMetricsController(metricsService, serverService)
+	heartbeatRepository := repository.NewHeartbeatRepository()
+	heartbeatService := services.NewHeartbeatService(serverRepository, heartbeatRepository, historyRepository, monitorService, maintenanceService)
+	heartbeatController := controllers.NewHeartbeatController(heartbeatService, serverService)
+	maintenanceController := controllers.NewMaintenanceController(maintenanceService)
+	escalationController := controllers.NewEscalationController(escalationService)
+	onCallController := controllers.NewOnCallController(onCallService)
+	deviceService := services.NewDeviceService(deviceTokenRepository)
+	deviceController := controllers.NewDeviceController(deviceService)
+	notificationPreferenceService := services.NewNotificationPreferenceService(notificationPreferenceRepository, serverRepository)
+	notificationPreferenceController := controllers.NewNotificationPreferenceController(notificationPreferenceService)
+	notificationChannelService := services.NewNotificationChannelService(notificationChannelRepository, serverRepository, userRepository, dispatcher)
+	notificationChannelController := controllers.NewNotificationChannelController(notificationChannelService)
+	notificationController := controllers.NewNotificationController(notificationService)
+	notificationTemplateController := controllers.NewNotificationTemplateController(notificationTemplateService)
+	probeScheduler := workers.NewProbeScheduler(serverRepository, monitorService)
+	metricsJob := workers.NewMetricsJob(checkResultRepository)
+	heartbeatMonitor := workers.NewHeartbeatMonitor(heartbeatService)
+	escalationWorker := workers.NewEscalationWorker(escalationService)
+	notificationWorker := workers.NewNotificationWorker(notificationService)
 	app := &App{
-		AuthController:    authController,
-		ServerController:  serverController,
-		HistoryController: historyController,
+		AuthController:                   authController,
+		ServerController:                 serverController,
+		HistoryController:                historyController,
+		MetricsController:                metricsController,
+		HeartbeatController:              heartbeatController,
+		MaintenanceController:            maintenanceController,
+		EscalationController:             escalationController,
+		OnCallController:                 onCallController,
+		DeviceController:                 deviceController,
+		NotificationPreferenceController: notificationPreferenceController,
+		NotificationChannelController:    notificationChannelController,
+		NotificationController:           notificationController,
+		NotificationTemplateController:   notificationTemplateController,
+		ProbeScheduler:                   probeScheduler,
+		MetricsJob:                       metricsJob,
+		HeartbeatMonitor:                 heartbeatMonitor,
+		EscalationWorker:                 escalationWorker,
+		NotificationWorker:               notificationWorker,
 	}
 	return app, nil
 }
@@ -38,17 +96,41 @@ func InitializeApp() (*App, error) {
 // wire.go:
 
 // Provider set for repositories
-var repositorySet = wire.NewSet(repository.NewUserRepository, repository.NewServerRepository, repository.NewHistoryRepository)
+var repositorySet = wire.NewSet(repository.NewUserRepository, repository.NewServerRepository, repository.NewHistoryRepository, repository.NewHistoryEventRepository, repository.NewCheckResultRepository, repository.NewHeartbeatRepository, repository.NewMaintenanceRepository, repository.NewEscalationRepository, repository.NewOnCallRepository, repository.NewDeviceTokenRepository, repository.NewNotificationPreferenceRepository, repository.NewNotificationChannelRepository, repository.NewNotificationOutboxRepository, repository.NewNotificationTemplateRepository, repository.NewNotificationDigestRepository, repository.NewTokenRepository)
+
+// Provider set for server checks
+var checkerSet = wire.NewSet(checker.NewDefaultRunner)
+
+// Provider set for notification channels
+var notifierSet = wire.NewSet(notifier.NewDefaultDispatcher)
 
 // Provider set for services
-var serviceSet = wire.NewSet(services.NewAuthService, services.NewServerService, services.NewHistoryService, services.NewNotificationService)
+var serviceSet = wire.NewSet(services.NewAuthService, services.NewServerService, services.NewHistoryService, services.NewNotificationService, services.NewMonitorService, services.NewMetricsService, services.NewHeartbeatService, services.NewMaintenanceService, services.NewEscalationService, services.NewOnCallService, services.NewDeviceService, services.NewNotificationPreferenceService, services.NewNotificationChannelService, services.NewNotificationTemplateService)
 
 // Provider set for controllers
-var controllerSet = wire.NewSet(controllers.NewAuthController, controllers.NewServerController, controllers.NewHistoryController)
+var controllerSet = wire.NewSet(controllers.NewAuthController, controllers.NewServerController, controllers.NewHistoryController, controllers.NewMetricsController, controllers.NewHeartbeatController, controllers.NewMaintenanceController, controllers.NewEscalationController, controllers.NewOnCallController, controllers.NewDeviceController, controllers.NewNotificationPreferenceController, controllers.NewNotificationChannelController, controllers.NewNotificationController, controllers.NewNotificationTemplateController)
+
+// Provider set for background workers
+var workerSet = wire.NewSet(workers.NewProbeScheduler, workers.NewMetricsJob, workers.NewHeartbeatMonitor, workers.NewEscalationWorker, workers.NewNotificationWorker)
 
 // App holds all application dependencies
 type App struct {
-	AuthController    *controllers.AuthController
-	ServerController  *controllers.ServerController
-	HistoryController *controllers.HistoryController
+	AuthController                   *controllers.AuthController
+	ServerController                 *controllers.ServerController
+	HistoryController                *controllers.HistoryController
+	MetricsController                *controllers.MetricsController
+	HeartbeatController              *controllers.HeartbeatController
+	MaintenanceController            *controllers.MaintenanceController
+	EscalationController             *controllers.EscalationController
+	OnCallController                 *controllers.OnCallController
+	DeviceController                 *controllers.DeviceController
+	NotificationPreferenceController *controllers.NotificationPreferenceController
+	NotificationChannelController    *controllers.NotificationChannelController
+	NotificationController           *controllers.NotificationController
+	NotificationTemplateController   *controllers.NotificationTemplateController
+	ProbeScheduler                   *workers.ProbeScheduler
+	MetricsJob                       *workers.MetricsJob
+	HeartbeatMonitor                 *workers.HeartbeatMonitor
+	EscalationWorker                 *workers.EscalationWorker
+	NotificationWorker               *workers.NotificationWorker
 }
@@ -20,12 +20,25 @@ type UpdateProfileRequest struct {
 	Name     string `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
 	Division string `json:"division,omitempty"`
 	Phone    string `json:"phone,omitempty"`
+	Locale   string `json:"locale,omitempty" validate:"omitempty,oneof=en id"`
+}
+
+// RefreshTokenRequest represents access token refresh request
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
 }
 
 // AuthResponse represents authentication response
 type AuthResponse struct {
-	Token string    `json:"token"`
-	User  UserDTO   `json:"user"`
+	TokenResponse
+	User UserDTO `json:"user"`
+}
+
+// TokenResponse represents a short-lived access token and the refresh token to renew it
+type TokenResponse struct {
+	Token        string `json:"token"` // access token
+	ExpiresAt    string `json:"expires_at"`
+	RefreshToken string `json:"refresh_token"`
 }
 
 // UserDTO represents user data transfer object
@@ -35,5 +48,6 @@ type UserDTO struct {
 	Email     string `json:"email"`
 	Division  string `json:"division,omitempty"`
 	Phone     string `json:"phone,omitempty"`
+	Locale    string `json:"locale,omitempty"`
 	CreatedAt string `json:"created_at"`
 }
\ No newline at end of file
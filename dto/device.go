@@ -0,0 +1,13 @@
+package dto
+
+// RegisterDeviceRequest represents register FCM device token request
+type RegisterDeviceRequest struct {
+	Token      string `json:"token" validate:"required,max=4096"`
+	Platform   string `json:"platform" validate:"required,oneof=ANDROID IOS WEB"`
+	AppVersion string `json:"app_version,omitempty" validate:"omitempty,max=50"`
+}
+
+// UnregisterDeviceRequest represents unregister FCM device token request
+type UnregisterDeviceRequest struct {
+	Token string `json:"token" validate:"required,max=4096"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+// CreateEscalationPolicyRequest represents create escalation policy request
+type CreateEscalationPolicyRequest struct {
+	Name        string                   `json:"name" validate:"required,min=1,max=255"`
+	Description string                   `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Levels      []EscalationLevelRequest `json:"levels" validate:"required,min=1,max=10,dive"`
+}
+
+// UpdateEscalationPolicyRequest represents update escalation policy request
+type UpdateEscalationPolicyRequest struct {
+	Name        string                   `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Description *string                  `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Levels      []EscalationLevelRequest `json:"levels,omitempty" validate:"omitempty,min=1,max=10,dive"` // replaces all levels when set
+}
+
+// EscalationLevelRequest represents a level of an escalation policy; it needs at least one target
+type EscalationLevelRequest struct {
+	DelayMinutes    int      `json:"delay_minutes" validate:"required,min=1,max=10080"`
+	TargetUserIDs   []string `json:"target_user_ids,omitempty" validate:"omitempty,max=50,dive,uuid"`
+	TargetDivisions []string `json:"target_divisions,omitempty" validate:"omitempty,max=20,dive,required,max=255"`
+	TargetSchedules []string `json:"target_schedule_ids,omitempty" validate:"omitempty,max=20,dive,uuid"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+// CreateMaintenanceWindowRequest represents create maintenance window request.
+// StartsAt and RecurrenceEnd accept RFC3339 or a local time ("2006-01-02T15:04:05") in Timezone.
+type CreateMaintenanceWindowRequest struct {
+	Name            string   `json:"name" validate:"required,min=1,max=255"`
+	Description     string   `json:"description,omitempty" validate:"omitempty,max=1000"`
+	StartsAt        string   `json:"starts_at" validate:"required"`
+	DurationMinutes int      `json:"duration_minutes" validate:"required,min=1,max=43200"`
+	Timezone        string   `json:"timezone,omitempty" validate:"omitempty,timezone"`
+	Recurrence      string   `json:"recurrence,omitempty" validate:"omitempty,oneof=NONE DAILY WEEKLY MONTHLY"`
+	RecurrenceEnd   string   `json:"recurrence_end,omitempty"`
+	ServerIDs       []string `json:"server_ids" validate:"required,min=1,max=500,dive,uuid"`
+}
+
+// UpdateMaintenanceWindowRequest represents update maintenance window request
+type UpdateMaintenanceWindowRequest struct {
+	Name            string   `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Description     *string  `json:"description,omitempty" validate:"omitempty,max=1000"`
+	StartsAt        string   `json:"starts_at,omitempty"`
+	DurationMinutes *int     `json:"duration_minutes,omitempty" validate:"omitempty,min=1,max=43200"`
+	Timezone        string   `json:"timezone,omitempty" validate:"omitempty,timezone"`
+	Recurrence      string   `json:"recurrence,omitempty" validate:"omitempty,oneof=NONE DAILY WEEKLY MONTHLY"`
+	RecurrenceEnd   *string  `json:"recurrence_end,omitempty"`                                          // empty string removes the end
+	ServerIDs       []string `json:"server_ids,omitempty" validate:"omitempty,min=1,max=500,dive,uuid"` // replaces all servers when set
+}
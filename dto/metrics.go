@@ -0,0 +1,24 @@
+package dto
+
+// MetricPointDTO represents a single point of a server metrics series.
+// Raw points carry Status; rollup points carry the aggregated fields.
+type MetricPointDTO struct {
+	Timestamp       string  `json:"timestamp"`
+	Status          string  `json:"status,omitempty"`
+	Count           int64   `json:"count"`
+	UpCount         int64   `json:"up_count"`
+	MinResponseTime float64 `json:"min_response_time"`
+	AvgResponseTime float64 `json:"avg_response_time"`
+	MaxResponseTime float64 `json:"max_response_time"`
+	P95ResponseTime float64 `json:"p95_response_time"`
+	UpRatio         float64 `json:"up_ratio"`
+}
+
+// ServerMetricsDTO represents a server metrics series
+type ServerMetricsDTO struct {
+	ServerID   string           `json:"server_id"`
+	Resolution string           `json:"resolution"`
+	From       string           `json:"from"`
+	To         string           `json:"to"`
+	Points     []MetricPointDTO `json:"points"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+// CreateNotificationChannelRequest represents create notification channel request.
+// WEBHOOK and SLACK channels need URL, EMAIL channels need EmailTo.
+type CreateNotificationChannelRequest struct {
+	Name      string   `json:"name" validate:"required,min=1,max=255"`
+	Type      string   `json:"type" validate:"required,oneof=WEBHOOK EMAIL SLACK"`
+	URL       string   `json:"url,omitempty" validate:"omitempty,url,max=2048"`
+	Secret    string   `json:"secret,omitempty" validate:"omitempty,min=16,max=255"` // generated for webhooks when empty
+	EmailTo   []string `json:"email_to,omitempty" validate:"omitempty,max=20,dive,email"`
+	Division  string   `json:"division,omitempty" validate:"omitempty,max=100"`
+	ServerIDs []string `json:"server_ids,omitempty" validate:"omitempty,max=500,dive,uuid"`
+}
+
+// UpdateNotificationChannelRequest represents update notification channel request
+type UpdateNotificationChannelRequest struct {
+	Name      string   `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	URL       string   `json:"url,omitempty" validate:"omitempty,url,max=2048"`
+	Secret    *string  `json:"secret,omitempty" validate:"omitempty,max=255"` // empty string disables signing
+	EmailTo   []string `json:"email_to,omitempty" validate:"omitempty,max=20,dive,email"`
+	Division  *string  `json:"division,omitempty" validate:"omitempty,max=100"`
+	ServerIDs []string `json:"server_ids,omitempty" validate:"omitempty,max=500,dive,uuid"` // replaces all servers when set
+	Disabled  *bool    `json:"disabled,omitempty"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+// UpdateNotificationPreferenceRequest represents update notification preferences request.
+// Omitted fields are left unchanged; a list replaces the current one ([] clears it).
+type UpdateNotificationPreferenceRequest struct {
+	ServerIDs       []string `json:"server_ids,omitempty" validate:"omitempty,max=200,dive,uuid"`
+	Tags            []string `json:"tags,omitempty" validate:"omitempty,max=50,dive,min=1,max=50"`
+	Divisions       []string `json:"divisions,omitempty" validate:"omitempty,max=50,dive,min=1,max=100"`
+	Events          []string `json:"events,omitempty" validate:"omitempty,dive,oneof=DOWN RECOVERED CERT_EXPIRY FLAPPING ACKNOWLEDGED RESOLVED"` // [] receives all events
+	MinSeverity     string   `json:"min_severity,omitempty" validate:"omitempty,oneof=LOW MEDIUM HIGH CRITICAL"`
+	QuietHoursStart *string  `json:"quiet_hours_start,omitempty"` // "15:04"; empty string disables quiet hours
+	QuietHoursEnd   *string  `json:"quiet_hours_end,omitempty"`
+	Timezone        string   `json:"timezone,omitempty" validate:"omitempty,timezone"`
+}
@@ -0,0 +1,40 @@
+package dto
+
+import "time"
+
+// UpdateNotificationTemplateRequest represents update notification template request.
+// Title and body are Go text/template templates executed against the notification data.
+type UpdateNotificationTemplateRequest struct {
+	Title string `json:"title" validate:"required,max=500"`
+	Body  string `json:"body" validate:"required,max=5000"`
+}
+
+// PreviewNotificationTemplateRequest represents preview notification template request.
+// Without title and body the current template of the event is rendered.
+type PreviewNotificationTemplateRequest struct {
+	Event   string            `json:"event" validate:"required"`
+	Channel string            `json:"channel,omitempty"` // PUSH (default), WEBHOOK, EMAIL, SLACK
+	Locale  string            `json:"locale,omitempty"`  // default locale when empty
+	Title   string            `json:"title,omitempty" validate:"max=500"`
+	Body    string            `json:"body,omitempty" validate:"max=5000"`
+	Data    map[string]string `json:"data,omitempty"` // overrides values of the sample incident
+}
+
+// NotificationTemplateDTO represents the template of an event for a channel and locale
+type NotificationTemplateDTO struct {
+	Event     string     `json:"event"`
+	Channel   string     `json:"channel"`
+	Locale    string     `json:"locale"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Custom    bool       `json:"custom"` // stored in the database instead of built in
+	UpdatedBy string     `json:"updated_by,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// NotificationPreviewDTO represents a template rendered against a sample incident
+type NotificationPreviewDTO struct {
+	Title string            `json:"title"`
+	Body  string            `json:"body"`
+	Data  map[string]string `json:"data"`
+}
@@ -0,0 +1,48 @@
+package dto
+
+// CreateOnCallScheduleRequest represents create on-call schedule request.
+// Layer times accept RFC3339 or a local time ("2006-01-02T15:04:05") in Timezone.
+type CreateOnCallScheduleRequest struct {
+	Name        string               `json:"name" validate:"required,min=1,max=255"`
+	Description string               `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Timezone    string               `json:"timezone,omitempty" validate:"omitempty,timezone"`
+	Layers      []OnCallLayerRequest `json:"layers" validate:"required,min=1,max=10,dive"`
+}
+
+// UpdateOnCallScheduleRequest represents update on-call schedule request
+type UpdateOnCallScheduleRequest struct {
+	Name        string               `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	Description *string              `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Timezone    string               `json:"timezone,omitempty" validate:"omitempty,timezone"`
+	Layers      []OnCallLayerRequest `json:"layers,omitempty" validate:"omitempty,min=1,max=10,dive"` // replaces all layers when set
+}
+
+// OnCallLayerRequest represents a rotation layer; later layers take precedence over earlier ones
+type OnCallLayerRequest struct {
+	Name         string   `json:"name,omitempty" validate:"omitempty,max=255"`
+	StartsAt     string   `json:"starts_at" validate:"required"`
+	EndsAt       string   `json:"ends_at,omitempty"`
+	Rotation     string   `json:"rotation" validate:"required,oneof=DAILY WEEKLY"` // models.OnCallRotationDaily or models.OnCallRotationWeekly
+	UserIDs      []string `json:"user_ids" validate:"required,min=1,max=50,dive,uuid"`
+	RestrictFrom string   `json:"restrict_from,omitempty" validate:"omitempty,datetime=15:04"`
+	RestrictTo   string   `json:"restrict_to,omitempty" validate:"omitempty,datetime=15:04"`
+}
+
+// CreateOnCallOverrideRequest represents create on-call override request.
+// Times accept RFC3339 or a local time in the schedule's time zone.
+type CreateOnCallOverrideRequest struct {
+	UserID   string `json:"user_id" validate:"required,uuid"`
+	StartsAt string `json:"starts_at" validate:"required"`
+	EndsAt   string `json:"ends_at" validate:"required"`
+}
+
+// OnCallDTO represents who is on call for a schedule at a time
+type OnCallDTO struct {
+	ScheduleID   string  `json:"schedule_id"`
+	ScheduleName string  `json:"schedule_name"`
+	At           string  `json:"at"`
+	UserID       *string `json:"user_id"` // null when nobody is on call
+	UserName     string  `json:"user_name,omitempty"`
+	Source       string  `json:"source,omitempty"` // LAYER, OVERRIDE
+	LayerName    string  `json:"layer_name,omitempty"`
+}
@@ -1,31 +1,110 @@
 package dto
 
-// CreateServerRequest represents create server request
+// CreateServerRequest represents create server request.
+// Which of URL, Host and Port are required depends on CheckType (see checker.Validate).
 type CreateServerRequest struct {
-	Name string `json:"name" validate:"required,min=1,max=255"`
-	URL  string `json:"url" validate:"required,url"`
+	Name                 string                 `json:"name" validate:"required,min=1,max=255"`
+	URL                  string                 `json:"url,omitempty" validate:"omitempty,url"`
+	CheckType            string                 `json:"check_type,omitempty" validate:"omitempty,oneof=HTTP TCP DNS TLS HEARTBEAT"`
+	Host                 string                 `json:"host,omitempty" validate:"omitempty,hostname_rfc1123|ip"`
+	Port                 int                    `json:"port,omitempty" validate:"omitempty,min=1,max=65535"`
+	DNSRecordType        string                 `json:"dns_record_type,omitempty" validate:"omitempty,oneof=A AAAA CNAME MX NS TXT"`
+	DNSExpected          string                 `json:"dns_expected,omitempty" validate:"omitempty,max=255"`
+	TimeoutSeconds       int                    `json:"timeout_seconds,omitempty" validate:"omitempty,min=1,max=120"`
+	Assertions           *HTTPAssertionsRequest `json:"assertions,omitempty"`
+	HeartbeatPeriod      int                    `json:"heartbeat_period_seconds,omitempty" validate:"omitempty,min=10,max=2678400"`
+	HeartbeatGrace       int                    `json:"heartbeat_grace_seconds,omitempty" validate:"omitempty,min=0,max=86400"`
+	ConfirmReporters     int                    `json:"confirm_reporters,omitempty" validate:"omitempty,min=1,max=100"`
+	ConfirmWindowSeconds int                    `json:"confirm_window_seconds,omitempty" validate:"omitempty,min=10,max=86400"`
+	FailureThreshold     int                    `json:"failure_threshold,omitempty" validate:"omitempty,min=1,max=20"`
+	SuccessThreshold     int                    `json:"success_threshold,omitempty" validate:"omitempty,min=1,max=20"`
+	DependsOn            []string               `json:"depends_on,omitempty" validate:"omitempty,max=50,dive,uuid"`
+	EscalationPolicyID   string                 `json:"escalation_policy_id,omitempty" validate:"omitempty,uuid"`
+	OnCallScheduleID     string                 `json:"on_call_schedule_id,omitempty" validate:"omitempty,uuid"`
+	Tags                 []string               `json:"tags,omitempty" validate:"omitempty,max=20,dive,min=1,max=50"`
+	Division             string                 `json:"division,omitempty" validate:"omitempty,max=100"`
+	Severity             string                 `json:"severity,omitempty" validate:"omitempty,oneof=LOW MEDIUM HIGH CRITICAL"`
 }
 
 // UpdateServerRequest represents update server request
 type UpdateServerRequest struct {
-	Name string `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
-	URL  string `json:"url,omitempty" validate:"omitempty,url"`
+	Name                 string                 `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+	URL                  string                 `json:"url,omitempty" validate:"omitempty,url"`
+	CheckType            string                 `json:"check_type,omitempty" validate:"omitempty,oneof=HTTP TCP DNS TLS HEARTBEAT"`
+	Host                 string                 `json:"host,omitempty" validate:"omitempty,hostname_rfc1123|ip"`
+	Port                 *int                   `json:"port,omitempty" validate:"omitempty,min=1,max=65535"`
+	DNSRecordType        string                 `json:"dns_record_type,omitempty" validate:"omitempty,oneof=A AAAA CNAME MX NS TXT"`
+	DNSExpected          *string                `json:"dns_expected,omitempty" validate:"omitempty,max=255"`
+	TimeoutSeconds       *int                   `json:"timeout_seconds,omitempty" validate:"omitempty,min=1,max=120"`
+	Assertions           *HTTPAssertionsRequest `json:"assertions,omitempty"` // replaces all assertions when set
+	HeartbeatPeriod      *int                   `json:"heartbeat_period_seconds,omitempty" validate:"omitempty,min=10,max=2678400"`
+	HeartbeatGrace       *int                   `json:"heartbeat_grace_seconds,omitempty" validate:"omitempty,min=0,max=86400"`
+	ConfirmReporters     *int                   `json:"confirm_reporters,omitempty" validate:"omitempty,min=1,max=100"`
+	ConfirmWindowSeconds *int                   `json:"confirm_window_seconds,omitempty" validate:"omitempty,min=10,max=86400"`
+	FailureThreshold     *int                   `json:"failure_threshold,omitempty" validate:"omitempty,min=1,max=20"`
+	SuccessThreshold     *int                   `json:"success_threshold,omitempty" validate:"omitempty,min=1,max=20"`
+	DependsOn            []string               `json:"depends_on,omitempty" validate:"omitempty,max=50,dive,uuid"` // replaces all dependencies when set
+	EscalationPolicyID   *string                `json:"escalation_policy_id,omitempty"`                             // empty string removes the policy
+	OnCallScheduleID     *string                `json:"on_call_schedule_id,omitempty"`                              // empty string removes the schedule
+	Tags                 []string               `json:"tags,omitempty" validate:"omitempty,max=20,dive,min=1,max=50"` // replaces all tags when set
+	Division             *string                `json:"division,omitempty" validate:"omitempty,max=100"`
+	Severity             string                 `json:"severity,omitempty" validate:"omitempty,oneof=LOW MEDIUM HIGH CRITICAL"`
+}
+
+// HTTPAssertionsRequest represents the assertions of an HTTP check
+type HTTPAssertionsRequest struct {
+	StatusCodes       string            `json:"status_codes,omitempty" validate:"omitempty,max=255"`
+	BodyContains      string            `json:"body_contains,omitempty" validate:"omitempty,max=1000"`
+	BodyNotContains   string            `json:"body_not_contains,omitempty" validate:"omitempty,max=1000"`
+	BodyRegex         string            `json:"body_regex,omitempty" validate:"omitempty,max=1000"`
+	JSONPath          string            `json:"json_path,omitempty" validate:"omitempty,startswith=$,max=255"`
+	JSONPathValue     string            `json:"json_path_value,omitempty" validate:"omitempty,max=1000"`
+	Headers           map[string]string `json:"headers,omitempty" validate:"omitempty,max=20,dive,keys,required,max=255,endkeys,max=1000"`
+	MaxResponseTimeMs int64             `json:"max_response_time_ms,omitempty" validate:"omitempty,min=1,max=120000"`
 }
 
 // UpdateServerStatusRequest represents update server status request
 type UpdateServerStatusRequest struct {
 	Status       string `json:"status" validate:"required,oneof=UP DOWN UNKNOWN"`
 	ResponseTime int64  `json:"response_time,omitempty"`
+	Message      string `json:"message,omitempty" validate:"omitempty,max=1000"`
+}
+
+// HeartbeatPingRequest represents the optional body of a heartbeat ping
+type HeartbeatPingRequest struct {
+	ExitCode   *int   `json:"exit_code,omitempty"`
+	DurationMs *int64 `json:"duration_ms,omitempty" validate:"omitempty,min=0"`
+	Log        string `json:"log,omitempty"` // only the tail is kept
+}
+
+// DependencyGraphDTO represents the server dependency graph
+type DependencyGraphDTO struct {
+	Nodes []DependencyNodeDTO `json:"nodes"`
+	Edges []DependencyEdgeDTO `json:"edges"`
+}
+
+// DependencyNodeDTO represents a server in the dependency graph
+type DependencyNodeDTO struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Status string `json:"status"`
+}
+
+// DependencyEdgeDTO represents a server depending on a parent server
+type DependencyEdgeDTO struct {
+	ServerID    string `json:"server_id"`
+	DependsOnID string `json:"depends_on_id"`
 }
 
 // ServerDTO represents server data transfer object
 type ServerDTO struct {
-	ID         string `json:"id"`
-	Name       string `json:"name"`
-	URL        string `json:"url"`
-	Status     string `json:"status,omitempty"`
-	ResponseTime int64 `json:"response_time,omitempty"`
-	LastChecked string `json:"last_checked,omitempty"`
-	CreatedBy  string `json:"created_by"`
-	CreatedAt  string `json:"created_at"`
-}
\ No newline at end of file
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	URL          string `json:"url"`
+	Status       string `json:"status,omitempty"`
+	ResponseTime int64  `json:"response_time,omitempty"`
+	LastChecked  string `json:"last_checked,omitempty"`
+	CreatedBy    string `json:"created_by"`
+	CreatedAt    string `json:"created_at"`
+}
@@ -2,8 +2,12 @@ package main
 
 import (
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"NetGuardServer/config"
+	"NetGuardServer/di"
 	"NetGuardServer/routes"
 
 	"github.com/gofiber/fiber/v2"
@@ -25,6 +29,12 @@ func main() {
 
 	log.Println("✅ Database connected and migrated successfully")
 
+	// Initialize all dependencies using Wire
+	appContainer, err := di.InitializeApp()
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize application: %v", err)
+	}
+
 	app := fiber.New()
 
 	// Middleware
@@ -32,14 +42,40 @@ func main() {
 	app.Use(cors.New())
 
 	// Setup routes
-	routes.SetupRoutes(app)
+	routes.SetupRoutes(app, appContainer)
 
 	// Route sederhana
 	app.Get("/", func(c *fiber.Ctx) error {
 		return c.SendString("🚀 NetGuard Backend is running!")
 	})
 
+	// Jalankan background workers
+	appContainer.ProbeScheduler.Start()
+	appContainer.MetricsJob.Start()
+	appContainer.HeartbeatMonitor.Start()
+	appContainer.EscalationWorker.Start()
+	appContainer.NotificationWorker.Start()
+
 	// Jalankan server
-	log.Printf("✅ Server running at http://localhost:%s", config.AppConfig.Server.Port)
-	log.Fatal(app.Listen(":" + config.AppConfig.Server.Port))
+	go func() {
+		log.Printf("✅ Server running at http://localhost:%s", config.AppConfig.Server.Port)
+		if err := app.Listen(":" + config.AppConfig.Server.Port); err != nil {
+			log.Fatalf("❌ Server stopped: %v", err)
+		}
+	}()
+
+	// Tunggu sinyal shutdown
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+
+	log.Println("🛑 Shutting down...")
+	appContainer.ProbeScheduler.Stop()
+	appContainer.MetricsJob.Stop()
+	appContainer.HeartbeatMonitor.Stop()
+	appContainer.EscalationWorker.Stop()
+	appContainer.NotificationWorker.Stop()
+	if err := app.Shutdown(); err != nil {
+		log.Printf("⚠️  Failed to shut down server cleanly: %v", err)
+	}
 }
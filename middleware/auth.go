@@ -3,6 +3,7 @@ package middleware
 import (
 	"NetGuardServer/repository"
 	"NetGuardServer/utils"
+	"fmt"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
@@ -37,6 +38,26 @@ func JWTMiddleware(c *fiber.Ctx) error {
 		})
 	}
 
+	// Reject revoked tokens, and tokens without ID that cannot be revoked
+	tokenID, err := uuid.Parse(fmt.Sprint(claims["jti"]))
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid or expired token",
+		})
+	}
+	revoked, err := repository.NewTokenRepository().IsRevoked(tokenID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to check token",
+		})
+	}
+	if revoked {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Token has been revoked",
+		})
+	}
+	c.Locals("token_id", tokenID.String())
+
 	// Set user information in context
 	if userID, ok := claims["user_id"].(string); ok {
 		c.Locals("user_id", userID)
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"NetGuardServer/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func TestJWTMiddlewareRejectsTokenWithoutID(t *testing.T) {
+	previous := config.AppConfig.JWT
+	t.Cleanup(func() { config.AppConfig.JWT = previous })
+	config.AppConfig.JWT.Secret = "test-secret"
+
+	sign := func(claims jwt.MapClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+		return token
+	}
+	exp := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "no jti", token: sign(jwt.MapClaims{"user_id": uuid.New().String(), "exp": exp})},
+		{name: "jti that is no token ID", token: sign(jwt.MapClaims{"jti": "legacy", "user_id": uuid.New().String(), "exp": exp})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/", JWTMiddleware, func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			// Rejected before the revocation list is consulted: such a token could never be revoked
+			if resp.StatusCode != fiber.StatusUnauthorized {
+				t.Errorf("status = %d, want %d", resp.StatusCode, fiber.StatusUnauthorized)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Rollup resolutions
+const (
+	ResolutionRaw  = "raw"
+	ResolutionHour = "hour"
+	ResolutionDay  = "day"
+)
+
+// CheckResult is a single status observation of a server (time-series row)
+type CheckResult struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	ServerID     uuid.UUID `gorm:"type:uuid;not null;index:idx_check_results_server_checked_at" json:"server_id"`
+	Status       string    `gorm:"not null" json:"status"` // UP, DOWN, UNKNOWN
+	ResponseTime int64     `json:"response_time"`          // milliseconds
+	Source       string    `json:"source"`                 // CLIENT, PROBE, HEARTBEAT
+	Message      string    `json:"message,omitempty"`      // reason of a DOWN result
+	ReportedBy   uuid.UUID `gorm:"type:uuid" json:"reported_by"`
+	CheckedAt    time.Time `gorm:"not null;index:idx_check_results_server_checked_at;index" json:"checked_at"`
+}
+
+// CheckRollup aggregates check results of a server per hour or per day
+type CheckRollup struct {
+	ServerID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"server_id"`
+	Resolution      string    `gorm:"primaryKey" json:"resolution"` // hour, day
+	BucketStart     time.Time `gorm:"primaryKey" json:"bucket_start"`
+	Count           int64     `json:"count"`
+	UpCount         int64     `json:"up_count"`
+	MinResponseTime float64   `json:"min_response_time"`
+	AvgResponseTime float64   `json:"avg_response_time"`
+	MaxResponseTime float64   `json:"max_response_time"`
+	P95ResponseTime float64   `json:"p95_response_time"`
+	UpRatio         float64   `json:"up_ratio"`
+}
+
+// Hook: auto set UUID & check time
+func (r *CheckResult) BeforeCreate(tx *gorm.DB) (err error) {
+	r.ID = uuid.New()
+	if r.CheckedAt.IsZero() {
+		r.CheckedAt = time.Now()
+	}
+	return
+}
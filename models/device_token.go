@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Device platforms
+const (
+	DevicePlatformAndroid = "ANDROID"
+	DevicePlatformIOS     = "IOS"
+	DevicePlatformWeb     = "WEB"
+)
+
+// DeviceToken is an FCM registration token of a user's device.
+// A token belongs to one user at a time: registering it again moves it to the new user.
+type DeviceToken struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	Token      string    `gorm:"type:text;not null;uniqueIndex" json:"token"`
+	Platform   string    `gorm:"not null" json:"platform"` // ANDROID, IOS, WEB
+	AppVersion string    `json:"app_version,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"` // last registration
+}
+
+// Hook: auto set UUID
+func (t *DeviceToken) BeforeCreate(tx *gorm.DB) (err error) {
+	t.ID = uuid.New()
+	return
+}
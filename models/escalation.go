@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EscalationPolicy re-notifies users, level by level, about an incident nobody acknowledged
+type EscalationPolicy struct {
+	ID          uuid.UUID         `gorm:"type:uuid;primaryKey" json:"id"`
+	Name        string            `gorm:"not null" json:"name"`
+	Description string            `json:"description,omitempty"`
+	Levels      []EscalationLevel `gorm:"foreignKey:PolicyID;constraint:OnDelete:CASCADE" json:"levels"`
+	CreatedBy   uuid.UUID         `gorm:"type:uuid" json:"created_by"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// EscalationLevel is a step of an escalation policy. Its targets are notified DelayMinutes
+// after the previous level (or after the incident was opened, for the first level).
+// Target divisions notify every active user of the division (see User.Division), target
+// schedules the user on call at the time of the escalation.
+type EscalationLevel struct {
+	ID              uuid.UUID   `gorm:"type:uuid;primaryKey" json:"id"`
+	PolicyID        uuid.UUID   `gorm:"type:uuid;not null;index" json:"policy_id"`
+	Level           int         `gorm:"not null" json:"level"` // 1-based order within the policy
+	DelayMinutes    int         `gorm:"not null" json:"delay_minutes"`
+	TargetUserIDs   []uuid.UUID `gorm:"type:text;serializer:json" json:"target_user_ids,omitempty"`
+	TargetDivisions []string    `gorm:"type:text;serializer:json" json:"target_divisions,omitempty"`
+	TargetSchedules []uuid.UUID `gorm:"type:text;serializer:json" json:"target_schedule_ids,omitempty"`
+}
+
+// Hook: auto set UUID & timestamp
+func (p *EscalationPolicy) BeforeCreate(tx *gorm.DB) (err error) {
+	p.ID = uuid.New()
+	p.CreatedAt = time.Now()
+	return
+}
+
+// Hook: auto set UUID
+func (l *EscalationLevel) BeforeCreate(tx *gorm.DB) (err error) {
+	l.ID = uuid.New()
+	return
+}
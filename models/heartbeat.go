@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// HeartbeatPing is a ping received from a heartbeat server (e.g. a cron job).
+// Pings that failed, opened or resolved an incident are linked to it.
+type HeartbeatPing struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	ServerID   uuid.UUID  `gorm:"type:uuid;not null;index:idx_heartbeat_pings_server_received_at" json:"server_id"`
+	HistoryID  *uuid.UUID `gorm:"type:uuid;index" json:"history_id,omitempty"`
+	Status     string     `gorm:"not null" json:"status"` // UP, DOWN (non-zero exit code)
+	ExitCode   *int       `json:"exit_code,omitempty"`    // exit code of the job
+	DurationMs *int64     `json:"duration_ms,omitempty"`  // run time of the job
+	LogTail    string     `gorm:"type:text" json:"log_tail,omitempty"`
+	RemoteAddr string     `json:"remote_addr,omitempty"`
+	ReceivedAt time.Time  `gorm:"not null;index:idx_heartbeat_pings_server_received_at" json:"received_at"`
+}
+
+// Hook: auto set UUID & receive time
+func (p *HeartbeatPing) BeforeCreate(tx *gorm.DB) (err error) {
+	p.ID = uuid.New()
+	if p.ReceivedAt.IsZero() {
+		p.ReceivedAt = time.Now()
+	}
+	return
+}
@@ -7,38 +7,153 @@ import (
 	"gorm.io/gorm"
 )
 
+// History statuses
+const (
+	HistoryStatusDown         = "DOWN"
+	HistoryStatusAcknowledged = "ACKNOWLEDGED" // someone is working on it
+	HistoryStatusResolved     = "RESOLVED"
+	HistoryStatusImpacted     = "IMPACTED" // DOWN while a parent dependency is DOWN, linked to the parent incident
+)
+
 type ServerDownHistory struct {
-	ID            uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
-	ServerID      uuid.UUID `gorm:"type:uuid" json:"server_id"`
-	ServerName    string    `gorm:"not null" json:"server_name"`
-	URL           string    `gorm:"not null" json:"url"`
-	Status        string    `gorm:"not null" json:"status"` // DOWN, RESOLVED
-	Timestamp     time.Time `json:"timestamp"`
-	CreatedBy     uuid.UUID `gorm:"type:uuid" json:"created_by"`
-	Description   string    `json:"description,omitempty"`
-	ResolvedBy    *uuid.UUID `gorm:"type:uuid" json:"resolved_by,omitempty"`
-	ResolvedAt    *time.Time `json:"resolved_at,omitempty"`
-	ResolveNote   string     `json:"resolve_note,omitempty"`
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	ServerID       uuid.UUID  `gorm:"type:uuid" json:"server_id"`
+	ServerName     string     `gorm:"not null" json:"server_name"`
+	URL            string     `gorm:"not null" json:"url"`
+	Status         string     `gorm:"not null" json:"status"`                     // DOWN, IMPACTED, ACKNOWLEDGED, RESOLVED
+	ParentID       *uuid.UUID `gorm:"type:uuid;index" json:"parent_id,omitempty"` // root incident of an impacted record
+	Timestamp      time.Time  `json:"timestamp"`
+	CreatedBy      uuid.UUID  `gorm:"type:uuid" json:"created_by"`
+	Description    string     `json:"description,omitempty"`
+	AcknowledgedBy *uuid.UUID `gorm:"type:uuid" json:"acknowledged_by,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	AckSeconds     *int64     `json:"time_to_acknowledge_seconds,omitempty"`
+	AssigneeID     *uuid.UUID `gorm:"type:uuid;index" json:"assignee_id,omitempty"`
+	AssignedBy     *uuid.UUID `gorm:"type:uuid" json:"assigned_by,omitempty"`
+	AssignedAt     *time.Time `json:"assigned_at,omitempty"`
+
+	// Escalation schedule: EscalationLevel levels of the policy were notified so far,
+	// the next one is due at NextEscalationAt (nil once acknowledged, resolved or exhausted)
+	EscalationPolicyID *uuid.UUID `gorm:"type:uuid" json:"escalation_policy_id,omitempty"`
+	EscalationLevel    int        `gorm:"not null;default:0" json:"escalation_level"`
+	NextEscalationAt   *time.Time `gorm:"index" json:"next_escalation_at,omitempty"`
+
+	ResolvedBy      *uuid.UUID `gorm:"type:uuid" json:"resolved_by,omitempty"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty"`
+	ResolveNote     string     `json:"resolve_note,omitempty"`
+	AutoResolved    bool       `gorm:"default:false" json:"auto_resolved"` // resolved by the system when the server reported UP
+	DowntimeSeconds *int64     `json:"downtime_seconds,omitempty"`
+	ReporterCount   int        `gorm:"not null;default:1" json:"reporter_count"` // distinct users/probes that reported DOWN
+	LastReportedAt  *time.Time `json:"last_reported_at,omitempty"`
+
+	// Time the server spent in maintenance windows while the record was open, recorded when it is resolved
+	MaintenanceSeconds int64 `gorm:"not null;default:0" json:"maintenance_seconds"`
+
+	Reporters []HistoryReporter `gorm:"foreignKey:HistoryID;constraint:OnDelete:CASCADE" json:"reporters,omitempty"`
+}
+
+// HistoryReporter tracks a user (or the system probe) that reported an incident DOWN
+type HistoryReporter struct {
+	HistoryID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"history_id"`
+	UserID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	ReportCount     int       `gorm:"not null;default:1" json:"report_count"`
+	FirstReportedAt time.Time `json:"first_reported_at"`
+	LastReportedAt  time.Time `json:"last_reported_at"`
+}
+
+// HistoryReporterResponse represents an incident reporter with user name instead of ID
+type HistoryReporterResponse struct {
+	Name            string    `json:"name"`
+	ReportCount     int       `json:"report_count"`
+	FirstReportedAt time.Time `json:"first_reported_at"`
+	LastReportedAt  time.Time `json:"last_reported_at"`
 }
 
 // HistoryResponse represents history with user names instead of IDs
 type HistoryResponse struct {
-	ID            uuid.UUID `json:"id"`
-	ServerID      uuid.UUID `json:"server_id"`
-	ServerName    string    `json:"server_name"`
-	URL           string    `json:"url"`
-	Status        string    `json:"status"`
-	Timestamp     time.Time `json:"timestamp"`
-	CreatedBy     string    `json:"created_by"`     // User name instead of UUID
-	ResolvedBy    *string   `json:"resolved_by"`    // User name instead of UUID
-	ResolvedAt    *time.Time `json:"resolved_at,omitempty"`
-	ResolveNote   string    `json:"resolve_note,omitempty"`
-	Description   string    `json:"description,omitempty"`
+	ID              uuid.UUID  `json:"id"`
+	ServerID        uuid.UUID  `json:"server_id"`
+	ServerName      string     `json:"server_name"`
+	URL             string     `json:"url"`
+	Status          string     `json:"status"`
+	ParentID        *uuid.UUID `json:"parent_id,omitempty"`
+	Timestamp       time.Time  `json:"timestamp"`
+	CreatedBy       string     `json:"created_by"`                // User name instead of UUID
+	AcknowledgedBy  *string    `json:"acknowledged_by,omitempty"` // User name instead of UUID
+	AcknowledgedAt  *time.Time `json:"acknowledged_at,omitempty"`
+	AckSeconds      *int64     `json:"time_to_acknowledge_seconds,omitempty"`
+	AssigneeID      *uuid.UUID `json:"assignee_id,omitempty"`
+	Assignee        *string    `json:"assignee,omitempty"` // User name of AssigneeID
+	AssignedAt      *time.Time `json:"assigned_at,omitempty"`
+	EscalationLevel int        `json:"escalation_level"`
+	ResolvedBy      *string    `json:"resolved_by"` // User name instead of UUID
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty"`
+	ResolveNote     string     `json:"resolve_note,omitempty"`
+	Description     string     `json:"description,omitempty"`
+	AutoResolved    bool       `json:"auto_resolved"`
+	DowntimeSeconds *int64     `json:"downtime_seconds,omitempty"`
+	ReporterCount   int        `json:"reporter_count"`
+	LastReportedAt  *time.Time `json:"last_reported_at,omitempty"`
+
+	// Time the server spent in maintenance windows while the record was open, recorded when it is resolved
+	MaintenanceSeconds int64 `gorm:"not null;default:0" json:"maintenance_seconds"`
+
+	Reporters []HistoryReporterResponse `json:"reporters"`
+	Timeline  []HistoryEventResponse    `json:"timeline,omitempty"` // only set when fetching a single record
+}
+
+// Resolve marks the history record as resolved and records the downtime duration
+func (h *ServerDownHistory) Resolve(resolvedBy uuid.UUID, resolvedAt time.Time) {
+	downtime := int64(resolvedAt.Sub(h.Timestamp).Seconds())
+	h.Status = HistoryStatusResolved
+	h.ResolvedBy = &resolvedBy
+	h.ResolvedAt = &resolvedAt
+	h.DowntimeSeconds = &downtime
+	h.NextEscalationAt = nil
+}
+
+// Downtime returns how long the server was down: until the record was resolved,
+// or until now while it is open
+func (h *ServerDownHistory) Downtime(now time.Time) time.Duration {
+	if h.DowntimeSeconds != nil {
+		return time.Duration(*h.DowntimeSeconds) * time.Second
+	}
+	return now.Sub(h.Timestamp)
+}
+
+// Acknowledge marks the history record as acknowledged and records the time to acknowledge
+func (h *ServerDownHistory) Acknowledge(acknowledgedBy uuid.UUID, acknowledgedAt time.Time) {
+	ackSeconds := int64(acknowledgedAt.Sub(h.Timestamp).Seconds())
+	h.Status = HistoryStatusAcknowledged
+	h.AcknowledgedBy = &acknowledgedBy
+	h.AcknowledgedAt = &acknowledgedAt
+	h.AckSeconds = &ackSeconds
+	h.NextEscalationAt = nil
 }
 
 // Hook: auto set UUID & timestamp
 func (h *ServerDownHistory) BeforeCreate(tx *gorm.DB) (err error) {
 	h.ID = uuid.New()
 	h.Timestamp = time.Now()
+	if h.ReporterCount == 0 {
+		h.ReporterCount = 1
+	}
 	return
 }
+
+// MonthlyReportEntry represents the incident statistics of a server in a month.
+// Time spent in maintenance windows is excluded from downtime, and so are records
+// of the server being impacted by a parent incident.
+type MonthlyReportEntry struct {
+	ServerID             uuid.UUID `json:"server_id"`
+	ServerName           string    `json:"server_name"`
+	URL                  string    `json:"url"`
+	DownCount            int       `json:"down_count"`
+	ResolvedCount        int       `json:"resolved_count"`
+	ImpactedCount        int       `json:"impacted_count"`      // records caused by a parent incident, not counted as downs
+	AvgResolutionTime    float64   `json:"avg_resolution_time"` // seconds
+	AcknowledgedCount    int       `json:"acknowledged_count"`
+	AvgAckTime           float64   `json:"avg_time_to_acknowledge"` // seconds (MTTA) over acknowledged incidents
+	TotalDowntimeSeconds int64     `json:"total_downtime_seconds"`
+	MaintenanceSeconds   int64     `json:"maintenance_seconds"` // excluded from downtime
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// History event types
+const (
+	HistoryEventOpened       = "OPENED"       // incident opened by the first DOWN report
+	HistoryEventReported     = "REPORTED"     // further DOWN report attached to the open incident
+	HistoryEventPromoted     = "PROMOTED"     // impacted record became an incident of its own
+	HistoryEventAcknowledged = "ACKNOWLEDGED" // incident acknowledged by a user
+	HistoryEventAssigned     = "ASSIGNED"     // incident assigned to a user
+	HistoryEventResolved     = "RESOLVED"     // incident resolved by a user or the system
+	HistoryEventNotification = "NOTIFICATION" // notification sent about the incident
+	HistoryEventComment      = "COMMENT"      // free-text comment of a user
+	HistoryEventEscalated    = "ESCALATED"    // level of the escalation policy notified
+)
+
+// HistoryEvent is an entry of the append-only timeline of a ServerDownHistory record
+type HistoryEvent struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	HistoryID uuid.UUID `gorm:"type:uuid;not null;index:idx_history_events_history_created_at" json:"history_id"`
+	Type      string    `gorm:"not null" json:"type"`
+	AuthorID  uuid.UUID `gorm:"type:uuid;not null" json:"author_id"` // SystemUserID for events of the backend
+	Message   string    `gorm:"type:text" json:"message,omitempty"`
+	CreatedAt time.Time `gorm:"not null;index:idx_history_events_history_created_at" json:"created_at"`
+}
+
+// HistoryEventResponse represents a timeline event with the author name instead of ID
+type HistoryEventResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Type      string    `json:"type"`
+	Author    string    `json:"author"`
+	Message   string    `json:"message,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Hook: auto set UUID & timestamp
+func (e *HistoryEvent) BeforeCreate(tx *gorm.DB) (err error) {
+	e.ID = uuid.New()
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+	return
+}
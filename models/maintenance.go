@@ -0,0 +1,176 @@
+package models
+
+import (
+	"sort"
+	"time"
+	_ "time/tzdata" // maintenance windows must resolve time zones on hosts without zoneinfo
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Maintenance window recurrences
+const (
+	RecurrenceNone    = "NONE"
+	RecurrenceDaily   = "DAILY"
+	RecurrenceWeekly  = "WEEKLY"
+	RecurrenceMonthly = "MONTHLY"
+)
+
+// MaintenanceWindow is a planned period in which DOWN observations of its servers
+// neither open incidents nor send notifications. Recurring windows repeat at the same
+// wall-clock time in Timezone, so they follow daylight saving changes.
+type MaintenanceWindow struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	Name            string     `gorm:"not null" json:"name"`
+	Description     string     `json:"description,omitempty"`
+	StartsAt        time.Time  `gorm:"not null" json:"starts_at"` // start of the first occurrence
+	DurationMinutes int        `gorm:"not null" json:"duration_minutes"`
+	Timezone        string     `gorm:"not null;default:'UTC'" json:"timezone"`    // IANA name, e.g. Asia/Jakarta
+	Recurrence      string     `gorm:"not null;default:'NONE'" json:"recurrence"` // NONE, DAILY, WEEKLY, MONTHLY
+	RecurrenceEnd   *time.Time `json:"recurrence_end,omitempty"`                  // no occurrence starts after it
+	Active          bool       `gorm:"-" json:"active"`                           // in progress at the time of the request
+	Servers         []Server   `gorm:"many2many:maintenance_window_servers;constraint:OnDelete:CASCADE" json:"servers"`
+	CreatedBy       uuid.UUID  `gorm:"type:uuid" json:"created_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// TimeRange is a half-open time interval [Start, End)
+type TimeRange struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Overlap returns how long the range overlaps [start, end)
+func (r TimeRange) Overlap(start, end time.Time) time.Duration {
+	if r.Start.After(start) {
+		start = r.Start
+	}
+	if r.End.Before(end) {
+		end = r.End
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// MergeTimeRanges merges overlapping ranges, so time covered twice is only counted once
+func MergeTimeRanges(ranges []TimeRange) []TimeRange {
+	sorted := append([]TimeRange(nil), ranges...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	var merged []TimeRange
+	for _, r := range sorted {
+		if last := len(merged) - 1; last >= 0 && !r.Start.After(merged[last].End) {
+			if r.End.After(merged[last].End) {
+				merged[last].End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// MaintenanceTime returns how long [from, to) overlaps occurrences of the windows,
+// time covered by several windows counted once
+func MaintenanceTime(windows []MaintenanceWindow, from, to time.Time) time.Duration {
+	var periods []TimeRange
+	for i := range windows {
+		periods = append(periods, windows[i].Periods(from, to)...)
+	}
+	var total time.Duration
+	for _, period := range MergeTimeRanges(periods) {
+		total += period.Overlap(from, to)
+	}
+	return total
+}
+
+// Location returns the window's time zone, falling back to UTC
+func (w *MaintenanceWindow) Location() *time.Location {
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Periods returns the occurrences of the window that overlap [from, to)
+func (w *MaintenanceWindow) Periods(from, to time.Time) []TimeRange {
+	duration := time.Duration(w.DurationMinutes) * time.Minute
+	start := w.StartsAt.In(w.Location())
+
+	var periods []TimeRange
+	for k := w.firstCandidate(start, from, duration); ; k++ {
+		occurrence := w.occurrence(start, k)
+		if !occurrence.Before(to) || (w.RecurrenceEnd != nil && occurrence.After(*w.RecurrenceEnd)) {
+			break
+		}
+		if end := occurrence.Add(duration); end.After(from) {
+			periods = append(periods, TimeRange{Start: occurrence, End: end})
+		}
+		if w.Recurrence == RecurrenceNone || w.Recurrence == "" {
+			break
+		}
+	}
+	return periods
+}
+
+// ActiveAt reports whether an occurrence of the window is in progress at t
+func (w *MaintenanceWindow) ActiveAt(t time.Time) bool {
+	return len(w.Periods(t, t.Add(time.Nanosecond))) > 0
+}
+
+// occurrence returns the start of the k-th occurrence (k = 0 is the first one), at the
+// same wall clock time in the window's time zone
+func (w *MaintenanceWindow) occurrence(start time.Time, k int) time.Time {
+	switch w.Recurrence {
+	case RecurrenceDaily:
+		return start.AddDate(0, 0, k)
+	case RecurrenceWeekly:
+		return start.AddDate(0, 0, 7*k)
+	case RecurrenceMonthly:
+		// On the same day of the month, or its last day in shorter months (Jan 31 → Feb 28)
+		month := time.Date(start.Year(), start.Month()+time.Month(k), 1, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+		lastDay := month.AddDate(0, 1, -1).Day()
+		return month.AddDate(0, 0, min(start.Day(), lastDay)-1)
+	}
+	return start
+}
+
+// firstCandidate estimates the first occurrence that can end after from,
+// staying one period early so daylight saving shifts are covered
+func (w *MaintenanceWindow) firstCandidate(start, from time.Time, duration time.Duration) int {
+	elapsed := from.Sub(start) - duration
+	if elapsed <= 0 {
+		return 0
+	}
+
+	var k int
+	switch w.Recurrence {
+	case RecurrenceDaily:
+		k = int(elapsed / (24 * time.Hour))
+	case RecurrenceWeekly:
+		k = int(elapsed / (7 * 24 * time.Hour))
+	case RecurrenceMonthly:
+		k = int(elapsed / (31 * 24 * time.Hour))
+	}
+	if k > 0 {
+		k--
+	}
+	return k
+}
+
+// Auto generate UUID & timestamp
+func (w *MaintenanceWindow) BeforeCreate(tx *gorm.DB) (err error) {
+	w.ID = uuid.New()
+	w.CreatedAt = time.Now()
+	if w.Timezone == "" {
+		w.Timezone = "UTC"
+	}
+	if w.Recurrence == "" {
+		w.Recurrence = RecurrenceNone
+	}
+	return
+}
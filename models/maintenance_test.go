@@ -0,0 +1,119 @@
+package models
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceWindowPeriods(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	utc := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	recurrenceEnd := utc(3, 3, 0)
+
+	tests := []struct {
+		name   string
+		window MaintenanceWindow
+		from   time.Time
+		to     time.Time
+		want   []time.Time // starts of the periods, each lasting the window's duration
+	}{
+		{
+			name:   "one-off window",
+			window: MaintenanceWindow{StartsAt: utc(3, 10, 22), DurationMinutes: 60, Timezone: "UTC", Recurrence: RecurrenceNone},
+			from:   utc(3, 1, 0),
+			to:     utc(4, 1, 0),
+			want:   []time.Time{utc(3, 10, 22)},
+		},
+		{
+			// Berlin switches to summer time on March 29, 09:00 local is 08:00 UTC before and 07:00 UTC after
+			name:   "daily window keeps its local time across daylight saving",
+			window: MaintenanceWindow{StartsAt: time.Date(2026, 3, 27, 9, 0, 0, 0, berlin), DurationMinutes: 30, Timezone: "Europe/Berlin", Recurrence: RecurrenceDaily},
+			from:   utc(3, 28, 0),
+			to:     utc(3, 31, 0),
+			want:   []time.Time{utc(3, 28, 8), utc(3, 29, 7), utc(3, 30, 7)},
+		},
+		{
+			name:   "weekly window",
+			window: MaintenanceWindow{StartsAt: utc(3, 1, 22), DurationMinutes: 60, Timezone: "UTC", Recurrence: RecurrenceWeekly},
+			from:   utc(3, 10, 0),
+			to:     utc(3, 23, 0),
+			want:   []time.Time{utc(3, 15, 22), utc(3, 22, 22)},
+		},
+		{
+			name:   "monthly window on the 31st falls on the last day of shorter months",
+			window: MaintenanceWindow{StartsAt: utc(1, 31, 22), DurationMinutes: 60, Timezone: "UTC", Recurrence: RecurrenceMonthly},
+			from:   utc(2, 1, 0),
+			to:     utc(5, 1, 0),
+			want:   []time.Time{utc(2, 28, 22), utc(3, 31, 22), utc(4, 30, 22)},
+		},
+		{
+			name:   "no occurrence starts after the recurrence end",
+			window: MaintenanceWindow{StartsAt: utc(3, 1, 22), DurationMinutes: 60, Timezone: "UTC", Recurrence: RecurrenceDaily, RecurrenceEnd: &recurrenceEnd},
+			from:   utc(3, 1, 0),
+			to:     utc(3, 10, 0),
+			want:   []time.Time{utc(3, 1, 22), utc(3, 2, 22)},
+		},
+		{
+			name:   "period already in progress at from",
+			window: MaintenanceWindow{StartsAt: utc(3, 1, 22), DurationMinutes: 120, Timezone: "UTC", Recurrence: RecurrenceDaily},
+			from:   utc(3, 5, 23),
+			to:     utc(3, 6, 0),
+			want:   []time.Time{utc(3, 5, 22)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			duration := time.Duration(tt.window.DurationMinutes) * time.Minute
+			var starts []time.Time
+			for _, period := range tt.window.Periods(tt.from, tt.to) {
+				if period.End.Sub(period.Start) != duration {
+					t.Errorf("period %v - %v, want it to last %v", period.Start, period.End, duration)
+				}
+				starts = append(starts, period.Start.UTC())
+			}
+			if !slices.EqualFunc(starts, tt.want, time.Time.Equal) {
+				t.Errorf("periods start at %v, want %v", starts, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindowActiveAt(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	recurrenceEnd := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	daily := MaintenanceWindow{StartsAt: time.Date(2026, 3, 27, 9, 0, 0, 0, berlin), DurationMinutes: 30, Timezone: "Europe/Berlin", Recurrence: RecurrenceDaily, RecurrenceEnd: &recurrenceEnd}
+	monthly := MaintenanceWindow{StartsAt: time.Date(2026, 1, 31, 22, 0, 0, 0, time.UTC), DurationMinutes: 60, Timezone: "UTC", Recurrence: RecurrenceMonthly}
+
+	tests := []struct {
+		name   string
+		window MaintenanceWindow
+		at     time.Time
+		want   bool
+	}{
+		{name: "before daylight saving", window: daily, at: time.Date(2026, 3, 28, 8, 15, 0, 0, time.UTC), want: true},
+		{name: "old UTC time after daylight saving", window: daily, at: time.Date(2026, 3, 30, 8, 15, 0, 0, time.UTC), want: false},
+		{name: "new UTC time after daylight saving", window: daily, at: time.Date(2026, 3, 30, 7, 15, 0, 0, time.UTC), want: true},
+		{name: "end of a period", window: daily, at: time.Date(2026, 3, 30, 7, 30, 0, 0, time.UTC), want: false},
+		{name: "after the recurrence end", window: daily, at: time.Date(2026, 4, 2, 7, 15, 0, 0, time.UTC), want: false},
+		{name: "last day of February", window: monthly, at: time.Date(2026, 2, 28, 22, 30, 0, 0, time.UTC), want: true},
+		{name: "not the overflow into March", window: monthly, at: time.Date(2026, 3, 3, 22, 30, 0, 0, time.UTC), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.ActiveAt(tt.at); got != tt.want {
+				t.Errorf("ActiveAt(%v) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
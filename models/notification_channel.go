@@ -0,0 +1,85 @@
+package models
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Notification channel types
+const (
+	ChannelTypeWebhook = "WEBHOOK" // signed JSON POST to URL
+	ChannelTypeEmail   = "EMAIL"   // email to EmailTo via the configured SMTP server
+	ChannelTypeSlack   = "SLACK"   // Slack or Mattermost incoming webhook URL
+)
+
+// NotificationChannel delivers server alerts outside FCM. A channel receives the alerts
+// of its servers and of the servers of its division (team); one with neither receives all.
+// Notification preferences of users do not apply to channels.
+type NotificationChannel struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Name      string    `gorm:"not null" json:"name"`
+	Type      string    `gorm:"not null" json:"type"` // WEBHOOK, EMAIL, SLACK
+	URL       string    `json:"url,omitempty"`        // redacted in JSON, Slack webhook URLs are credentials
+	Secret    string    `json:"-"`                    // WEBHOOK: HMAC-SHA256 signing key
+	EmailTo   []string  `gorm:"type:text;serializer:json" json:"email_to,omitempty"`
+	Division  string    `json:"division,omitempty"`
+	Servers   []Server  `gorm:"many2many:notification_channel_servers;constraint:OnDelete:CASCADE" json:"servers"`
+	Disabled  bool      `gorm:"not null;default:false" json:"disabled"`
+	CreatedBy uuid.UUID `gorm:"type:uuid" json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// The secret is only returned in the response of the request that set it
+	IssuedSecret string `gorm:"-" json:"secret,omitempty"`
+}
+
+// MarshalJSON encodes the channel with its URL redacted
+func (c NotificationChannel) MarshalJSON() ([]byte, error) {
+	type channel NotificationChannel
+	redacted := channel(c)
+	redacted.URL = RedactURL(c.URL)
+	return json.Marshal(redacted)
+}
+
+// RedactURL keeps only the scheme and host of a URL, e.g. "https://hooks.slack.com/***"
+func RedactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "***"
+	}
+	redacted := u.Scheme + "://" + u.Host
+	if (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+		redacted += "/***"
+	}
+	return redacted
+}
+
+// Receives reports whether the channel receives an alert
+func (c *NotificationChannel) Receives(alert Alert) bool {
+	if c.Disabled {
+		return false
+	}
+	if len(c.Servers) == 0 && c.Division == "" {
+		return true
+	}
+	for _, server := range c.Servers {
+		if server.ID == alert.ServerID {
+			return true
+		}
+	}
+	return c.Division != "" && strings.EqualFold(c.Division, alert.Division)
+}
+
+// Hook: auto set UUID & timestamp
+func (c *NotificationChannel) BeforeCreate(tx *gorm.DB) (err error) {
+	c.ID = uuid.New()
+	c.CreatedAt = time.Now()
+	return
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNotificationChannelJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel NotificationChannel
+		wantURL string
+	}{
+		{
+			name:    "Slack webhook URL is redacted",
+			channel: NotificationChannel{Type: ChannelTypeSlack, URL: "https://hooks.slack.com/services/T000/B000/XXXX"},
+			wantURL: "https://hooks.slack.com/***",
+		},
+		{
+			name:    "webhook secret is hidden",
+			channel: NotificationChannel{Type: ChannelTypeWebhook, URL: "https://hooks.company.com/netguard?token=abc", Secret: "signing-secret-0123"},
+			wantURL: "https://hooks.company.com/***",
+		},
+		{
+			name:    "URL without path",
+			channel: NotificationChannel{Type: ChannelTypeWebhook, URL: "https://hooks.company.com"},
+			wantURL: "https://hooks.company.com",
+		},
+		{
+			name:    "email channel has no URL",
+			channel: NotificationChannel{Type: ChannelTypeEmail, EmailTo: []string{"ops@company.com"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Channels are encoded as values and through pointers
+			for _, v := range []any{tt.channel, &tt.channel} {
+				encoded, err := json.Marshal(v)
+				if err != nil {
+					t.Fatalf("failed to encode: %v", err)
+				}
+				var decoded map[string]any
+				json.Unmarshal(encoded, &decoded)
+
+				url, _ := decoded["url"].(string)
+				if url != tt.wantURL {
+					t.Errorf("url = %q, want %q", url, tt.wantURL)
+				}
+				if _, ok := decoded["secret"]; ok || strings.Contains(string(encoded), "signing-secret") {
+					t.Errorf("JSON %s contains the secret", encoded)
+				}
+			}
+		})
+	}
+
+	issued := NotificationChannel{Secret: "signing-secret-0123", IssuedSecret: "signing-secret-0123"}
+	encoded, _ := json.Marshal(issued)
+	if !strings.Contains(string(encoded), `"secret":"signing-secret-0123"`) {
+		t.Errorf("JSON %s does not contain the issued secret", encoded)
+	}
+}
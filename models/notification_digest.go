@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NotificationEventDigest is the event of a notification summarizing the server alerts
+// a user or notification channel missed while it was rate limited
+const NotificationEventDigest = "DIGEST"
+
+// NotificationRateCounter counts the server alerts delivered to a user or notification channel
+// in the current fixed rate limit window
+type NotificationRateCounter struct {
+	Key         string    `gorm:"primaryKey" json:"key"` // user:<id> or channel:<id>
+	WindowStart time.Time `gorm:"not null" json:"window_start"`
+	Count       int       `gorm:"not null" json:"count"`
+}
+
+// NotificationDigestItem is a server alert held back from a rate limited user or notification
+// channel. The items of a recipient are sent together in one digest notification.
+type NotificationDigestItem struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	RecipientKey string     `gorm:"not null;index" json:"recipient_key"` // user:<id> or channel:<id>
+	UserID       *uuid.UUID `gorm:"type:uuid" json:"user_id,omitempty"`
+	ChannelID    *uuid.UUID `gorm:"type:uuid" json:"channel_id,omitempty"`
+	OutboxID     uuid.UUID  `gorm:"type:uuid;not null" json:"outbox_id"` // notification the alert was held back from
+	Event        string     `gorm:"not null" json:"event"`
+	ServerID     *uuid.UUID `gorm:"type:uuid" json:"server_id,omitempty"`
+	ServerName   string     `json:"server_name"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// Hook: auto set UUID
+func (i *NotificationDigestItem) BeforeCreate(tx *gorm.DB) (err error) {
+	i.ID = uuid.New()
+	return
+}
+
+// UserRateKey returns the rate limit and digest key of a user
+func UserRateKey(userID uuid.UUID) string {
+	return "user:" + userID.String()
+}
+
+// ChannelRateKey returns the rate limit and digest key of a notification channel
+func ChannelRateKey(channelID uuid.UUID) string {
+	return "channel:" + channelID.String()
+}
+
+// Digest summarizes digest items: the number of items per event, and the names of the
+// servers they are about in order of first appearance
+type Digest struct {
+	Total   int
+	Counts  map[string]int
+	Servers []string
+}
+
+// NewDigest summarizes the digest items of a recipient
+func NewDigest(items []NotificationDigestItem) Digest {
+	digest := Digest{Total: len(items), Counts: make(map[string]int)}
+	seen := make(map[string]bool)
+	for _, item := range items {
+		digest.Counts[item.Event]++
+		if item.ServerName != "" && !seen[item.ServerName] {
+			seen[item.ServerName] = true
+			digest.Servers = append(digest.Servers, item.ServerName)
+		}
+	}
+	return digest
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Notification outbox delivery targets
+const (
+	OutboxTargetPush    = "PUSH"    // FCM push notification to UserIDs
+	OutboxTargetChannel = "CHANNEL" // notification channel ChannelID
+	OutboxTargetTopic   = "TOPIC"   // FCM push notification to the serverdown topic, for apps without registered device
+)
+
+// Notification outbox statuses
+const (
+	OutboxStatusPending = "PENDING" // waiting for its (next) delivery attempt
+	OutboxStatusSent    = "SENT"    // delivered
+	OutboxStatusDead    = "DEAD"    // gave up after the maximum number of attempts
+
+	OutboxStatusDigested = "DIGESTED" // held back by a rate limit, sent in a digest instead
+)
+
+// NotificationOutbox is a notification waiting to be delivered to a single target.
+// Notifications are stored first and delivered by the outbox worker, so a failed
+// delivery is retried instead of lost. A retried push notification is only sent
+// to the devices it failed to reach.
+type NotificationOutbox struct {
+	ID        uuid.UUID         `gorm:"type:uuid;primaryKey" json:"id"`
+	HistoryID *uuid.UUID        `gorm:"type:uuid;index" json:"history_id,omitempty"` // incident the notification is about
+	ServerID  *uuid.UUID        `gorm:"type:uuid" json:"server_id,omitempty"`
+	Event     string            `gorm:"not null" json:"event"`
+	Target    string            `gorm:"not null" json:"target"` // PUSH, CHANNEL, TOPIC
+	ChannelID *uuid.UUID        `gorm:"type:uuid;index" json:"channel_id,omitempty"`
+	UserIDs   []uuid.UUID       `gorm:"type:text;serializer:json" json:"user_ids,omitempty"`
+	Recipient string            `json:"recipient"` // human readable target, e.g. "the on-call user"
+	Title     string            `gorm:"not null" json:"title"`
+	Body      string            `gorm:"type:text" json:"body"`
+	Data      map[string]string `gorm:"type:text;serializer:json" json:"data"`
+	Pending   []string          `gorm:"type:text;serializer:json" json:"-"` // device tokens left after a partial failure
+
+	Status        string     `gorm:"not null;default:'PENDING';index:idx_notification_outboxes_due" json:"status"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"not null;index:idx_notification_outboxes_due" json:"next_attempt_at"`
+	LastError     string     `gorm:"type:text" json:"last_error,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+
+	Log []NotificationAttempt `gorm:"foreignKey:OutboxID;constraint:OnDelete:CASCADE" json:"log,omitempty"`
+}
+
+// NotificationAttempt is the outcome of one delivery attempt of an outbox notification
+type NotificationAttempt struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	OutboxID   uuid.UUID `gorm:"type:uuid;not null;index" json:"outbox_id"`
+	Attempt    int       `gorm:"not null" json:"attempt"`
+	Success    bool      `gorm:"not null" json:"success"`
+	Error      string    `gorm:"type:text" json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Describe describes the notification for the incident timeline
+func (n *NotificationOutbox) Describe() string {
+	return fmt.Sprintf("%s notification to %s", n.Event, n.Recipient)
+}
+
+// RateLimited tells whether rate limits apply to the notification: server alerts on their
+// first delivery attempt. Notifications asking a user to act, digests and retries are never held back.
+func (n *NotificationOutbox) RateLimited() bool {
+	return n.ServerID != nil && n.Event != NotificationEventDigest && n.Attempts == 0
+}
+
+// RecordAttempt applies the outcome of a delivery attempt: the notification is sent,
+// scheduled for a retry after an exponential backoff, or dead after maxAttempts.
+// It returns the attempt to add to the delivery log.
+func (n *NotificationOutbox) RecordAttempt(deliveryErr error, started, now time.Time, maxAttempts int, base, maxDelay time.Duration) NotificationAttempt {
+	n.Attempts++
+	attempt := NotificationAttempt{
+		OutboxID:   n.ID,
+		Attempt:    n.Attempts,
+		Success:    deliveryErr == nil,
+		DurationMs: now.Sub(started).Milliseconds(),
+		CreatedAt:  now,
+	}
+
+	switch {
+	case deliveryErr == nil:
+		n.Status = OutboxStatusSent
+		n.SentAt = &now
+		n.LastError = ""
+	case n.Attempts >= maxAttempts:
+		attempt.Error = deliveryErr.Error()
+		n.Status = OutboxStatusDead
+		n.LastError = attempt.Error
+	default:
+		attempt.Error = deliveryErr.Error()
+		n.Status = OutboxStatusPending
+		n.LastError = attempt.Error
+		n.NextAttemptAt = now.Add(RetryDelay(n.Attempts, base, maxDelay))
+	}
+	return attempt
+}
+
+// RetryDelay returns the backoff after the given number of failed attempts:
+// base, doubled after every further attempt, capped at maxDelay
+func RetryDelay(attempts int, base, maxDelay time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
+
+// Hook: auto set UUID, timestamp and first delivery attempt
+func (n *NotificationOutbox) BeforeCreate(tx *gorm.DB) (err error) {
+	n.ID = uuid.New()
+	n.CreatedAt = time.Now()
+	if n.Status == "" {
+		n.Status = OutboxStatusPending
+	}
+	if n.NextAttemptAt.IsZero() {
+		n.NextAttemptAt = n.CreatedAt
+	}
+	return
+}
+
+// Hook: auto set UUID & timestamp
+func (a *NotificationAttempt) BeforeCreate(tx *gorm.DB) (err error) {
+	a.ID = uuid.New()
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now()
+	}
+	return
+}
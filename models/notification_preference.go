@@ -0,0 +1,166 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification events users can choose from
+const (
+	NotificationEventDown       = "DOWN"        // incident opened
+	NotificationEventRecovered  = "RECOVERED"   // server back UP
+	NotificationEventCertExpiry = "CERT_EXPIRY" // TLS certificate about to expire
+	NotificationEventFlapping   = "FLAPPING"    // server started or stopped flapping
+
+	NotificationEventAcknowledged = "ACKNOWLEDGED" // a user took ownership of an incident
+	NotificationEventResolved     = "RESOLVED"     // a user resolved an incident
+)
+
+// Notification events about incidents that ask a user to act. They are always
+// delivered and cannot be chosen in notification preferences.
+const (
+	NotificationEventAssigned  = "ASSIGNED"  // incident assigned to the user
+	NotificationEventEscalated = "ESCALATED" // unacknowledged incident escalated to the user
+)
+
+// NotificationPreference holds which server alerts a user wants. Users without
+// preferences get every alert, as do users with default preferences.
+type NotificationPreference struct {
+	UserID uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+
+	// Subscriptions: when any is set, only alerts of matching servers are received
+	ServerIDs []uuid.UUID `gorm:"type:text;serializer:json" json:"server_ids"`
+	Tags      []string    `gorm:"type:text;serializer:json" json:"tags"`
+	Divisions []string    `gorm:"type:text;serializer:json" json:"divisions"`
+
+	Events      []string `gorm:"type:text;serializer:json" json:"events"` // empty receives all events
+	MinSeverity string   `gorm:"not null;default:'LOW'" json:"min_severity"`
+
+	// Quiet hours ("15:04", may wrap past midnight) in Timezone; empty disables them
+	QuietHoursStart string `json:"quiet_hours_start,omitempty"`
+	QuietHoursEnd   string `json:"quiet_hours_end,omitempty"`
+	Timezone        string `gorm:"not null;default:'UTC'" json:"timezone"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Alert describes a server notification, to be matched against preferences
+type Alert struct {
+	Event    string
+	Severity string
+	ServerID uuid.UUID
+	Tags     []string
+	Division string
+	Personal bool      // addressed to the user (e.g. as on-call user) rather than to all users
+	Actor    uuid.UUID // user whose action the alert is about, who is not notified; uuid.Nil for none
+}
+
+// NewAlert describes an alert about a server
+func NewAlert(event string, server *Server) Alert {
+	return Alert{
+		Event:    event,
+		Severity: server.Severity,
+		ServerID: server.ID,
+		Tags:     server.Tags,
+		Division: server.Division,
+	}
+}
+
+// WantedBy returns the users who want the alert at the given time, leaving out its actor.
+// Users without preferences get the defaults, which want every alert.
+func (a Alert) WantedBy(userIDs []uuid.UUID, preferences []NotificationPreference, at time.Time) []uuid.UUID {
+	byUser := make(map[uuid.UUID]NotificationPreference, len(preferences))
+	for _, preference := range preferences {
+		byUser[preference.UserID] = preference
+	}
+
+	var wanted []uuid.UUID
+	for _, userID := range userIDs {
+		if a.Actor != uuid.Nil && userID == a.Actor {
+			continue
+		}
+		preference, ok := byUser[userID]
+		if !ok {
+			preference = DefaultNotificationPreference(userID)
+		}
+		if preference.Wants(a, at) {
+			wanted = append(wanted, userID)
+		}
+	}
+	return wanted
+}
+
+// DefaultNotificationPreference returns the preferences of a user who has not set any
+func DefaultNotificationPreference(userID uuid.UUID) NotificationPreference {
+	return NotificationPreference{
+		UserID:      userID,
+		ServerIDs:   []uuid.UUID{},
+		Tags:        []string{},
+		Divisions:   []string{},
+		Events:      []string{},
+		MinSeverity: SeverityLow,
+		Timezone:    "UTC",
+	}
+}
+
+// Location returns the time zone of the preferences, UTC if unknown
+func (p *NotificationPreference) Location() *time.Location {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Wants reports whether the user wants an alert sent at the given time. Personal alerts,
+// e.g. the page of the on-call user, are always wanted. For alerts to all users the event
+// must be chosen, the severity at least MinSeverity, the time outside quiet hours, and the
+// server must match a subscription if the user has any.
+func (p *NotificationPreference) Wants(alert Alert, at time.Time) bool {
+	if alert.Personal {
+		return true
+	}
+	if len(p.Events) > 0 && !slices.Contains(p.Events, alert.Event) {
+		return false
+	}
+	if SeverityRank(alert.Severity) < SeverityRank(p.MinSeverity) {
+		return false
+	}
+	if p.InQuietHours(at) {
+		return false
+	}
+	return !p.HasSubscriptions() || p.Subscribed(alert)
+}
+
+// InQuietHours reports whether t falls within the user's quiet hours
+func (p *NotificationPreference) InQuietHours(t time.Time) bool {
+	if p.QuietHoursStart == "" || p.QuietHoursEnd == "" || p.QuietHoursStart == p.QuietHoursEnd {
+		return false
+	}
+	within, _ := withinDailyWindow(t.In(p.Location()), p.QuietHoursStart, p.QuietHoursEnd)
+	return within
+}
+
+// HasSubscriptions reports whether the user subscribed to specific servers, tags or divisions
+func (p *NotificationPreference) HasSubscriptions() bool {
+	return len(p.ServerIDs) > 0 || len(p.Tags) > 0 || len(p.Divisions) > 0
+}
+
+// Subscribed reports whether the alert's server matches one of the user's subscriptions
+func (p *NotificationPreference) Subscribed(alert Alert) bool {
+	if slices.Contains(p.ServerIDs, alert.ServerID) {
+		return true
+	}
+	for _, tag := range alert.Tags {
+		if slices.ContainsFunc(p.Tags, func(t string) bool { return strings.EqualFold(t, tag) }) {
+			return true
+		}
+	}
+	if alert.Division != "" {
+		return slices.ContainsFunc(p.Divisions, func(d string) bool { return strings.EqualFold(d, alert.Division) })
+	}
+	return false
+}
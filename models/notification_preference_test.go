@@ -0,0 +1,53 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNotificationPreferenceWants(t *testing.T) {
+	serverID := uuid.New()
+	// 02:00 UTC falls within the quiet hours 22:00-07:00
+	night := time.Date(2024, 1, 15, 2, 0, 0, 0, time.UTC)
+	noon := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	strict := NotificationPreference{
+		ServerIDs:       []uuid.UUID{uuid.New()},
+		Events:          []string{NotificationEventRecovered},
+		MinSeverity:     SeverityCritical,
+		QuietHoursStart: "22:00",
+		QuietHoursEnd:   "07:00",
+		Timezone:        "UTC",
+	}
+	quiet := DefaultNotificationPreference(uuid.New())
+	quiet.QuietHoursStart, quiet.QuietHoursEnd = "22:00", "07:00"
+
+	down := Alert{Event: NotificationEventDown, Severity: SeverityLow, ServerID: serverID}
+	page := down
+	page.Personal = true
+
+	tests := []struct {
+		name       string
+		preference NotificationPreference
+		alert      Alert
+		at         time.Time
+		want       bool
+	}{
+		{name: "default preferences want every alert", preference: DefaultNotificationPreference(uuid.New()), alert: down, at: night, want: true},
+		{name: "alert to all users in quiet hours", preference: quiet, alert: down, at: night, want: false},
+		{name: "alert to all users outside quiet hours", preference: quiet, alert: down, at: noon, want: true},
+		{name: "alert to all users filtered out", preference: strict, alert: down, at: noon, want: false},
+		{name: "on-call page in quiet hours", preference: quiet, alert: page, at: night, want: true},
+		{name: "on-call page bypasses filters and quiet hours", preference: strict, alert: page, at: night, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.preference.Wants(tt.alert, tt.at); got != tt.want {
+				t.Errorf("Wants() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
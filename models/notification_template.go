@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Notification locales
+const (
+	LocaleEnglish    = "en"
+	LocaleIndonesian = "id"
+)
+
+// Locales are the supported notification locales
+var Locales = []string{LocaleEnglish, LocaleIndonesian}
+
+// TemplateChannelPush is the template channel of FCM push notifications; the other
+// template channels are the notification channel types (WEBHOOK, EMAIL, SLACK)
+const TemplateChannelPush = "PUSH"
+
+// TemplateChannels are the channels a notification template can be set for
+var TemplateChannels = []string{TemplateChannelPush, ChannelTypeWebhook, ChannelTypeEmail, ChannelTypeSlack}
+
+// TemplateEvents are the notification events that have a template
+var TemplateEvents = []string{
+	NotificationEventDown,
+	NotificationEventRecovered,
+	NotificationEventCertExpiry,
+	NotificationEventFlapping,
+	NotificationEventAcknowledged,
+	NotificationEventResolved,
+	NotificationEventAssigned,
+	NotificationEventEscalated,
+	NotificationEventDigest,
+}
+
+// NotificationTemplate overrides the built-in title and body template of an event
+// for one channel and locale
+type NotificationTemplate struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Event     string    `gorm:"not null;uniqueIndex:idx_notification_templates_key" json:"event"`
+	Channel   string    `gorm:"not null;uniqueIndex:idx_notification_templates_key" json:"channel"` // PUSH, WEBHOOK, EMAIL, SLACK
+	Locale    string    `gorm:"not null;uniqueIndex:idx_notification_templates_key" json:"locale"`
+	Title     string    `gorm:"type:text;not null" json:"title"`
+	Body      string    `gorm:"type:text;not null" json:"body"`
+	UpdatedBy uuid.UUID `gorm:"type:uuid" json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Hook: auto set UUID
+func (t *NotificationTemplate) BeforeCreate(tx *gorm.DB) (err error) {
+	t.ID = uuid.New()
+	return
+}
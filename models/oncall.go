@@ -0,0 +1,179 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// On-call sources, telling what put a user on call
+const (
+	OnCallSourceLayer    = "LAYER"
+	OnCallSourceOverride = "OVERRIDE"
+)
+
+// On-call layer rotations, telling how long a shift lasts
+const (
+	OnCallRotationDaily  = "DAILY"
+	OnCallRotationWeekly = "WEEKLY"
+)
+
+// OnCallSchedule decides who is on call at any time. Overrides take precedence over layers,
+// and layers with a higher Level over lower ones. Times are evaluated in Timezone, so
+// rotation handoffs stay at the same wall-clock time across daylight saving changes.
+type OnCallSchedule struct {
+	ID          uuid.UUID        `gorm:"type:uuid;primaryKey" json:"id"`
+	Name        string           `gorm:"not null" json:"name"`
+	Description string           `json:"description,omitempty"`
+	Timezone    string           `gorm:"not null;default:'UTC'" json:"timezone"` // IANA name, e.g. Asia/Jakarta
+	Layers      []OnCallLayer    `gorm:"foreignKey:ScheduleID;constraint:OnDelete:CASCADE" json:"layers"`
+	Overrides   []OnCallOverride `gorm:"foreignKey:ScheduleID;constraint:OnDelete:CASCADE" json:"overrides,omitempty"`
+	CreatedBy   uuid.UUID        `gorm:"type:uuid" json:"created_by"`
+	CreatedAt   time.Time        `json:"created_at"`
+}
+
+// OnCallLayer rotates its users, one shift each, starting at StartsAt. Rotation is DAILY or
+// WEEKLY. A layer restricted to RestrictFrom-RestrictTo ("15:04", may wrap past midnight)
+// only covers that time of each day, leaving the rest to lower layers.
+type OnCallLayer struct {
+	ID           uuid.UUID   `gorm:"type:uuid;primaryKey" json:"id"`
+	ScheduleID   uuid.UUID   `gorm:"type:uuid;not null;index" json:"schedule_id"`
+	Level        int         `gorm:"not null" json:"level"` // 1-based, higher levels take precedence
+	Name         string      `json:"name,omitempty"`
+	StartsAt     time.Time   `gorm:"not null" json:"starts_at"` // start of the first shift, handoff time of later ones
+	EndsAt       *time.Time  `json:"ends_at,omitempty"`
+	Rotation     string      `gorm:"not null" json:"rotation"` // DAILY, WEEKLY
+	UserIDs      []uuid.UUID `gorm:"type:text;serializer:json" json:"user_ids"`
+	RestrictFrom string      `json:"restrict_from,omitempty"`
+	RestrictTo   string      `json:"restrict_to,omitempty"`
+}
+
+// OnCallOverride puts a user on call for [StartsAt, EndsAt), e.g. to swap a shift
+type OnCallOverride struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	ScheduleID uuid.UUID `gorm:"type:uuid;not null;index" json:"schedule_id"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
+	StartsAt   time.Time `gorm:"not null" json:"starts_at"`
+	EndsAt     time.Time `gorm:"not null" json:"ends_at"`
+	CreatedBy  uuid.UUID `gorm:"type:uuid" json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// OnCallEntry tells who is on call at a time and why
+type OnCallEntry struct {
+	UserID     uuid.UUID  `json:"user_id"`
+	Source     string     `json:"source"` // LAYER, OVERRIDE
+	LayerID    *uuid.UUID `json:"layer_id,omitempty"`
+	OverrideID *uuid.UUID `json:"override_id,omitempty"`
+}
+
+// Location returns the schedule's time zone, falling back to UTC
+func (s *OnCallSchedule) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// OnCallAt returns who is on call at t, or nil if nobody is.
+// Of overlapping overrides, the most recently created one wins.
+func (s *OnCallSchedule) OnCallAt(t time.Time) *OnCallEntry {
+	var override *OnCallOverride
+	for i := range s.Overrides {
+		o := &s.Overrides[i]
+		if !t.Before(o.StartsAt) && t.Before(o.EndsAt) && (override == nil || o.CreatedAt.After(override.CreatedAt)) {
+			override = o
+		}
+	}
+	if override != nil {
+		return &OnCallEntry{UserID: override.UserID, Source: OnCallSourceOverride, OverrideID: &override.ID}
+	}
+
+	layers := append([]OnCallLayer(nil), s.Layers...)
+	sort.SliceStable(layers, func(i, j int) bool { return layers[i].Level > layers[j].Level })
+
+	loc := s.Location()
+	for i := range layers {
+		if userID, ok := layers[i].userAt(t, loc); ok {
+			return &OnCallEntry{UserID: userID, Source: OnCallSourceLayer, LayerID: &layers[i].ID}
+		}
+	}
+	return nil
+}
+
+// userAt returns the user of the layer's shift at t, if the layer covers t
+func (l *OnCallLayer) userAt(t time.Time, loc *time.Location) (uuid.UUID, bool) {
+	if len(l.UserIDs) == 0 || t.Before(l.StartsAt) || (l.EndsAt != nil && !t.Before(*l.EndsAt)) {
+		return uuid.Nil, false
+	}
+	if !l.covers(t.In(loc)) {
+		return uuid.Nil, false
+	}
+
+	days := 1
+	if l.Rotation == OnCallRotationWeekly {
+		days = 7
+	}
+
+	// Count the handoffs so far on the local calendar, correcting the estimate for DST shifts
+	start := l.StartsAt.In(loc)
+	shift := int(t.Sub(start) / (time.Duration(days) * 24 * time.Hour))
+	for !start.AddDate(0, 0, (shift+1)*days).After(t) {
+		shift++
+	}
+	for shift > 0 && start.AddDate(0, 0, shift*days).After(t) {
+		shift--
+	}
+
+	return l.UserIDs[shift%len(l.UserIDs)], true
+}
+
+// covers reports whether the layer's daily restriction includes the local time t
+func (l *OnCallLayer) covers(t time.Time) bool {
+	if l.RestrictFrom == "" || l.RestrictTo == "" {
+		return true
+	}
+	within, ok := withinDailyWindow(t, l.RestrictFrom, l.RestrictTo)
+	return within || !ok
+}
+
+// withinDailyWindow reports whether the local time t lies within the daily window
+// from-to ("15:04", may wrap past midnight); ok is false if a bound is invalid
+func withinDailyWindow(t time.Time, fromStr, toStr string) (within, ok bool) {
+	from, errFrom := time.Parse("15:04", fromStr)
+	to, errTo := time.Parse("15:04", toStr)
+	if errFrom != nil || errTo != nil {
+		return false, false
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	fromMinute := from.Hour()*60 + from.Minute()
+	toMinute := to.Hour()*60 + to.Minute()
+	if fromMinute <= toMinute {
+		return minute >= fromMinute && minute < toMinute, true
+	}
+	return minute >= fromMinute || minute < toMinute, true
+}
+
+// Hook: auto set UUID & timestamp
+func (s *OnCallSchedule) BeforeCreate(tx *gorm.DB) (err error) {
+	s.ID = uuid.New()
+	s.CreatedAt = time.Now()
+	return
+}
+
+// Hook: auto set UUID
+func (l *OnCallLayer) BeforeCreate(tx *gorm.DB) (err error) {
+	l.ID = uuid.New()
+	return
+}
+
+// Hook: auto set UUID & timestamp
+func (o *OnCallOverride) BeforeCreate(tx *gorm.DB) (err error) {
+	o.ID = uuid.New()
+	o.CreatedAt = time.Now()
+	return
+}
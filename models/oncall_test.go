@@ -0,0 +1,74 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestOnCallAt(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	alice, bob, carol, dave := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	utc := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	ended := utc(3, 10, 8, 0)
+
+	// Daily handoff at 09:00 Berlin time: 08:00 UTC until March 29, 07:00 UTC after the switch to summer time
+	daily := OnCallLayer{ID: uuid.New(), Level: 1, StartsAt: time.Date(2026, 3, 1, 9, 0, 0, 0, berlin), Rotation: OnCallRotationDaily, UserIDs: []uuid.UUID{alice, bob, carol}}
+	weekly := OnCallLayer{ID: uuid.New(), Level: 1, StartsAt: utc(3, 2, 9, 0), Rotation: OnCallRotationWeekly, UserIDs: []uuid.UUID{alice, bob}}
+	endedLayer := daily
+	endedLayer.EndsAt = &ended
+	// Dave covers the nights on top of the daily rotation
+	nights := OnCallLayer{ID: uuid.New(), Level: 2, StartsAt: time.Date(2026, 3, 1, 9, 0, 0, 0, berlin), Rotation: OnCallRotationWeekly, UserIDs: []uuid.UUID{dave}, RestrictFrom: "22:00", RestrictTo: "06:00"}
+
+	swap := OnCallOverride{ID: uuid.New(), UserID: dave, StartsAt: utc(3, 5, 12, 0), EndsAt: utc(3, 5, 18, 0), CreatedAt: utc(3, 1, 0, 0)}
+	swapBack := OnCallOverride{ID: uuid.New(), UserID: carol, StartsAt: utc(3, 5, 14, 0), EndsAt: utc(3, 5, 16, 0), CreatedAt: utc(3, 2, 0, 0)}
+
+	tests := []struct {
+		name       string
+		schedule   OnCallSchedule
+		at         time.Time
+		wantUser   uuid.UUID // uuid.Nil when nobody is on call
+		wantSource string
+	}{
+		{name: "first shift", schedule: OnCallSchedule{Timezone: "Europe/Berlin", Layers: []OnCallLayer{daily}}, at: utc(3, 1, 8, 0), wantUser: alice, wantSource: OnCallSourceLayer},
+		{name: "just before the handoff", schedule: OnCallSchedule{Timezone: "Europe/Berlin", Layers: []OnCallLayer{daily}}, at: utc(3, 2, 7, 59), wantUser: alice, wantSource: OnCallSourceLayer},
+		{name: "at the handoff", schedule: OnCallSchedule{Timezone: "Europe/Berlin", Layers: []OnCallLayer{daily}}, at: utc(3, 2, 8, 0), wantUser: bob, wantSource: OnCallSourceLayer},
+		{name: "rotation wraps around", schedule: OnCallSchedule{Timezone: "Europe/Berlin", Layers: []OnCallLayer{daily}}, at: utc(3, 4, 8, 0), wantUser: alice, wantSource: OnCallSourceLayer},
+		{name: "handoff keeps its local time before daylight saving", schedule: OnCallSchedule{Timezone: "Europe/Berlin", Layers: []OnCallLayer{daily}}, at: utc(3, 29, 6, 59), wantUser: alice, wantSource: OnCallSourceLayer},
+		{name: "handoff keeps its local time after daylight saving", schedule: OnCallSchedule{Timezone: "Europe/Berlin", Layers: []OnCallLayer{daily}}, at: utc(3, 29, 7, 0), wantUser: bob, wantSource: OnCallSourceLayer},
+		{name: "weekly shift", schedule: OnCallSchedule{Timezone: "UTC", Layers: []OnCallLayer{weekly}}, at: utc(3, 9, 8, 59), wantUser: alice, wantSource: OnCallSourceLayer},
+		{name: "weekly handoff", schedule: OnCallSchedule{Timezone: "UTC", Layers: []OnCallLayer{weekly}}, at: utc(3, 9, 9, 0), wantUser: bob, wantSource: OnCallSourceLayer},
+		{name: "restricted layer takes precedence within its hours", schedule: OnCallSchedule{Timezone: "Europe/Berlin", Layers: []OnCallLayer{daily, nights}}, at: utc(3, 1, 23, 0), wantUser: dave, wantSource: OnCallSourceLayer},
+		{name: "lower layer outside the restricted hours", schedule: OnCallSchedule{Timezone: "Europe/Berlin", Layers: []OnCallLayer{daily, nights}}, at: utc(3, 1, 12, 0), wantUser: alice, wantSource: OnCallSourceLayer},
+		{name: "override takes precedence over layers", schedule: OnCallSchedule{Timezone: "Europe/Berlin", Layers: []OnCallLayer{daily}, Overrides: []OnCallOverride{swap, swapBack}}, at: utc(3, 5, 13, 0), wantUser: dave, wantSource: OnCallSourceOverride},
+		{name: "latest overlapping override wins", schedule: OnCallSchedule{Timezone: "Europe/Berlin", Layers: []OnCallLayer{daily}, Overrides: []OnCallOverride{swap, swapBack}}, at: utc(3, 5, 15, 0), wantUser: carol, wantSource: OnCallSourceOverride},
+		{name: "layer again after the override", schedule: OnCallSchedule{Timezone: "Europe/Berlin", Layers: []OnCallLayer{daily}, Overrides: []OnCallOverride{swap}}, at: utc(3, 5, 18, 0), wantUser: bob, wantSource: OnCallSourceLayer},
+		{name: "nobody before the first shift", schedule: OnCallSchedule{Timezone: "Europe/Berlin", Layers: []OnCallLayer{daily}}, at: utc(3, 1, 7, 59)},
+		{name: "nobody after the layer ended", schedule: OnCallSchedule{Timezone: "Europe/Berlin", Layers: []OnCallLayer{endedLayer}}, at: utc(3, 10, 8, 0)},
+		{name: "nobody without layers", schedule: OnCallSchedule{Timezone: "UTC"}, at: utc(3, 10, 8, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := tt.schedule.OnCallAt(tt.at)
+			if tt.wantUser == uuid.Nil {
+				if entry != nil {
+					t.Errorf("on call = %v, want nobody", entry.UserID)
+				}
+				return
+			}
+			if entry == nil {
+				t.Fatal("nobody on call, want a user")
+			}
+			if entry.UserID != tt.wantUser || entry.Source != tt.wantSource {
+				t.Errorf("on call = %v (%s), want %v (%s)", entry.UserID, entry.Source, tt.wantUser, tt.wantSource)
+			}
+		})
+	}
+}
@@ -7,17 +7,178 @@ import (
 	"gorm.io/gorm"
 )
 
+// Server statuses
+const (
+	ServerStatusUp          = "UP"
+	ServerStatusDown        = "DOWN"
+	ServerStatusUnknown     = "UNKNOWN"
+	ServerStatusSuspected   = "SUSPECTED"   // DOWN reported, but not yet confirmed by enough reporters
+	ServerStatusMaintenance = "MAINTENANCE" // DOWN reported during a maintenance window
+)
+
+// Check types
+const (
+	CheckTypeHTTP      = "HTTP"      // GET request to URL
+	CheckTypeTCP       = "TCP"       // TCP connect to Host:Port
+	CheckTypeDNS       = "DNS"       // resolve DNSRecordType records of Host
+	CheckTypeTLS       = "TLS"       // TLS handshake with Host:Port (default 443)
+	CheckTypeHeartbeat = "HEARTBEAT" // pushed: the server pings its heartbeat URL every HeartbeatPeriodSeconds
+)
+
+// Server severities, lowest first; alerts of a server carry its severity
+const (
+	SeverityLow      = "LOW"
+	SeverityMedium   = "MEDIUM"
+	SeverityHigh     = "HIGH"
+	SeverityCritical = "CRITICAL"
+)
+
+// SeverityRank orders severities from LOW (1) to CRITICAL (4); unknown severities rank as MEDIUM
+func SeverityRank(severity string) int {
+	switch severity {
+	case SeverityLow:
+		return 1
+	case SeverityHigh:
+		return 3
+	case SeverityCritical:
+		return 4
+	default:
+		return 2
+	}
+}
+
+// Status check sources
+const (
+	CheckSourceClient    = "CLIENT"    // reported by a mobile client
+	CheckSourceProbe     = "PROBE"     // checked by the backend probe scheduler
+	CheckSourceHeartbeat = "HEARTBEAT" // heartbeat ping, or a missed one
+)
+
 type Server struct {
-	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
-	Name      string    `gorm:"not null" json:"name"`
-	URL       string    `gorm:"not null" json:"url"`
+	ID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Name string    `gorm:"not null" json:"name"`
+	URL  string    `gorm:"not null" json:"url"`
+
+	// Check settings; Host/Port/DNS fields are only used by their check types
+	CheckType      string `gorm:"not null;default:'HTTP'" json:"check_type"` // HTTP, TCP, DNS, TLS, HEARTBEAT
+	Host           string `json:"host,omitempty"`
+	Port           int    `json:"port,omitempty"`
+	DNSRecordType  string `json:"dns_record_type,omitempty"` // A, AAAA, CNAME, MX, NS, TXT
+	DNSExpected    string `json:"dns_expected,omitempty"`    // optional value one of the answers must match
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty"` // 0 uses PROBE_TIMEOUT_SECONDS
+
+	Assertions HTTPAssertions `gorm:"embedded;embeddedPrefix:assert_" json:"assertions"`
+
+	// Heartbeat settings: a ping is expected every HeartbeatPeriodSeconds, plus HeartbeatGraceSeconds
+	// of slack, on POST /api/heartbeat/:token. HeartbeatExpectedBy is the deadline of the next ping.
+	// The token is a secret: it is only returned to the creator, as IssuedHeartbeatToken, when generated.
+	HeartbeatToken         *string    `gorm:"uniqueIndex" json:"-"`
+	IssuedHeartbeatToken   string     `gorm:"-" json:"heartbeat_token,omitempty"`
+	HeartbeatPeriodSeconds int        `json:"heartbeat_period_seconds,omitempty"`
+	HeartbeatGraceSeconds  int        `json:"heartbeat_grace_seconds,omitempty"`
+	LastHeartbeatAt        *time.Time `json:"last_heartbeat_at,omitempty"`
+	HeartbeatExpectedBy    *time.Time `gorm:"index" json:"heartbeat_expected_by,omitempty"`
+
+	// TLS certificate seen by the last HTTPS/TLS check
+	Certificate    CertificateInfo `gorm:"embedded;embeddedPrefix:cert_" json:"certificate"`
+	CertWarnedDays int             `json:"-"` // smallest expiry warning threshold already sent for this certificate
+
+	Status          string     `gorm:"not null;default:'UNKNOWN'" json:"status"` // UP, DOWN, SUSPECTED, MAINTENANCE, UNKNOWN
+	ResponseTime    int64      `json:"response_time"`                            // milliseconds
+	LastChecked     *time.Time `json:"last_checked,omitempty"`
+	LastCheckedBy   *uuid.UUID `gorm:"type:uuid" json:"last_checked_by,omitempty"`
+	LastCheckSource string     `json:"last_check_source,omitempty"` // CLIENT, PROBE, HEARTBEAT
+	SuspectedSince  *time.Time `json:"suspected_since,omitempty"`
+
+	// DOWN confirmation policy: at least ConfirmReporters distinct reporters (users or probe)
+	// must report DOWN within ConfirmWindowSeconds before an incident is opened
+	ConfirmReporters     int `gorm:"not null;default:1" json:"confirm_reporters"`
+	ConfirmWindowSeconds int `gorm:"not null;default:300" json:"confirm_window_seconds"`
+
+	// Hysteresis: a server is only considered DOWN after FailureThreshold consecutive DOWN
+	// observations, and only recovers after SuccessThreshold consecutive UP observations
+	FailureThreshold     int `gorm:"not null;default:1" json:"failure_threshold"`
+	SuccessThreshold     int `gorm:"not null;default:1" json:"success_threshold"`
+	ConsecutiveFailures  int `json:"consecutive_failures"`
+	ConsecutiveSuccesses int `json:"consecutive_successes"`
+
+	// Flap detection: while flapping, DOWN/UP notifications are collapsed into one alert
+	Flapping      bool       `gorm:"not null;default:false" json:"flapping"`
+	FlappingSince *time.Time `json:"flapping_since,omitempty"`
+
+	DependsOn []uuid.UUID `gorm:"-" json:"depends_on,omitempty"` // parent servers, see ServerDependency
+
+	EscalationPolicyID *uuid.UUID `gorm:"type:uuid;index" json:"escalation_policy_id,omitempty"`
+	OnCallScheduleID   *uuid.UUID `gorm:"type:uuid;index" json:"on_call_schedule_id,omitempty"` // notifications go to the on-call user
+
+	// Notification routing: users subscribe to servers by ID, tag or division
+	// and can ignore alerts below a minimum severity
+	Tags     []string `gorm:"type:text;serializer:json" json:"tags,omitempty"` // lower case
+	Division string   `json:"division,omitempty"`                              // team owning the server
+	Severity string   `gorm:"not null;default:'MEDIUM'" json:"severity"`       // LOW, MEDIUM, HIGH, CRITICAL
+
 	CreatedBy uuid.UUID `gorm:"type:uuid" json:"created_by"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// ServerDependency declares that a server depends on a parent server (e.g. its gateway or
+// database host). DOWN reports of a server whose parent is DOWN are linked to the parent incident.
+type ServerDependency struct {
+	ServerID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"server_id"`
+	DependsOnID uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"depends_on_id"`
+}
+
+// HTTPAssertions are extra conditions an HTTP check response must meet to count as UP
+type HTTPAssertions struct {
+	StatusCodes       string            `json:"status_codes,omitempty"` // e.g. "200-299,301"; empty accepts any 2xx/3xx
+	BodyContains      string            `json:"body_contains,omitempty"`
+	BodyNotContains   string            `json:"body_not_contains,omitempty"`
+	BodyRegex         string            `json:"body_regex,omitempty"`
+	JSONPath          string            `json:"json_path,omitempty"` // e.g. "$.data.status" or "$.items[0].ok"
+	JSONPathValue     string            `json:"json_path_value,omitempty"`
+	Headers           map[string]string `gorm:"type:text;serializer:json" json:"headers,omitempty"` // empty value only requires presence
+	MaxResponseTimeMs int64             `json:"max_response_time_ms,omitempty"`
+}
+
+// CertificateInfo describes a server's TLS certificate
+type CertificateInfo struct {
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // earliest expiry in the chain
+	Issuer    string     `json:"issuer,omitempty"`
+	Subject   string     `json:"subject,omitempty"`
+	SANs      []string   `gorm:"column:sans;type:text;serializer:json" json:"sans,omitempty"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+}
+
 // Auto generate UUID & timestamp
 func (s *Server) BeforeCreate(tx *gorm.DB) (err error) {
 	s.ID = uuid.New()
 	s.CreatedAt = time.Now()
+	if s.Status == "" {
+		s.Status = ServerStatusUnknown
+	}
+	if s.CheckType == "" {
+		s.CheckType = CheckTypeHTTP
+	}
+	if s.Severity == "" {
+		s.Severity = SeverityMedium
+	}
+	if s.ConfirmReporters <= 0 {
+		s.ConfirmReporters = 1
+	}
+	if s.ConfirmWindowSeconds <= 0 {
+		s.ConfirmWindowSeconds = 300
+	}
+	if s.FailureThreshold <= 0 {
+		s.FailureThreshold = 1
+	}
+	if s.SuccessThreshold <= 0 {
+		s.SuccessThreshold = 1
+	}
 	return
 }
+
+// ScheduleHeartbeat sets the deadline of the next heartbeat ping, counted from the given time
+func (s *Server) ScheduleHeartbeat(from time.Time) {
+	expectedBy := from.Add(time.Duration(s.HeartbeatPeriodSeconds+s.HeartbeatGraceSeconds) * time.Second)
+	s.HeartbeatExpectedBy = &expectedBy
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RefreshToken is a single-use refresh token, stored as a SHA-256 hash. Refreshing rotates it:
+// it is marked used and a new token of the same family is issued together with a new access
+// token. All tokens issued from one login share a family, so presenting a used token again
+// reveals a stolen token and the whole family is revoked.
+type RefreshToken struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	FamilyID        uuid.UUID  `gorm:"type:uuid;not null;index" json:"family_id"`
+	TokenHash       string     `gorm:"uniqueIndex;not null" json:"-"`
+	AccessTokenID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"access_token_id"` // jti of the access token issued with it
+	AccessExpiresAt time.Time  `gorm:"not null" json:"access_expires_at"`
+	ExpiresAt       time.Time  `gorm:"not null;index" json:"expires_at"`
+	UsedAt          *time.Time `json:"used_at,omitempty"`    // rotated
+	RevokedAt       *time.Time `json:"revoked_at,omitempty"` // logged out or family revoked
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// RevokedToken is an access token revoked before it expires, by its token ID (jti).
+// It is kept until the access token expires.
+type RevokedToken struct {
+	TokenID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"token_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`
+	RevokedAt time.Time `gorm:"not null" json:"revoked_at"`
+}
+
+// Hook: auto set UUID & timestamp
+func (t *RefreshToken) BeforeCreate(tx *gorm.DB) (err error) {
+	t.ID = uuid.New()
+	t.CreatedAt = time.Now()
+	return
+}
@@ -7,6 +7,10 @@ import (
 	"gorm.io/gorm"
 )
 
+// SystemUserID identifies actions performed by the backend itself (e.g. the probe scheduler)
+// instead of a registered user
+var SystemUserID = uuid.Nil
+
 type User struct {
 	ID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
 	Name         string    `gorm:"not null" json:"name"`
@@ -14,6 +18,7 @@ type User struct {
 	PasswordHash string    `gorm:"not null" json:"-"`
 	Division     string    `json:"division"`
 	Phone        string    `json:"phone"`
+	Locale       string    `json:"locale"`                     // notification locale (en, id); empty uses NOTIFICATION_LOCALE
 	Role         string    `gorm:"default:'USER'" json:"role"` // ADMIN, USER
 	IsActive     bool      `gorm:"default:true" json:"is_active"`
 	CreatedAt    time.Time `json:"created_at"`
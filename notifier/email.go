@@ -0,0 +1,127 @@
+package notifier
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/models"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EmailSender sends the message as a plain text email to the channel addresses.
+// STARTTLS is used when the SMTP server offers it, and credentials are only sent
+// if the server supports AUTH.
+type EmailSender struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// NewEmailSender creates an email sender for the given SMTP server
+func NewEmailSender(cfg config.SMTPConfig) *EmailSender {
+	return &EmailSender{
+		Host:     cfg.Host,
+		Port:     cfg.Port,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		From:     cfg.From,
+	}
+}
+
+// Send delivers a message to an email channel
+func (s *EmailSender) Send(ctx context.Context, channel *models.NotificationChannel, msg Message) error {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", net.JoinHostPort(s.Host, strconv.Itoa(s.Port)))
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("SMTP handshake failed: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.Host}); err != nil {
+			return fmt.Errorf("SMTP STARTTLS failed: %w", err)
+		}
+	}
+	if s.Username != "" {
+		if ok, _ := client.Extension("AUTH"); ok {
+			if err := client.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host)); err != nil {
+				return fmt.Errorf("SMTP authentication failed: %w", err)
+			}
+		}
+	}
+
+	if err := client.Mail(s.From); err != nil {
+		return fmt.Errorf("SMTP MAIL FROM rejected: %w", err)
+	}
+	for _, to := range channel.EmailTo {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("SMTP RCPT TO %s rejected: %w", to, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA rejected: %w", err)
+	}
+	if _, err := writer.Write(s.compose(channel.EmailTo, msg)); err != nil {
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("SMTP server rejected the email: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// Validate checks that the channel has recipients
+func (s *EmailSender) Validate(channel *models.NotificationChannel) error {
+	if len(channel.EmailTo) == 0 {
+		return errors.New("email channels require email_to")
+	}
+	return nil
+}
+
+// compose builds the email with headers; the data fields are listed below the body
+func (s *EmailSender) compose(to []string, msg Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + s.From + "\r\n")
+	b.WriteString("To: " + strings.Join(to, ", ") + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Title) + "\r\n")
+	b.WriteString("Date: " + msg.Timestamp.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body + "\r\n")
+
+	if len(msg.Data) > 0 {
+		keys := make([]string, 0, len(msg.Data))
+		for key := range msg.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		b.WriteString("\r\n")
+		for _, key := range keys {
+			b.WriteString(key + ": " + msg.Data[key] + "\r\n")
+		}
+	}
+	return []byte(b.String())
+}
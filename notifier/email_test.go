@@ -0,0 +1,141 @@
+package notifier
+
+import (
+	"NetGuardServer/models"
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpSession is what an SMTP stand-in received in one session
+type smtpSession struct {
+	from string
+	to   []string
+	data string
+}
+
+// smtpStandIn accepts one SMTP session on a local port without STARTTLS or AUTH.
+// Recipients in reject are refused. It returns the port and the received session.
+func smtpStandIn(t *testing.T, reject string) (int, <-chan smtpSession) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	sessions := make(chan smtpSession, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var session smtpSession
+		defer func() { sessions <- session }()
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		reply("220 localhost ESMTP stand-in")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			command := strings.ToUpper(line)
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(command, "MAIL FROM:"):
+				session.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+				reply("250 OK")
+			case strings.HasPrefix(command, "RCPT TO:"):
+				to := strings.Trim(line[len("RCPT TO:"):], "<>")
+				if to == reject {
+					reply("550 No such user")
+					continue
+				}
+				session.to = append(session.to, to)
+				reply("250 OK")
+			case command == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					dataLine, err := reader.ReadString('\n')
+					if err != nil || dataLine == ".\r\n" {
+						break
+					}
+					data.WriteString(dataLine)
+				}
+				session.data = data.String()
+				reply("250 OK")
+			case command == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("502 Command not implemented")
+			}
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port, sessions
+}
+
+func TestEmailSender(t *testing.T) {
+	msg := Message{
+		Event:     models.NotificationEventDown,
+		Title:     "Server DOWN: API Server",
+		Body:      "https://api.company.com is unreachable",
+		Data:      map[string]string{"server_name": "API Server"},
+		Timestamp: time.Now(),
+	}
+
+	tests := []struct {
+		name    string
+		to      []string
+		reject  string
+		wantErr bool
+	}{
+		{name: "delivered to every recipient", to: []string{"ops@company.com", "noc@company.com"}},
+		{name: "recipient rejected", to: []string{"ops@company.com", "gone@company.com"}, reject: "gone@company.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port, sessions := smtpStandIn(t, tt.reject)
+			sender := &EmailSender{Host: "127.0.0.1", Port: port, From: "netguard@company.com"}
+			channel := &models.NotificationChannel{Type: models.ChannelTypeEmail, EmailTo: tt.to}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			err := sender.Send(ctx, channel, msg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+
+			session := <-sessions
+			if session.from != sender.From {
+				t.Errorf("MAIL FROM = %q, want %q", session.from, sender.From)
+			}
+			if tt.wantErr {
+				if session.data != "" {
+					t.Error("email sent although a recipient was rejected")
+				}
+				return
+			}
+			if strings.Join(session.to, ",") != strings.Join(tt.to, ",") {
+				t.Errorf("RCPT TO = %v, want %v", session.to, tt.to)
+			}
+			for _, want := range []string{"Subject: " + msg.Title, "To: " + strings.Join(tt.to, ", "), msg.Body, "server_name: API Server"} {
+				if !strings.Contains(session.data, want) {
+					t.Errorf("email does not contain %q:\n%s", want, session.data)
+				}
+			}
+		})
+	}
+}
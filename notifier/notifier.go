@@ -0,0 +1,90 @@
+// Package notifier implements the notification channels (signed webhooks, SMTP email and
+// Slack/Mattermost incoming webhooks) that deliver server alerts next to FCM push
+// notifications, so outages still reach the team when Firebase is unavailable.
+package notifier
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// defaultTimeout is used when the configuration sets no channel timeout
+const defaultTimeout = 10 * time.Second
+
+// Message is a notification delivered over a channel
+type Message struct {
+	Event     string            `json:"event"` // DOWN, RECOVERED, CERT_EXPIRY, FLAPPING, TEST
+	Title     string            `json:"title"`
+	Body      string            `json:"body"`
+	Data      map[string]string `json:"data,omitempty"`
+	Timestamp time.Time         `json:"timestamp"`
+}
+
+// Sender delivers messages over one type of channel
+type Sender interface {
+	Send(ctx context.Context, channel *models.NotificationChannel, msg Message) error
+	Validate(channel *models.NotificationChannel) error
+}
+
+// Dispatcher dispatches messages to the sender registered for the channel type
+type Dispatcher struct {
+	senders map[string]Sender
+	timeout time.Duration
+}
+
+// NewDispatcher creates a dispatcher with the given senders and timeout per message
+func NewDispatcher(senders map[string]Sender, timeout time.Duration) *Dispatcher {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Dispatcher{
+		senders: senders,
+		timeout: timeout,
+	}
+}
+
+// NewDefaultDispatcher creates a dispatcher with all built-in channel types, using the
+// channel timeout and SMTP server from the configuration. Email channels are only
+// available when an SMTP host is configured.
+func NewDefaultDispatcher() *Dispatcher {
+	senders := map[string]Sender{
+		models.ChannelTypeWebhook: NewWebhookSender(),
+		models.ChannelTypeSlack:   NewSlackSender(),
+	}
+	if config.AppConfig.SMTP.Host != "" {
+		senders[models.ChannelTypeEmail] = NewEmailSender(config.AppConfig.SMTP)
+	}
+	return NewDispatcher(senders, time.Duration(config.AppConfig.Channels.TimeoutSeconds)*time.Second)
+}
+
+// Send delivers a message over a channel
+func (d *Dispatcher) Send(ctx context.Context, channel *models.NotificationChannel, msg Message) error {
+	sender, ok := d.senders[channel.Type]
+	if !ok {
+		return fmt.Errorf("unsupported channel type %s", channel.Type)
+	}
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	return sender.Send(ctx, channel, msg)
+}
+
+// Validate checks that a channel has the settings its type requires
+func (d *Dispatcher) Validate(channel *models.NotificationChannel) error {
+	sender, ok := d.senders[channel.Type]
+	if !ok {
+		if channel.Type == models.ChannelTypeEmail {
+			return errors.New("email channels require SMTP_HOST to be configured")
+		}
+		return errors.New("channel type must be WEBHOOK, EMAIL, or SLACK")
+	}
+	return sender.Validate(channel)
+}
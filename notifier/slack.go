@@ -0,0 +1,38 @@
+package notifier
+
+import (
+	"NetGuardServer/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SlackSender posts the message to a Slack or Mattermost incoming webhook,
+// which both accept {"text": ...} with Markdown-style bold
+type SlackSender struct {
+	Client *http.Client
+}
+
+// NewSlackSender creates a Slack/Mattermost sender; timeouts come from the request context
+func NewSlackSender() *SlackSender {
+	return &SlackSender{Client: &http.Client{}}
+}
+
+// Send delivers a message to a Slack/Mattermost channel
+func (s *SlackSender) Send(ctx context.Context, channel *models.NotificationChannel, msg Message) error {
+	payload, err := json.Marshal(map[string]string{
+		"username": "NetGuard",
+		"text":     fmt.Sprintf("*%s*\n%s", msg.Title, msg.Body),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode chat payload: %w", err)
+	}
+
+	return postJSON(ctx, s.Client, channel.URL, payload, nil)
+}
+
+// Validate checks the incoming webhook URL
+func (s *SlackSender) Validate(channel *models.NotificationChannel) error {
+	return validateURL(channel.URL)
+}
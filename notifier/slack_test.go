@@ -0,0 +1,49 @@
+package notifier
+
+import (
+	"NetGuardServer/models"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSlackSender(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "delivered", status: http.StatusOK},
+		{name: "rejected", status: http.StatusForbidden, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := make(chan received, 1)
+			server := webhookStandIn(t, tt.status, requests)
+			channel := &models.NotificationChannel{Type: models.ChannelTypeSlack, URL: server.URL + "/services/T000/B000/XXXX"}
+
+			err := NewSlackSender().Send(context.Background(), channel, Message{Title: "Server DOWN", Body: "API Server is down"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && (strings.Contains(err.Error(), "leaked") || strings.Contains(err.Error(), "XXXX")) {
+				t.Errorf("error %q leaks the response body or URL", err)
+			}
+
+			req := <-requests
+			var payload map[string]string
+			if err := json.Unmarshal(req.body, &payload); err != nil {
+				t.Fatalf("invalid payload %s: %v", req.body, err)
+			}
+			if payload["text"] != "*Server DOWN*\nAPI Server is down" || payload["username"] != "NetGuard" {
+				t.Errorf("payload = %v", payload)
+			}
+			if req.header.Get(SignatureHeader) != "" {
+				t.Error("Slack requests are not signed")
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package notifier
+
+import (
+	"NetGuardServer/models"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	templateparse "text/template/parse"
+	"time"
+)
+
+// Template renders the title and body of a notification with text/template.
+// Templates are executed against the data payload of the notification, e.g.
+// {{.server_name}}; keys missing from the payload render as empty strings and
+// the values "true" and "false" are booleans, e.g. {{if .flapping}}.
+type Template struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// templateFuncs are the functions available to notification templates
+var templateFuncs = template.FuncMap{
+	// duration formats a number of seconds, e.g. "5400" as "1h30m0s"
+	"duration": func(seconds string) string {
+		n, err := strconv.ParseInt(seconds, 10, 64)
+		if err != nil {
+			return seconds
+		}
+		return (time.Duration(n) * time.Second).String()
+	},
+	// datetime formats an RFC 3339 timestamp, e.g. as "2024-01-31 23:59 UTC"
+	"datetime": func(value string) string {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return value
+		}
+		return t.Format("2006-01-02 15:04 MST")
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// defaultTemplates are the built-in templates per locale and event
+var defaultTemplates = map[string]map[string]Template{
+	models.LocaleEnglish: {
+		models.NotificationEventDown: {
+			Title: "Server DOWN: {{.server_name}}",
+			Body:  "{{.server_url}}",
+		},
+		models.NotificationEventRecovered: {
+			Title: "Server UP: {{.server_name}}",
+			Body:  "{{.server_url}} is back online after {{duration .downtime_seconds}}",
+		},
+		models.NotificationEventCertExpiry: {
+			Title: "Certificate expiring: {{.server_name}}",
+			Body:  "TLS certificate of {{.server_url}} expires in {{.days_left}} day(s) ({{datetime .expires_at}})",
+		},
+		models.NotificationEventFlapping: {
+			Title: `{{if .flapping}}Server flapping{{else}}Server stable{{end}}: {{.server_name}}`,
+			Body:  `{{if .flapping}}{{.server_url}} changed state {{.state_changes}} times in the last {{duration .window_seconds}}{{else}}{{.server_url}} stopped flapping and is {{.status}}{{end}}`,
+		},
+		models.NotificationEventAcknowledged: {
+			Title: "Incident acknowledged: {{.server_name}}",
+			Body:  "{{.acknowledged_by}} is working on the incident of {{.server_url}}",
+		},
+		models.NotificationEventResolved: {
+			Title: "Incident resolved: {{.server_name}}",
+			Body:  "{{.resolved_by}} resolved the incident of {{.server_url}}: {{.resolve_note}}",
+		},
+		models.NotificationEventAssigned: {
+			Title: "Incident assigned: {{.server_name}}",
+			Body:  "{{.assigned_by}} assigned you the incident of {{.server_url}}",
+		},
+		models.NotificationEventEscalated: {
+			Title: "Escalation level {{.escalation_level}}: {{.server_name}}",
+			Body:  "{{.server_url}} is DOWN and nobody acknowledged the incident yet",
+		},
+		models.NotificationEventDigest: {
+			Title: "NetGuard digest: {{.summary}}",
+			Body:  "Servers: {{.servers}}",
+		},
+	},
+	models.LocaleIndonesian: {
+		models.NotificationEventDown: {
+			Title: "Server DOWN: {{.server_name}}",
+			Body:  "{{.server_url}} tidak dapat dijangkau",
+		},
+		models.NotificationEventRecovered: {
+			Title: "Server UP: {{.server_name}}",
+			Body:  "{{.server_url}} kembali online setelah down selama {{duration .downtime_seconds}}",
+		},
+		models.NotificationEventCertExpiry: {
+			Title: "Sertifikat segera kedaluwarsa: {{.server_name}}",
+			Body:  "Sertifikat TLS {{.server_url}} kedaluwarsa dalam {{.days_left}} hari ({{datetime .expires_at}})",
+		},
+		models.NotificationEventFlapping: {
+			Title: `{{if .flapping}}Server flapping{{else}}Server stabil{{end}}: {{.server_name}}`,
+			Body:  `{{if .flapping}}{{.server_url}} berganti status {{.state_changes}} kali dalam {{duration .window_seconds}} terakhir{{else}}{{.server_url}} berhenti flapping dan berstatus {{.status}}{{end}}`,
+		},
+		models.NotificationEventAcknowledged: {
+			Title: "Incident di-acknowledge: {{.server_name}}",
+			Body:  "{{.acknowledged_by}} sedang menangani incident {{.server_url}}",
+		},
+		models.NotificationEventResolved: {
+			Title: "Incident selesai: {{.server_name}}",
+			Body:  "{{.resolved_by}} menyelesaikan incident {{.server_url}}: {{.resolve_note}}",
+		},
+		models.NotificationEventAssigned: {
+			Title: "Incident ditugaskan: {{.server_name}}",
+			Body:  "{{.assigned_by}} menugaskan incident {{.server_url}} kepada Anda",
+		},
+		models.NotificationEventEscalated: {
+			Title: "Eskalasi level {{.escalation_level}}: {{.server_name}}",
+			Body:  "{{.server_url}} DOWN dan belum ada yang meng-acknowledge incident",
+		},
+		models.NotificationEventDigest: {
+			Title: "Ringkasan NetGuard: {{.summary}}",
+			Body:  "Server: {{.servers}}",
+		},
+	},
+}
+
+// digestPhrases are the phrases per locale and event making up the summary of a digest
+var digestPhrases = map[string]map[string]string{
+	models.LocaleEnglish: {
+		models.NotificationEventDown:         "%d server(s) down",
+		models.NotificationEventRecovered:    "%d recovered",
+		models.NotificationEventCertExpiry:   "%d certificate(s) expiring",
+		models.NotificationEventFlapping:     "%d flapping",
+		models.NotificationEventAcknowledged: "%d acknowledged",
+		models.NotificationEventResolved:     "%d resolved",
+	},
+	models.LocaleIndonesian: {
+		models.NotificationEventDown:         "%d server down",
+		models.NotificationEventRecovered:    "%d pulih",
+		models.NotificationEventCertExpiry:   "%d sertifikat segera kedaluwarsa",
+		models.NotificationEventFlapping:     "%d flapping",
+		models.NotificationEventAcknowledged: "%d di-acknowledge",
+		models.NotificationEventResolved:     "%d selesai",
+	},
+}
+
+// DefaultTemplate returns the built-in template of an event in a locale,
+// falling back to English for unknown locales
+func DefaultTemplate(event, locale string) (Template, bool) {
+	if tmpl, ok := defaultTemplates[locale][event]; ok {
+		return tmpl, true
+	}
+	tmpl, ok := defaultTemplates[models.LocaleEnglish][event]
+	return tmpl, ok
+}
+
+// DigestSummary summarizes the number of held back alerts per event in a locale,
+// e.g. "7 server(s) down, 2 recovered"
+func DigestSummary(locale string, counts map[string]int) string {
+	phrases, ok := digestPhrases[locale]
+	if !ok {
+		phrases = digestPhrases[models.LocaleEnglish]
+	}
+
+	var parts []string
+	for _, event := range models.TemplateEvents {
+		if counts[event] == 0 {
+			continue
+		}
+		phrase, ok := phrases[event]
+		if !ok {
+			phrase = "%d " + strings.ToLower(event)
+		}
+		parts = append(parts, fmt.Sprintf(phrase, counts[event]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Validate checks the syntax of the title and body templates
+func (t Template) Validate() error {
+	if _, err := parse("title", t.Title); err != nil {
+		return err
+	}
+	_, err := parse("body", t.Body)
+	return err
+}
+
+// Render executes the title and body templates against the data payload of a notification
+func (t Template) Render(data map[string]string) (title, body string, err error) {
+	if title, err = execute("title", t.Title, data); err != nil {
+		return "", "", err
+	}
+	if body, err = execute("body", t.Body, data); err != nil {
+		return "", "", err
+	}
+	return title, body, nil
+}
+
+// parse parses a notification template
+func parse(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// execute parses and executes a notification template
+func execute(name, text string, data map[string]string) (string, error) {
+	tmpl, err := parse(name, text)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, templateData(tmpl, data)); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", name, err)
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// templateData converts a data payload into the data a template is executed against:
+// "true" and "false" become booleans, and the keys the template refers to that are
+// missing from the payload become empty strings rather than "<no value>"
+func templateData(tmpl *template.Template, data map[string]string) map[string]any {
+	values := make(map[string]any, len(data))
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			collectFields(t.Tree.Root, values)
+		}
+	}
+	for key, value := range data {
+		switch value {
+		case "true":
+			values[key] = true
+		case "false":
+			values[key] = false
+		default:
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// collectFields sets the top-level fields a template node refers to, e.g. server_name
+// of {{.server_name}}, to empty strings
+func collectFields(node templateparse.Node, values map[string]any) {
+	switch n := node.(type) {
+	case *templateparse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectFields(child, values)
+		}
+	case *templateparse.ActionNode:
+		collectFields(n.Pipe, values)
+	case *templateparse.IfNode:
+		collectBranchFields(&n.BranchNode, values)
+	case *templateparse.RangeNode:
+		collectBranchFields(&n.BranchNode, values)
+	case *templateparse.WithNode:
+		collectBranchFields(&n.BranchNode, values)
+	case *templateparse.TemplateNode:
+		collectFields(n.Pipe, values)
+	case *templateparse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			collectFields(cmd, values)
+		}
+	case *templateparse.CommandNode:
+		for _, arg := range n.Args {
+			collectFields(arg, values)
+		}
+	case *templateparse.FieldNode:
+		values[n.Ident[0]] = ""
+	}
+}
+
+// collectBranchFields collects the fields of the pipeline and both branches of an if, range or with
+func collectBranchFields(n *templateparse.BranchNode, values map[string]any) {
+	collectFields(n.Pipe, values)
+	collectFields(n.List, values)
+	collectFields(n.ElseList, values)
+}
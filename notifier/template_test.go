@@ -0,0 +1,31 @@
+package notifier
+
+import "testing"
+
+func TestTemplateRender(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		data map[string]string
+		want string
+	}{
+		{name: "payload value", body: "{{.server_name}} is down", data: map[string]string{"server_name": "API Server"}, want: "API Server is down"},
+		{name: "missing key renders empty", body: "[{{.resolve_note}}]", data: map[string]string{}, want: "[]"},
+		{name: "missing key in a condition renders empty", body: "{{if .server_url}}{{.missing}}{{else}}[{{.missing}}]{{end}}", data: map[string]string{}, want: "[]"},
+		{name: "true is a boolean", body: "{{if .flapping}}flapping{{else}}stable{{end}}", data: map[string]string{"flapping": "true"}, want: "flapping"},
+		{name: "false is a boolean", body: "{{if .flapping}}flapping{{else}}stable{{end}}", data: map[string]string{"flapping": "false"}, want: "stable"},
+		{name: "other values stay strings", body: "{{upper .status}} {{duration .downtime_seconds}}", data: map[string]string{"status": "down", "downtime_seconds": "90"}, want: "DOWN 1m30s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, body, err := Template{Title: "Title", Body: tt.body}.Render(tt.data)
+			if err != nil {
+				t.Fatalf("render failed: %v", err)
+			}
+			if body != tt.want {
+				t.Errorf("body = %q, want %q", body, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package notifier
+
+import (
+	"NetGuardServer/models"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Webhook signature headers
+const (
+	SignatureHeader = "X-NetGuard-Signature" // "sha256=" + hex HMAC-SHA256 of "<timestamp>.<body>"
+	TimestampHeader = "X-NetGuard-Timestamp" // Unix seconds, lets receivers reject replays
+)
+
+// WebhookSender POSTs the message as JSON to the channel URL. When the channel has a
+// secret, the request is signed so the receiver can verify it came from NetGuard.
+// Any 2xx response counts as delivered.
+type WebhookSender struct {
+	Client *http.Client
+}
+
+// NewWebhookSender creates a webhook sender; timeouts come from the request context
+func NewWebhookSender() *WebhookSender {
+	return &WebhookSender{Client: &http.Client{}}
+}
+
+// Send delivers a message to a webhook channel
+func (s *WebhookSender) Send(ctx context.Context, channel *models.NotificationChannel, msg Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	headers := map[string]string{}
+	if channel.Secret != "" {
+		timestamp := strconv.FormatInt(msg.Timestamp.Unix(), 10)
+		headers[TimestampHeader] = timestamp
+		headers[SignatureHeader] = "sha256=" + Sign(channel.Secret, timestamp, payload)
+	}
+
+	return postJSON(ctx, s.Client, channel.URL, payload, headers)
+}
+
+// Validate checks the webhook URL
+func (s *WebhookSender) Validate(channel *models.NotificationChannel) error {
+	return validateURL(channel.URL)
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<payload>" with the channel secret
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// postJSON POSTs a JSON payload and fails on non-2xx responses. Errors are stored in the
+// delivery log, so they never contain the URL (a credential for Slack) or the response body.
+func postJSON(ctx context.Context, client *http.Client, target string, payload []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(payload))
+	if err != nil {
+		return errors.New("invalid URL")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "NetGuard-Notifier/1.0")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// validateURL checks that a channel URL is an absolute http(s) URL
+func validateURL(target string) error {
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("channel URL must be an http(s) URL")
+	}
+	return nil
+}
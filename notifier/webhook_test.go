@@ -0,0 +1,104 @@
+package notifier
+
+import (
+	"NetGuardServer/models"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// received is a request captured by a webhook stand-in
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+// webhookStandIn records the requests it receives and answers with the given status
+func webhookStandIn(t *testing.T, status int, requests chan<- received) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- received{header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+		w.Write([]byte("internal detail: token=leaked"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWebhookSender(t *testing.T) {
+	msg := Message{Event: models.NotificationEventDown, Title: "Server DOWN", Body: "API Server is down", Timestamp: time.Unix(1700000000, 0)}
+
+	tests := []struct {
+		name       string
+		secret     string
+		status     int
+		wantErr    bool
+		wantSigned bool
+	}{
+		{name: "signed delivery", secret: "0123456789abcdef0123", status: http.StatusOK, wantSigned: true},
+		{name: "unsigned delivery", status: http.StatusNoContent},
+		{name: "error response", secret: "0123456789abcdef0123", status: http.StatusInternalServerError, wantErr: true, wantSigned: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := make(chan received, 1)
+			server := webhookStandIn(t, tt.status, requests)
+			channel := &models.NotificationChannel{Type: models.ChannelTypeWebhook, URL: server.URL + "/hook", Secret: tt.secret}
+
+			err := NewWebhookSender().Send(context.Background(), channel, msg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && strings.Contains(err.Error(), "leaked") {
+				t.Errorf("error %q echoes the response body", err)
+			}
+
+			req := <-requests
+			var payload Message
+			if err := json.Unmarshal(req.body, &payload); err != nil || payload.Title != msg.Title {
+				t.Errorf("payload = %s, want the message", req.body)
+			}
+
+			signature := req.header.Get(SignatureHeader)
+			if !tt.wantSigned {
+				if signature != "" {
+					t.Errorf("unexpected signature %q", signature)
+				}
+				return
+			}
+			// Verify the signature like a receiver would
+			timestamp := req.header.Get(TimestampHeader)
+			mac := hmac.New(sha256.New, []byte(tt.secret))
+			mac.Write([]byte(timestamp + "."))
+			mac.Write(req.body)
+			want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+			if timestamp != "1700000000" || !hmac.Equal([]byte(signature), []byte(want)) {
+				t.Errorf("signature %q at %s, want %q at 1700000000", signature, timestamp, want)
+			}
+		})
+	}
+}
+
+func TestWebhookSenderUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	channel := &models.NotificationChannel{Type: models.ChannelTypeWebhook, URL: server.URL + "/services/T000/B000/secret-path"}
+
+	err := NewWebhookSender().Send(context.Background(), channel, Message{Title: "Test"})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if strings.Contains(err.Error(), "secret-path") {
+		t.Errorf("error %q contains the channel URL", err)
+	}
+}
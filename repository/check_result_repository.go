@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/models"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CheckResultRepository defines the interface for check result (time-series) data operations
+type CheckResultRepository interface {
+	Create(result *models.CheckResult) error
+	FindByServerID(serverID uuid.UUID, from, to time.Time) ([]models.CheckResult, error)
+	FindReporters(serverID uuid.UUID, status string, since time.Time) ([]uuid.UUID, error)
+	FindRollups(serverID uuid.UUID, resolution string, from, to time.Time) ([]models.CheckRollup, error)
+	Rollup(resolution string, from, to, prunedBefore time.Time) error
+	DeleteOlderThan(before time.Time) (int64, error)
+}
+
+// checkResultRepository implements CheckResultRepository
+type checkResultRepository struct {
+	db *gorm.DB
+}
+
+// NewCheckResultRepository creates a new check result repository instance
+func NewCheckResultRepository() CheckResultRepository {
+	return &checkResultRepository{
+		db: config.AppConfig.DB,
+	}
+}
+
+// Create creates a new check result
+func (r *checkResultRepository) Create(result *models.CheckResult) error {
+	return r.db.Create(result).Error
+}
+
+// FindByServerID finds raw check results of a server within [from, to)
+func (r *checkResultRepository) FindByServerID(serverID uuid.UUID, from, to time.Time) ([]models.CheckResult, error) {
+	var results []models.CheckResult
+	err := r.db.Where("server_id = ? AND checked_at >= ? AND checked_at < ?", serverID, from, to).
+		Order("checked_at ASC").
+		Find(&results).Error
+	return results, err
+}
+
+// FindReporters finds the distinct reporters that reported the given status for a server since a time
+func (r *checkResultRepository) FindReporters(serverID uuid.UUID, status string, since time.Time) ([]uuid.UUID, error) {
+	var reporters []uuid.UUID
+	err := r.db.Model(&models.CheckResult{}).
+		Where("server_id = ? AND status = ? AND checked_at >= ?", serverID, status, since).
+		Distinct().
+		Pluck("reported_by", &reporters).Error
+	return reporters, err
+}
+
+// FindRollups finds rollups of a server for a resolution with buckets starting within [from, to)
+func (r *checkResultRepository) FindRollups(serverID uuid.UUID, resolution string, from, to time.Time) ([]models.CheckRollup, error) {
+	var rollups []models.CheckRollup
+	err := r.db.Where("server_id = ? AND resolution = ? AND bucket_start >= ? AND bucket_start < ?", serverID, resolution, from, to).
+		Order("bucket_start ASC").
+		Find(&rollups).Error
+	return rollups, err
+}
+
+// Rollup (re)computes rollups of the given resolution for all buckets touching [from, to).
+// from is truncated to the start of its bucket so buckets are always recomputed in full,
+// and existing buckets are overwritten, so the current (partial) bucket can be refreshed safely.
+// Raw check results before prunedBefore may already be pruned, so buckets starting before it
+// are only inserted when missing and never overwritten with a partial recomputation.
+func (r *checkResultRepository) Rollup(resolution string, from, to, prunedBefore time.Time) error {
+	if resolution != models.ResolutionHour && resolution != models.ResolutionDay {
+		return fmt.Errorf("invalid rollup resolution: %s", resolution)
+	}
+
+	// Response time statistics only consider checks that actually measured a response time
+	query := fmt.Sprintf(`
+		INSERT INTO check_rollups (
+			server_id, resolution, bucket_start, count, up_count,
+			min_response_time, avg_response_time, max_response_time, p95_response_time, up_ratio
+		)
+		SELECT
+			server_id,
+			'%[1]s',
+			date_trunc('%[1]s', checked_at),
+			COUNT(*),
+			COUNT(*) FILTER (WHERE status = 'UP'),
+			COALESCE(MIN(response_time) FILTER (WHERE response_time > 0), 0),
+			COALESCE(AVG(response_time) FILTER (WHERE response_time > 0), 0),
+			COALESCE(MAX(response_time) FILTER (WHERE response_time > 0), 0),
+			COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY response_time) FILTER (WHERE response_time > 0), 0),
+			COALESCE(
+				COUNT(*) FILTER (WHERE status = 'UP')::float
+				/ NULLIF(COUNT(*) FILTER (WHERE status <> 'UNKNOWN'), 0),
+				0
+			)
+		FROM check_results
+		WHERE checked_at >= date_trunc('%[1]s', ?::timestamptz) AND checked_at < ?
+		GROUP BY 1, 3
+		ON CONFLICT (server_id, resolution, bucket_start) DO UPDATE SET
+			count = EXCLUDED.count,
+			up_count = EXCLUDED.up_count,
+			min_response_time = EXCLUDED.min_response_time,
+			avg_response_time = EXCLUDED.avg_response_time,
+			max_response_time = EXCLUDED.max_response_time,
+			p95_response_time = EXCLUDED.p95_response_time,
+			up_ratio = EXCLUDED.up_ratio
+		WHERE check_rollups.bucket_start >= ?
+	`, resolution)
+
+	return r.db.Exec(query, from, to, prunedBefore).Error
+}
+
+// DeleteOlderThan deletes raw check results older than the given time
+func (r *checkResultRepository) DeleteOlderThan(before time.Time) (int64, error) {
+	result := r.db.Where("checked_at < ?", before).Delete(&models.CheckResult{})
+	return result.RowsAffected, result.Error
+}
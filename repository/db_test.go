@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/models"
+	"os"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB connects the repositories to the PostgreSQL database of TEST_DATABASE_URL
+// and migrates the schema. Tests using it are skipped when the variable is not set.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set, skipping database test")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	if err := db.AutoMigrate(
+		&models.User{},
+		&models.Server{},
+		&models.ServerDownHistory{},
+		&models.HistoryReporter{},
+		&models.NotificationOutbox{},
+		&models.NotificationAttempt{},
+		&models.RefreshToken{},
+		&models.RevokedToken{},
+	); err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+
+	config.AppConfig.DB = db
+	return db
+}
+
+// createTestServer stores a server and deletes it with its records when the test ends
+func createTestServer(t *testing.T, db *gorm.DB) *models.Server {
+	t.Helper()
+
+	server := &models.Server{Name: "Test Server", URL: "https://test.example.com"}
+	if err := db.Create(server).Error; err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Where("server_id = ?", server.ID).Delete(&models.NotificationOutbox{})
+		db.Where("history_id IN (?)", db.Model(&models.ServerDownHistory{}).Select("id").Where("server_id = ?", server.ID)).
+			Delete(&models.HistoryReporter{})
+		db.Where("server_id = ?", server.ID).Delete(&models.ServerDownHistory{})
+		db.Delete(server)
+	})
+	return server
+}
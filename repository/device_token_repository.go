@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DeviceTokenRepository defines the interface for FCM device token data operations
+type DeviceTokenRepository interface {
+	Upsert(token *models.DeviceToken) error
+	FindByUserID(userID uuid.UUID) ([]models.DeviceToken, error)
+	FindByUserIDs(userIDs []uuid.UUID) ([]models.DeviceToken, error)
+	DeleteByToken(userID uuid.UUID, token string) (bool, error)
+	DeleteTokens(tokens []string) error
+}
+
+// deviceTokenRepository implements DeviceTokenRepository
+type deviceTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewDeviceTokenRepository creates a new device token repository instance
+func NewDeviceTokenRepository() DeviceTokenRepository {
+	return &deviceTokenRepository{
+		db: config.AppConfig.DB,
+	}
+}
+
+// Upsert registers a device token, or moves an already registered token to the user
+// and refreshes its platform and app version
+func (r *deviceTokenRepository) Upsert(token *models.DeviceToken) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "platform", "app_version", "updated_at"}),
+	}).Create(token).Error
+	if err != nil {
+		return err
+	}
+	// Reload to get the ID of an existing row
+	var stored models.DeviceToken
+	if err := r.db.Where("token = ?", token.Token).First(&stored).Error; err != nil {
+		return err
+	}
+	*token = stored
+	return nil
+}
+
+// FindByUserID finds the device tokens of a user, most recently registered first
+func (r *deviceTokenRepository) FindByUserID(userID uuid.UUID) ([]models.DeviceToken, error) {
+	var tokens []models.DeviceToken
+	err := r.db.Where("user_id = ?", userID).Order("updated_at DESC").Find(&tokens).Error
+	return tokens, err
+}
+
+// FindByUserIDs finds the device tokens of several users
+func (r *deviceTokenRepository) FindByUserIDs(userIDs []uuid.UUID) ([]models.DeviceToken, error) {
+	var tokens []models.DeviceToken
+	if len(userIDs) == 0 {
+		return tokens, nil
+	}
+	err := r.db.Where("user_id IN ?", userIDs).Find(&tokens).Error
+	return tokens, err
+}
+
+// DeleteByToken unregisters a device token of a user; it reports whether the token was registered
+func (r *deviceTokenRepository) DeleteByToken(userID uuid.UUID, token string) (bool, error) {
+	result := r.db.Where("user_id = ? AND token = ?", userID, token).Delete(&models.DeviceToken{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteTokens removes device tokens FCM no longer accepts, whoever they belong to
+func (r *deviceTokenRepository) DeleteTokens(tokens []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	return r.db.Where("token IN ?", tokens).Delete(&models.DeviceToken{}).Error
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EscalationRepository defines the interface for escalation policy data operations
+type EscalationRepository interface {
+	Create(policy *models.EscalationPolicy) error
+	FindByID(id uuid.UUID) (*models.EscalationPolicy, error)
+	FindAll() ([]models.EscalationPolicy, error)
+	Update(policy *models.EscalationPolicy, levels []models.EscalationLevel) error
+	Delete(id uuid.UUID) error
+}
+
+// escalationRepository implements EscalationRepository
+type escalationRepository struct {
+	db *gorm.DB
+}
+
+// NewEscalationRepository creates a new escalation policy repository instance
+func NewEscalationRepository() EscalationRepository {
+	return &escalationRepository{
+		db: config.AppConfig.DB,
+	}
+}
+
+// Create creates an escalation policy with its levels
+func (r *escalationRepository) Create(policy *models.EscalationPolicy) error {
+	return r.db.Create(policy).Error
+}
+
+// FindByID finds an escalation policy by ID with its levels in order
+func (r *escalationRepository) FindByID(id uuid.UUID) (*models.EscalationPolicy, error) {
+	var policy models.EscalationPolicy
+	err := r.db.Preload("Levels", func(db *gorm.DB) *gorm.DB {
+		return db.Order("level ASC")
+	}).Where("id = ?", id).First(&policy).Error
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// FindAll finds all escalation policies with their levels in order
+func (r *escalationRepository) FindAll() ([]models.EscalationPolicy, error) {
+	var policies []models.EscalationPolicy
+	err := r.db.Preload("Levels", func(db *gorm.DB) *gorm.DB {
+		return db.Order("level ASC")
+	}).Order("name ASC").Find(&policies).Error
+	return policies, err
+}
+
+// Update updates an escalation policy; levels replaces its levels unless nil
+func (r *escalationRepository) Update(policy *models.EscalationPolicy, levels []models.EscalationLevel) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(policy).Error; err != nil {
+			return err
+		}
+		if levels == nil {
+			return nil
+		}
+		if err := tx.Where("policy_id = ?", policy.ID).Delete(&models.EscalationLevel{}).Error; err != nil {
+			return err
+		}
+		for i := range levels {
+			levels[i].PolicyID = policy.ID
+		}
+		return tx.Create(&levels).Error
+	})
+}
+
+// Delete deletes an escalation policy by ID and detaches it from its servers
+// (levels are removed by cascade)
+func (r *escalationRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Server{}).Where("escalation_policy_id = ?", id).
+			Update("escalation_policy_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.EscalationPolicy{}, id).Error
+	})
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// HeartbeatRepository defines the interface for heartbeat ping data operations
+type HeartbeatRepository interface {
+	Create(ping *models.HeartbeatPing) error
+	FindByServerID(serverID uuid.UUID, historyID *uuid.UUID, limit int) ([]models.HeartbeatPing, error)
+}
+
+// heartbeatRepository implements HeartbeatRepository
+type heartbeatRepository struct {
+	db *gorm.DB
+}
+
+// NewHeartbeatRepository creates a new heartbeat repository instance
+func NewHeartbeatRepository() HeartbeatRepository {
+	return &heartbeatRepository{
+		db: config.AppConfig.DB,
+	}
+}
+
+// Create creates a new heartbeat ping
+func (r *heartbeatRepository) Create(ping *models.HeartbeatPing) error {
+	return r.db.Create(ping).Error
+}
+
+// FindByServerID finds the latest pings of a server, optionally only those linked to an incident
+func (r *heartbeatRepository) FindByServerID(serverID uuid.UUID, historyID *uuid.UUID, limit int) ([]models.HeartbeatPing, error) {
+	var pings []models.HeartbeatPing
+	query := r.db.Where("server_id = ?", serverID)
+	if historyID != nil {
+		query = query.Where("history_id = ?", *historyID)
+	}
+	err := query.Order("received_at DESC").Limit(limit).Find(&pings).Error
+	return pings, err
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// HistoryEventRepository defines the interface for incident timeline data operations.
+// The timeline is append-only: events are never updated or deleted.
+type HistoryEventRepository interface {
+	Create(event *models.HistoryEvent) error
+	FindByHistoryID(historyID uuid.UUID) ([]models.HistoryEvent, error)
+}
+
+// historyEventRepository implements HistoryEventRepository
+type historyEventRepository struct {
+	db *gorm.DB
+}
+
+// NewHistoryEventRepository creates a new history event repository instance
+func NewHistoryEventRepository() HistoryEventRepository {
+	return &historyEventRepository{
+		db: config.AppConfig.DB,
+	}
+}
+
+// Create appends an event to the timeline of a history record
+func (r *historyEventRepository) Create(event *models.HistoryEvent) error {
+	return r.db.Create(event).Error
+}
+
+// FindByHistoryID finds the timeline of a history record, oldest first
+func (r *historyEventRepository) FindByHistoryID(historyID uuid.UUID) ([]models.HistoryEvent, error) {
+	var events []models.HistoryEvent
+	err := r.db.Where("history_id = ?", historyID).Order("created_at ASC").Find(&events).Error
+	return events, err
+}
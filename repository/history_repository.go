@@ -4,18 +4,31 @@ import (
 	"NetGuardServer/config"
 	"NetGuardServer/models"
 
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // HistoryRepository defines the interface for history data operations
 type HistoryRepository interface {
 	Create(history *models.ServerDownHistory) error
+	CreateOrAttach(history *models.ServerDownHistory, reportedBy uuid.UUID, notifications []models.NotificationOutbox) (*models.ServerDownHistory, bool, error)
 	FindByID(id uuid.UUID) (*models.ServerDownHistory, error)
 	FindByServerID(serverID uuid.UUID) ([]models.ServerDownHistory, error)
+	FindOpenByServerID(serverID uuid.UUID) ([]models.ServerDownHistory, error)
+	FindOpenByServerIDs(serverIDs []uuid.UUID) ([]models.ServerDownHistory, error)
 	FindAll(limit int) ([]models.ServerDownHistory, error)
-	Update(history *models.ServerDownHistory) error
-	GetMonthlyReport(year, month int) ([]map[string]interface{}, error)
+	CountStateChanges(serverID uuid.UUID, since time.Time) (int64, error)
+	Acknowledge(history *models.ServerDownHistory) (bool, error)
+	Assign(history *models.ServerDownHistory) (bool, error)
+	Resolve(history *models.ServerDownHistory) (bool, error)
+	AddResolveNote(history *models.ServerDownHistory) (bool, error)
+	Promote(history *models.ServerDownHistory) (bool, error)
+	FindByPeriod(from, to time.Time) ([]models.ServerDownHistory, error)
+	FindDueEscalations(now time.Time) ([]models.ServerDownHistory, error)
+	UpdateEscalation(history *models.ServerDownHistory) (bool, error)
 }
 
 // historyRepository implements HistoryRepository
@@ -35,55 +48,238 @@ func (r *historyRepository) Create(history *models.ServerDownHistory) error {
 	return r.db.Create(history).Error
 }
 
+// CreateOrAttach attaches a DOWN report to the open history record of the server,
+// or creates the given record if the server has none. The server row is locked for
+// the duration of the transaction so concurrent reports cannot open two records.
+// The notifications are added to the outbox in the same transaction, only if the record
+// is created. It returns the open record and whether it was newly created.
+func (r *historyRepository) CreateOrAttach(history *models.ServerDownHistory, reportedBy uuid.UUID, notifications []models.NotificationOutbox) (*models.ServerDownHistory, bool, error) {
+	var result models.ServerDownHistory
+	created := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var server models.Server
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", history.ServerID).First(&server).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+
+		err := tx.Where("server_id = ? AND resolved_at IS NULL", history.ServerID).
+			Order("timestamp ASC").First(&result).Error
+		if err == gorm.ErrRecordNotFound {
+			history.LastReportedAt = &now
+			if err := tx.Create(history).Error; err != nil {
+				return err
+			}
+			created = true
+			result = *history
+
+			reporter := models.HistoryReporter{
+				HistoryID:       history.ID,
+				UserID:          reportedBy,
+				FirstReportedAt: now,
+				LastReportedAt:  now,
+			}
+			if err := tx.Create(&reporter).Error; err != nil {
+				return err
+			}
+
+			if len(notifications) == 0 {
+				return nil
+			}
+			for i := range notifications {
+				notifications[i].HistoryID = &history.ID
+			}
+			return tx.Create(&notifications).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		// Known reporter: bump its counter, otherwise add it to the list
+		res := tx.Model(&models.HistoryReporter{}).
+			Where("history_id = ? AND user_id = ?", result.ID, reportedBy).
+			Updates(map[string]interface{}{
+				"report_count":     gorm.Expr("report_count + 1"),
+				"last_reported_at": now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			reporter := models.HistoryReporter{
+				HistoryID:       result.ID,
+				UserID:          reportedBy,
+				FirstReportedAt: now,
+				LastReportedAt:  now,
+			}
+			if err := tx.Create(&reporter).Error; err != nil {
+				return err
+			}
+			result.ReporterCount++
+		}
+
+		result.LastReportedAt = &now
+		return tx.Model(&result).Updates(map[string]interface{}{
+			"reporter_count":   result.ReporterCount,
+			"last_reported_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &result, created, nil
+}
+
 // FindByID finds a history record by ID
 func (r *historyRepository) FindByID(id uuid.UUID) (*models.ServerDownHistory, error) {
 	var history models.ServerDownHistory
-	err := r.db.Where("id = ?", id).First(&history).Error
+	err := r.db.Preload("Reporters").Where("id = ?", id).First(&history).Error
 	if err != nil {
 		return nil, err
 	}
 	return &history, nil
 }
 
-// Update updates a history record
-func (r *historyRepository) Update(history *models.ServerDownHistory) error {
-	return r.db.Save(history).Error
+// The lifecycle updates below only write their own columns, so they never overwrite the
+// reporter counters of CreateOrAttach or the schedule of UpdateEscalation. Each one is guarded
+// by the state it expects and reports whether the record was updated.
+
+// Acknowledge records the acknowledgement of a record, unless it was acknowledged or resolved in the meantime
+func (r *historyRepository) Acknowledge(history *models.ServerDownHistory) (bool, error) {
+	res := r.db.Model(&models.ServerDownHistory{}).
+		Where("id = ? AND acknowledged_at IS NULL AND resolved_at IS NULL", history.ID).
+		Updates(map[string]interface{}{
+			"status":             history.Status,
+			"acknowledged_by":    history.AcknowledgedBy,
+			"acknowledged_at":    history.AcknowledgedAt,
+			"ack_seconds":        history.AckSeconds,
+			"next_escalation_at": history.NextEscalationAt,
+		})
+	return res.RowsAffected > 0, res.Error
 }
 
-// GetMonthlyReport gets monthly server down statistics
-func (r *historyRepository) GetMonthlyReport(year, month int) ([]map[string]interface{}, error) {
-	var results []map[string]interface{}
+// Assign records the assignee of a record, unless it was resolved in the meantime
+func (r *historyRepository) Assign(history *models.ServerDownHistory) (bool, error) {
+	res := r.db.Model(&models.ServerDownHistory{}).
+		Where("id = ? AND resolved_at IS NULL", history.ID).
+		Updates(map[string]interface{}{
+			"assignee_id": history.AssigneeID,
+			"assigned_by": history.AssignedBy,
+			"assigned_at": history.AssignedAt,
+		})
+	return res.RowsAffected > 0, res.Error
+}
 
-	query := `
-		SELECT
-			server_id,
-			server_name,
-			url,
-			COUNT(*) as down_count,
-			COUNT(CASE WHEN resolved_at IS NOT NULL THEN 1 END) as resolved_count,
-			AVG(EXTRACT(EPOCH FROM (COALESCE(resolved_at, CURRENT_TIMESTAMP) - timestamp))) as avg_resolution_time
-		FROM server_down_histories
-		WHERE EXTRACT(YEAR FROM timestamp) = $1
-		AND EXTRACT(MONTH FROM timestamp) = $2
-		AND status = 'DOWN'
-		GROUP BY server_id, server_name, url
-		ORDER BY down_count DESC
-	`
+// Resolve records the resolution of a record, unless it was resolved in the meantime
+func (r *historyRepository) Resolve(history *models.ServerDownHistory) (bool, error) {
+	res := r.db.Model(&models.ServerDownHistory{}).
+		Where("id = ? AND resolved_at IS NULL", history.ID).
+		Updates(map[string]interface{}{
+			"status":              history.Status,
+			"resolved_by":         history.ResolvedBy,
+			"resolved_at":         history.ResolvedAt,
+			"resolve_note":        history.ResolveNote,
+			"auto_resolved":       history.AutoResolved,
+			"downtime_seconds":    history.DowntimeSeconds,
+			"maintenance_seconds": history.MaintenanceSeconds,
+			"next_escalation_at":  history.NextEscalationAt,
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+// AddResolveNote attaches the resolve note of a user to an auto-resolved record, unless it already has one
+func (r *historyRepository) AddResolveNote(history *models.ServerDownHistory) (bool, error) {
+	res := r.db.Model(&models.ServerDownHistory{}).
+		Where("id = ? AND auto_resolved = ? AND COALESCE(resolve_note, '') = ''", history.ID, true).
+		Updates(map[string]interface{}{
+			"resolved_by":  history.ResolvedBy,
+			"resolve_note": history.ResolveNote,
+		})
+	return res.RowsAffected > 0, res.Error
+}
 
-	err := r.db.Raw(query, year, month).Scan(&results).Error
-	return results, err
+// Promote detaches an open impacted record from its parent incident and turns it into
+// a DOWN incident; an acknowledged record keeps its status
+func (r *historyRepository) Promote(history *models.ServerDownHistory) (bool, error) {
+	res := r.db.Model(&models.ServerDownHistory{}).
+		Where("id = ? AND resolved_at IS NULL", history.ID).
+		Updates(map[string]interface{}{
+			"status":    gorm.Expr("CASE WHEN status = ? THEN ? ELSE status END", models.HistoryStatusImpacted, models.HistoryStatusDown),
+			"parent_id": nil,
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+// FindByPeriod finds history records opened within [from, to), oldest first
+func (r *historyRepository) FindByPeriod(from, to time.Time) ([]models.ServerDownHistory, error) {
+	var histories []models.ServerDownHistory
+	err := r.db.Where("timestamp >= ? AND timestamp < ?", from, to).Order("timestamp ASC").Find(&histories).Error
+	return histories, err
 }
 
 // FindByServerID finds history records by server ID
 func (r *historyRepository) FindByServerID(serverID uuid.UUID) ([]models.ServerDownHistory, error) {
 	var histories []models.ServerDownHistory
-	err := r.db.Where("server_id = ?", serverID).Order("timestamp DESC").Find(&histories).Error
+	err := r.db.Preload("Reporters").Where("server_id = ?", serverID).Order("timestamp DESC").Find(&histories).Error
+	return histories, err
+}
+
+// FindOpenByServerID finds unresolved history records of a server, oldest first
+func (r *historyRepository) FindOpenByServerID(serverID uuid.UUID) ([]models.ServerDownHistory, error) {
+	var histories []models.ServerDownHistory
+	err := r.db.Where("server_id = ? AND resolved_at IS NULL", serverID).Order("timestamp ASC").Find(&histories).Error
+	return histories, err
+}
+
+// FindOpenByServerIDs finds unresolved history records of several servers, oldest first
+func (r *historyRepository) FindOpenByServerIDs(serverIDs []uuid.UUID) ([]models.ServerDownHistory, error) {
+	var histories []models.ServerDownHistory
+	err := r.db.Where("server_id IN ? AND resolved_at IS NULL", serverIDs).Order("timestamp ASC").Find(&histories).Error
 	return histories, err
 }
 
 // FindAll finds all history records with limit
 func (r *historyRepository) FindAll(limit int) ([]models.ServerDownHistory, error) {
 	var histories []models.ServerDownHistory
-	err := r.db.Order("timestamp DESC").Limit(limit).Find(&histories).Error
+	err := r.db.Preload("Reporters").Order("timestamp DESC").Limit(limit).Find(&histories).Error
 	return histories, err
-}
\ No newline at end of file
+}
+
+// CountStateChanges counts the DOWN/UP transitions of a server since a time:
+// every incident opened and every incident resolved counts as one change
+func (r *historyRepository) CountStateChanges(serverID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Raw(`
+		SELECT
+			COUNT(*) FILTER (WHERE timestamp >= @since) +
+			COUNT(*) FILTER (WHERE resolved_at >= @since)
+		FROM server_down_histories
+		WHERE server_id = @server AND (timestamp >= @since OR resolved_at >= @since)
+	`, map[string]interface{}{"server": serverID, "since": since}).Scan(&count).Error
+	return count, err
+}
+
+// FindDueEscalations finds the open, unacknowledged records whose next escalation level is due
+func (r *historyRepository) FindDueEscalations(now time.Time) ([]models.ServerDownHistory, error) {
+	var histories []models.ServerDownHistory
+	err := r.db.Where("next_escalation_at <= ? AND acknowledged_at IS NULL AND resolved_at IS NULL", now).
+		Order("next_escalation_at ASC").Find(&histories).Error
+	return histories, err
+}
+
+// UpdateEscalation updates only the escalation schedule of a record, unless it was acknowledged
+// or resolved in the meantime. It reports whether the record was updated.
+func (r *historyRepository) UpdateEscalation(history *models.ServerDownHistory) (bool, error) {
+	res := r.db.Model(&models.ServerDownHistory{}).
+		Where("id = ? AND acknowledged_at IS NULL AND resolved_at IS NULL", history.ID).
+		Updates(map[string]interface{}{
+			"escalation_policy_id": history.EscalationPolicyID,
+			"escalation_level":     history.EscalationLevel,
+			"next_escalation_at":   history.NextEscalationAt,
+		})
+	return res.RowsAffected > 0, res.Error
+}
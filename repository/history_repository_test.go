@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"NetGuardServer/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCreateOrAttach(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewHistoryRepository()
+	alice, bob := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name          string
+		reporters     []uuid.UUID
+		wantCreated   []bool
+		wantReporters int
+		wantReports   map[uuid.UUID]int
+	}{
+		{
+			name:          "first report opens the incident",
+			reporters:     []uuid.UUID{alice},
+			wantCreated:   []bool{true},
+			wantReporters: 1,
+			wantReports:   map[uuid.UUID]int{alice: 1},
+		},
+		{
+			name:          "repeated reports of one user are counted",
+			reporters:     []uuid.UUID{alice, alice, alice},
+			wantCreated:   []bool{true, false, false},
+			wantReporters: 1,
+			wantReports:   map[uuid.UUID]int{alice: 3},
+		},
+		{
+			name:          "reports of other users join the incident",
+			reporters:     []uuid.UUID{alice, bob, alice},
+			wantCreated:   []bool{true, false, false},
+			wantReporters: 2,
+			wantReports:   map[uuid.UUID]int{alice: 2, bob: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := createTestServer(t, db)
+
+			var incidentID uuid.UUID
+			for i, reporter := range tt.reporters {
+				// Every report offers a notification, it is only queued with a new incident
+				notifications := []models.NotificationOutbox{{
+					ServerID: &server.ID,
+					Event:    models.NotificationEventDown,
+					Target:   models.OutboxTargetPush,
+					Title:    "Server Down",
+				}}
+				history := &models.ServerDownHistory{
+					ServerID:   server.ID,
+					ServerName: server.Name,
+					URL:        server.URL,
+					Status:     models.HistoryStatusDown,
+					CreatedBy:  reporter,
+				}
+
+				open, created, err := repo.CreateOrAttach(history, reporter, notifications)
+				if err != nil {
+					t.Fatalf("report %d: %v", i+1, err)
+				}
+				if created != tt.wantCreated[i] {
+					t.Errorf("report %d: created = %v, want %v", i+1, created, tt.wantCreated[i])
+				}
+				if i == 0 {
+					incidentID = open.ID
+				} else if open.ID != incidentID {
+					t.Errorf("report %d: attached to %s, want incident %s", i+1, open.ID, incidentID)
+				}
+			}
+
+			stored, err := repo.FindByID(incidentID)
+			if err != nil {
+				t.Fatalf("failed to find incident: %v", err)
+			}
+			if stored.ReporterCount != tt.wantReporters || len(stored.Reporters) != tt.wantReporters {
+				t.Errorf("reporter count = %d with %d reporters, want %d", stored.ReporterCount, len(stored.Reporters), tt.wantReporters)
+			}
+			for _, reporter := range stored.Reporters {
+				if reporter.ReportCount != tt.wantReports[reporter.UserID] {
+					t.Errorf("reporter %s: report count = %d, want %d", reporter.UserID, reporter.ReportCount, tt.wantReports[reporter.UserID])
+				}
+			}
+
+			var incidents, queued int64
+			db.Model(&models.ServerDownHistory{}).Where("server_id = ?", server.ID).Count(&incidents)
+			db.Model(&models.NotificationOutbox{}).Where("server_id = ?", server.ID).Count(&queued)
+			if incidents != 1 || queued != 1 {
+				t.Errorf("got %d incidents and %d queued notifications, want 1 and 1", incidents, queued)
+			}
+		})
+	}
+}
+
+func TestLifecycleUpdates(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewHistoryRepository()
+	alice, bob := uuid.New(), uuid.New()
+
+	// open reports an incident of alice and returns a copy loaded before bob reported it too
+	// and the escalation reached level 2
+	open := func(t *testing.T) *models.ServerDownHistory {
+		server := createTestServer(t, db)
+		history := &models.ServerDownHistory{ServerID: server.ID, ServerName: server.Name, URL: server.URL, Status: models.HistoryStatusDown, CreatedBy: alice}
+		opened, _, err := repo.CreateOrAttach(history, alice, nil)
+		if err != nil {
+			t.Fatalf("failed to open incident: %v", err)
+		}
+		stale, _ := repo.FindByID(opened.ID)
+
+		if _, _, err := repo.CreateOrAttach(&models.ServerDownHistory{ServerID: server.ID}, bob, nil); err != nil {
+			t.Fatalf("failed to attach report: %v", err)
+		}
+		escalated := *opened
+		escalated.EscalationLevel = 2
+		if _, err := repo.UpdateEscalation(&escalated); err != nil {
+			t.Fatalf("failed to escalate: %v", err)
+		}
+		return stale
+	}
+
+	tests := []struct {
+		name   string
+		update func(history *models.ServerDownHistory) (bool, error)
+		want   []bool
+	}{
+		{
+			name: "acknowledge",
+			update: func(history *models.ServerDownHistory) (bool, error) {
+				history.Acknowledge(alice, time.Now())
+				return repo.Acknowledge(history)
+			},
+			want: []bool{true, false},
+		},
+		{
+			name: "assign",
+			update: func(history *models.ServerDownHistory) (bool, error) {
+				now := time.Now()
+				history.AssigneeID, history.AssignedBy, history.AssignedAt = &bob, &alice, &now
+				return repo.Assign(history)
+			},
+			want: []bool{true, true},
+		},
+		{
+			name: "resolve",
+			update: func(history *models.ServerDownHistory) (bool, error) {
+				history.Resolve(alice, time.Now())
+				return repo.Resolve(history)
+			},
+			want: []bool{true, false},
+		},
+		{
+			name:   "promote",
+			update: repo.Promote,
+			want:   []bool{true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stale := open(t)
+
+			for i, want := range tt.want {
+				updated, err := tt.update(stale)
+				if err != nil {
+					t.Fatalf("update %d: %v", i+1, err)
+				}
+				if updated != want {
+					t.Errorf("update %d: updated = %v, want %v", i+1, updated, want)
+				}
+			}
+
+			stored, err := repo.FindByID(stale.ID)
+			if err != nil {
+				t.Fatalf("failed to find incident: %v", err)
+			}
+			if stored.ReporterCount != 2 || stored.EscalationLevel != 2 {
+				t.Errorf("reporter count %d and escalation level %d, want 2 and 2", stored.ReporterCount, stored.EscalationLevel)
+			}
+		})
+	}
+}
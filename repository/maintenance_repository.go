@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MaintenanceRepository defines the interface for maintenance window data operations
+type MaintenanceRepository interface {
+	Create(window *models.MaintenanceWindow, serverIDs []uuid.UUID) error
+	FindByID(id uuid.UUID) (*models.MaintenanceWindow, error)
+	FindAll(serverID *uuid.UUID) ([]models.MaintenanceWindow, error)
+	FindByServerID(serverID uuid.UUID) ([]models.MaintenanceWindow, error)
+	Update(window *models.MaintenanceWindow, serverIDs []uuid.UUID) error
+	Delete(id uuid.UUID) error
+}
+
+// maintenanceRepository implements MaintenanceRepository
+type maintenanceRepository struct {
+	db *gorm.DB
+}
+
+// NewMaintenanceRepository creates a new maintenance window repository instance
+func NewMaintenanceRepository() MaintenanceRepository {
+	return &maintenanceRepository{
+		db: config.AppConfig.DB,
+	}
+}
+
+// Create creates a maintenance window attached to the given servers
+func (r *maintenanceRepository) Create(window *models.MaintenanceWindow, serverIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(window).Error; err != nil {
+			return err
+		}
+		return replaceMaintenanceServers(tx, window.ID, serverIDs)
+	})
+}
+
+// FindByID finds a maintenance window by ID with its servers
+func (r *maintenanceRepository) FindByID(id uuid.UUID) (*models.MaintenanceWindow, error) {
+	var window models.MaintenanceWindow
+	err := r.db.Preload("Servers").Where("id = ?", id).First(&window).Error
+	if err != nil {
+		return nil, err
+	}
+	return &window, nil
+}
+
+// FindAll finds all maintenance windows with their servers, optionally only those of a server
+func (r *maintenanceRepository) FindAll(serverID *uuid.UUID) ([]models.MaintenanceWindow, error) {
+	var windows []models.MaintenanceWindow
+	query := r.db.Preload("Servers")
+	if serverID != nil {
+		query = query.Where("id IN (?)", r.db.Table("maintenance_window_servers").
+			Select("maintenance_window_id").Where("server_id = ?", *serverID))
+	}
+	err := query.Order("starts_at DESC").Find(&windows).Error
+	return windows, err
+}
+
+// FindByServerID finds the maintenance windows of a server, without loading their servers
+func (r *maintenanceRepository) FindByServerID(serverID uuid.UUID) ([]models.MaintenanceWindow, error) {
+	var windows []models.MaintenanceWindow
+	err := r.db.Joins("JOIN maintenance_window_servers ON maintenance_window_servers.maintenance_window_id = maintenance_windows.id").
+		Where("maintenance_window_servers.server_id = ?", serverID).
+		Find(&windows).Error
+	return windows, err
+}
+
+// Update updates a maintenance window; serverIDs replaces its servers unless nil
+func (r *maintenanceRepository) Update(window *models.MaintenanceWindow, serverIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(window).Error; err != nil {
+			return err
+		}
+		if serverIDs == nil {
+			return nil
+		}
+		return replaceMaintenanceServers(tx, window.ID, serverIDs)
+	})
+}
+
+// Delete deletes a maintenance window by ID (server links are removed by cascade)
+func (r *maintenanceRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.MaintenanceWindow{}, id).Error
+}
+
+// replaceMaintenanceServers sets the servers a maintenance window applies to
+func replaceMaintenanceServers(tx *gorm.DB, windowID uuid.UUID, serverIDs []uuid.UUID) error {
+	if err := tx.Exec("DELETE FROM maintenance_window_servers WHERE maintenance_window_id = ?", windowID).Error; err != nil {
+		return err
+	}
+
+	rows := make([]map[string]interface{}, 0, len(serverIDs))
+	for _, serverID := range serverIDs {
+		rows = append(rows, map[string]interface{}{
+			"maintenance_window_id": windowID,
+			"server_id":             serverID,
+		})
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return tx.Table("maintenance_window_servers").Clauses(clause.OnConflict{DoNothing: true}).Create(rows).Error
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationChannelRepository defines the interface for notification channel data operations
+type NotificationChannelRepository interface {
+	Create(channel *models.NotificationChannel, serverIDs []uuid.UUID) error
+	FindByID(id uuid.UUID) (*models.NotificationChannel, error)
+	FindAll(serverID *uuid.UUID) ([]models.NotificationChannel, error)
+	FindEnabled() ([]models.NotificationChannel, error)
+	Update(channel *models.NotificationChannel, serverIDs []uuid.UUID) error
+	Delete(id uuid.UUID) error
+}
+
+// notificationChannelRepository implements NotificationChannelRepository
+type notificationChannelRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationChannelRepository creates a new notification channel repository instance
+func NewNotificationChannelRepository() NotificationChannelRepository {
+	return &notificationChannelRepository{
+		db: config.AppConfig.DB,
+	}
+}
+
+// Create creates a notification channel attached to the given servers
+func (r *notificationChannelRepository) Create(channel *models.NotificationChannel, serverIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(channel).Error; err != nil {
+			return err
+		}
+		return replaceChannelServers(tx, channel.ID, serverIDs)
+	})
+}
+
+// FindByID finds a notification channel by ID with its servers
+func (r *notificationChannelRepository) FindByID(id uuid.UUID) (*models.NotificationChannel, error) {
+	var channel models.NotificationChannel
+	err := r.db.Preload("Servers").Where("id = ?", id).First(&channel).Error
+	if err != nil {
+		return nil, err
+	}
+	return &channel, nil
+}
+
+// FindAll finds all notification channels with their servers, optionally only those of a server
+func (r *notificationChannelRepository) FindAll(serverID *uuid.UUID) ([]models.NotificationChannel, error) {
+	var channels []models.NotificationChannel
+	query := r.db.Preload("Servers")
+	if serverID != nil {
+		query = query.Where("id IN (?)", r.db.Table("notification_channel_servers").
+			Select("notification_channel_id").Where("server_id = ?", *serverID))
+	}
+	err := query.Order("created_at ASC").Find(&channels).Error
+	return channels, err
+}
+
+// FindEnabled finds the channels that are not disabled, with their servers
+func (r *notificationChannelRepository) FindEnabled() ([]models.NotificationChannel, error) {
+	var channels []models.NotificationChannel
+	err := r.db.Preload("Servers").Where("disabled = ?", false).Find(&channels).Error
+	return channels, err
+}
+
+// Update updates a notification channel; serverIDs replaces its servers unless nil
+func (r *notificationChannelRepository) Update(channel *models.NotificationChannel, serverIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(channel).Error; err != nil {
+			return err
+		}
+		if serverIDs == nil {
+			return nil
+		}
+		return replaceChannelServers(tx, channel.ID, serverIDs)
+	})
+}
+
+// Delete deletes a notification channel by ID (server links are removed by cascade)
+func (r *notificationChannelRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.NotificationChannel{}, id).Error
+}
+
+// replaceChannelServers sets the servers a notification channel applies to
+func replaceChannelServers(tx *gorm.DB, channelID uuid.UUID, serverIDs []uuid.UUID) error {
+	if err := tx.Exec("DELETE FROM notification_channel_servers WHERE notification_channel_id = ?", channelID).Error; err != nil {
+		return err
+	}
+
+	rows := make([]map[string]interface{}, 0, len(serverIDs))
+	for _, serverID := range serverIDs {
+		rows = append(rows, map[string]interface{}{
+			"notification_channel_id": channelID,
+			"server_id":               serverID,
+		})
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return tx.Table("notification_channel_servers").Clauses(clause.OnConflict{DoNothing: true}).Create(rows).Error
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/models"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errDigestFlushed rolls back a digest flush whose items were flushed concurrently
+var errDigestFlushed = errors.New("digest items already flushed")
+
+// NotificationDigestRepository defines the interface for notification rate limit and digest data operations
+type NotificationDigestRepository interface {
+	Hit(keys []string, now time.Time, window time.Duration) (map[string]int, error)
+	Defer(notification *models.NotificationOutbox, items []models.NotificationDigestItem) error
+	FindDue(before time.Time) ([]models.NotificationDigestItem, error)
+	Flush(items []models.NotificationDigestItem, digest *models.NotificationOutbox) (bool, error)
+}
+
+// notificationDigestRepository implements NotificationDigestRepository
+type notificationDigestRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationDigestRepository creates a new notification digest repository instance
+func NewNotificationDigestRepository() NotificationDigestRepository {
+	return &notificationDigestRepository{
+		db: config.AppConfig.DB,
+	}
+}
+
+// Hit counts a delivery for each of the distinct rate limit keys and returns their counts in
+// the current window. A window starts at the first hit after the previous one ended.
+func (r *notificationDigestRepository) Hit(keys []string, now time.Time, window time.Duration) (map[string]int, error) {
+	counts := make(map[string]int, len(keys))
+	if len(keys) == 0 {
+		return counts, nil
+	}
+
+	counters := make([]models.NotificationRateCounter, len(keys))
+	for i, key := range keys {
+		counters[i] = models.NotificationRateCounter{Key: key, WindowStart: now, Count: 1}
+	}
+
+	expired := now.Add(-window)
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "key"}},
+		DoUpdates: clause.Set{
+			{
+				Column: clause.Column{Name: "count"},
+				Value:  gorm.Expr("CASE WHEN notification_rate_counters.window_start <= ? THEN 1 ELSE notification_rate_counters.count + 1 END", expired),
+			},
+			{
+				Column: clause.Column{Name: "window_start"},
+				Value:  gorm.Expr("CASE WHEN notification_rate_counters.window_start <= ? THEN excluded.window_start ELSE notification_rate_counters.window_start END", expired),
+			},
+		},
+	}, clause.Returning{}).Create(&counters).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, counter := range counters {
+		counts[counter.Key] = counter.Count
+	}
+	return counts, nil
+}
+
+// Defer stores the digest items held back from a notification, together with its remaining
+// push recipients and its status (DIGESTED when nothing is left to deliver)
+func (r *notificationDigestRepository) Defer(notification *models.NotificationOutbox, items []models.NotificationDigestItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(items) > 0 {
+			if err := tx.Create(&items).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(notification).Select("user_ids", "status").Updates(&models.NotificationOutbox{
+			UserIDs: notification.UserIDs,
+			Status:  notification.Status,
+		}).Error
+	})
+}
+
+// FindDue finds the digest items of every recipient whose oldest item was held back
+// at or before the given time, oldest first
+func (r *notificationDigestRepository) FindDue(before time.Time) ([]models.NotificationDigestItem, error) {
+	var items []models.NotificationDigestItem
+	due := r.db.Model(&models.NotificationDigestItem{}).Select("recipient_key").
+		Group("recipient_key").Having("MIN(created_at) <= ?", before)
+	err := r.db.Where("recipient_key IN (?)", due).Order("created_at ASC").Find(&items).Error
+	return items, err
+}
+
+// Flush deletes the digest items of a recipient and queues their digest notification, if any.
+// It returns false if the items were flushed concurrently.
+func (r *notificationDigestRepository) Flush(items []models.NotificationDigestItem, digest *models.NotificationOutbox) (bool, error) {
+	ids := make([]uuid.UUID, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("id IN ?", ids).Delete(&models.NotificationDigestItem{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected != int64(len(ids)) {
+			return errDigestFlushed
+		}
+		if digest == nil {
+			return nil
+		}
+		return tx.Omit(clause.Associations).Create(digest).Error
+	})
+	if errors.Is(err, errDigestFlushed) {
+		return false, nil
+	}
+	return err == nil, err
+}
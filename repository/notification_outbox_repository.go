@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationOutboxRepository defines the interface for notification outbox data operations
+type NotificationOutboxRepository interface {
+	Create(notifications []models.NotificationOutbox) error
+	ClaimDue(now time.Time, lease time.Duration, limit int) ([]models.NotificationOutbox, error)
+	SaveAttempt(notification *models.NotificationOutbox, attempt *models.NotificationAttempt) error
+	FindByID(id uuid.UUID) (*models.NotificationOutbox, error)
+	FindByHistoryID(historyID uuid.UUID) ([]models.NotificationOutbox, error)
+	FindAll(status string, limit int) ([]models.NotificationOutbox, error)
+	Requeue(id uuid.UUID, now time.Time) (bool, error)
+}
+
+// notificationOutboxRepository implements NotificationOutboxRepository
+type notificationOutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationOutboxRepository creates a new notification outbox repository instance
+func NewNotificationOutboxRepository() NotificationOutboxRepository {
+	return &notificationOutboxRepository{
+		db: config.AppConfig.DB,
+	}
+}
+
+// Create stores notifications for delivery
+func (r *notificationOutboxRepository) Create(notifications []models.NotificationOutbox) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.Omit(clause.Associations).Create(&notifications).Error
+}
+
+// ClaimDue finds pending notifications due at now, oldest first, and postpones their next
+// attempt by lease so that no other worker picks them up while they are being delivered.
+// Rows claimed by a concurrent transaction are skipped.
+func (r *notificationOutboxRepository) ClaimDue(now time.Time, lease time.Duration, limit int) ([]models.NotificationOutbox, error) {
+	var notifications []models.NotificationOutbox
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.OutboxStatusPending, now).
+			Order("next_attempt_at ASC").Limit(limit).Find(&notifications).Error
+		if err != nil || len(notifications) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, len(notifications))
+		for i, notification := range notifications {
+			ids[i] = notification.ID
+		}
+		return tx.Model(&models.NotificationOutbox{}).Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// SaveAttempt stores the outcome of a delivery attempt together with its delivery log entry
+func (r *notificationOutboxRepository) SaveAttempt(notification *models.NotificationOutbox, attempt *models.NotificationAttempt) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(attempt).Error; err != nil {
+			return err
+		}
+		return tx.Model(notification).Updates(map[string]interface{}{
+			"status":          notification.Status,
+			"attempts":        notification.Attempts,
+			"next_attempt_at": notification.NextAttemptAt,
+			"last_error":      notification.LastError,
+			"sent_at":         notification.SentAt,
+			"pending":         notification.Pending,
+		}).Error
+	})
+}
+
+// FindByID finds an outbox notification by ID with its delivery log
+func (r *notificationOutboxRepository) FindByID(id uuid.UUID) (*models.NotificationOutbox, error) {
+	var notification models.NotificationOutbox
+	err := r.db.Preload("Log", func(db *gorm.DB) *gorm.DB {
+		return db.Order("attempt ASC")
+	}).Where("id = ?", id).First(&notification).Error
+	if err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// FindByHistoryID finds the notifications of an incident with their delivery logs, oldest first
+func (r *notificationOutboxRepository) FindByHistoryID(historyID uuid.UUID) ([]models.NotificationOutbox, error) {
+	var notifications []models.NotificationOutbox
+	err := r.db.Preload("Log", func(db *gorm.DB) *gorm.DB {
+		return db.Order("attempt ASC")
+	}).Where("history_id = ?", historyID).Order("created_at ASC").Find(&notifications).Error
+	return notifications, err
+}
+
+// FindAll finds the latest outbox notifications, optionally of a single status
+func (r *notificationOutboxRepository) FindAll(status string, limit int) ([]models.NotificationOutbox, error) {
+	var notifications []models.NotificationOutbox
+	query := r.db.Order("created_at DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&notifications).Error
+	return notifications, err
+}
+
+// Requeue schedules a dead notification for another delivery attempt right away.
+// It returns false if the notification is not dead.
+func (r *notificationOutboxRepository) Requeue(id uuid.UUID, now time.Time) (bool, error) {
+	res := r.db.Model(&models.NotificationOutbox{}).
+		Where("id = ? AND status = ?", id, models.OutboxStatusDead).
+		Updates(map[string]interface{}{
+			"status":          models.OutboxStatusPending,
+			"next_attempt_at": now,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationPreferenceRepository defines the interface for notification preference data operations
+type NotificationPreferenceRepository interface {
+	FindByUserID(userID uuid.UUID) (*models.NotificationPreference, error)
+	FindByUserIDs(userIDs []uuid.UUID) ([]models.NotificationPreference, error)
+	Save(preference *models.NotificationPreference) error
+}
+
+// notificationPreferenceRepository implements NotificationPreferenceRepository
+type notificationPreferenceRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationPreferenceRepository creates a new notification preference repository instance
+func NewNotificationPreferenceRepository() NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{
+		db: config.AppConfig.DB,
+	}
+}
+
+// FindByUserID finds the notification preferences of a user
+func (r *notificationPreferenceRepository) FindByUserID(userID uuid.UUID) (*models.NotificationPreference, error) {
+	var preference models.NotificationPreference
+	err := r.db.Where("user_id = ?", userID).First(&preference).Error
+	if err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
+
+// FindByUserIDs finds the notification preferences of several users; users without preferences are left out
+func (r *notificationPreferenceRepository) FindByUserIDs(userIDs []uuid.UUID) ([]models.NotificationPreference, error) {
+	var preferences []models.NotificationPreference
+	if len(userIDs) == 0 {
+		return preferences, nil
+	}
+	err := r.db.Where("user_id IN ?", userIDs).Find(&preferences).Error
+	return preferences, err
+}
+
+// Save creates or replaces the notification preferences of a user
+func (r *notificationPreferenceRepository) Save(preference *models.NotificationPreference) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		UpdateAll: true,
+	}).Create(preference).Error
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationTemplateRepository defines the interface for notification template data operations
+type NotificationTemplateRepository interface {
+	Find(event, channel, locale string) (*models.NotificationTemplate, error)
+	FindAll() ([]models.NotificationTemplate, error)
+	Save(tmpl *models.NotificationTemplate) error
+	Delete(event, channel, locale string) (bool, error)
+}
+
+// notificationTemplateRepository implements NotificationTemplateRepository
+type notificationTemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationTemplateRepository creates a new notification template repository instance
+func NewNotificationTemplateRepository() NotificationTemplateRepository {
+	return &notificationTemplateRepository{
+		db: config.AppConfig.DB,
+	}
+}
+
+// Find finds the template of an event for a channel and locale
+func (r *notificationTemplateRepository) Find(event, channel, locale string) (*models.NotificationTemplate, error) {
+	var tmpl models.NotificationTemplate
+	err := r.db.Where("event = ? AND channel = ? AND locale = ?", event, channel, locale).First(&tmpl).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+// FindAll finds all stored templates
+func (r *notificationTemplateRepository) FindAll() ([]models.NotificationTemplate, error) {
+	var templates []models.NotificationTemplate
+	err := r.db.Order("event ASC, channel ASC, locale ASC").Find(&templates).Error
+	return templates, err
+}
+
+// Save creates or replaces the template of an event for a channel and locale
+func (r *notificationTemplateRepository) Save(tmpl *models.NotificationTemplate) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event"}, {Name: "channel"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "body", "updated_by", "updated_at"}),
+	}).Create(tmpl).Error
+}
+
+// Delete deletes the template of an event for a channel and locale.
+// It returns false if there was none.
+func (r *notificationTemplateRepository) Delete(event, channel, locale string) (bool, error) {
+	res := r.db.Where("event = ? AND channel = ? AND locale = ?", event, channel, locale).
+		Delete(&models.NotificationTemplate{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OnCallRepository defines the interface for on-call schedule data operations
+type OnCallRepository interface {
+	Create(schedule *models.OnCallSchedule) error
+	FindByID(id uuid.UUID) (*models.OnCallSchedule, error)
+	FindByIDs(ids []uuid.UUID) ([]models.OnCallSchedule, error)
+	FindAll() ([]models.OnCallSchedule, error)
+	Update(schedule *models.OnCallSchedule, layers []models.OnCallLayer) error
+	Delete(id uuid.UUID) error
+	CreateOverride(override *models.OnCallOverride) error
+	FindOverrideByID(id uuid.UUID) (*models.OnCallOverride, error)
+	DeleteOverride(id uuid.UUID) error
+}
+
+// onCallRepository implements OnCallRepository
+type onCallRepository struct {
+	db *gorm.DB
+}
+
+// NewOnCallRepository creates a new on-call schedule repository instance
+func NewOnCallRepository() OnCallRepository {
+	return &onCallRepository{
+		db: config.AppConfig.DB,
+	}
+}
+
+// Create creates an on-call schedule with its layers
+func (r *onCallRepository) Create(schedule *models.OnCallSchedule) error {
+	return r.db.Omit("Overrides").Create(schedule).Error
+}
+
+// FindByID finds an on-call schedule by ID with its layers and overrides
+func (r *onCallRepository) FindByID(id uuid.UUID) (*models.OnCallSchedule, error) {
+	var schedule models.OnCallSchedule
+	err := r.preload().Where("id = ?", id).First(&schedule).Error
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// FindByIDs finds the on-call schedules with the given IDs with their layers and overrides
+func (r *onCallRepository) FindByIDs(ids []uuid.UUID) ([]models.OnCallSchedule, error) {
+	var schedules []models.OnCallSchedule
+	if len(ids) == 0 {
+		return schedules, nil
+	}
+	err := r.preload().Where("id IN ?", ids).Find(&schedules).Error
+	return schedules, err
+}
+
+// FindAll finds all on-call schedules with their layers and overrides
+func (r *onCallRepository) FindAll() ([]models.OnCallSchedule, error) {
+	var schedules []models.OnCallSchedule
+	err := r.preload().Order("name ASC").Find(&schedules).Error
+	return schedules, err
+}
+
+// Update updates an on-call schedule; layers replaces its layers unless nil.
+// Overrides are managed separately.
+func (r *onCallRepository) Update(schedule *models.OnCallSchedule, layers []models.OnCallLayer) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(schedule).Error; err != nil {
+			return err
+		}
+		if layers == nil {
+			return nil
+		}
+		if err := tx.Where("schedule_id = ?", schedule.ID).Delete(&models.OnCallLayer{}).Error; err != nil {
+			return err
+		}
+		for i := range layers {
+			layers[i].ScheduleID = schedule.ID
+		}
+		return tx.Create(&layers).Error
+	})
+}
+
+// Delete deletes an on-call schedule by ID and detaches it from its servers
+// (layers and overrides are removed by cascade)
+func (r *onCallRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Server{}).Where("on_call_schedule_id = ?", id).
+			Update("on_call_schedule_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.OnCallSchedule{}, id).Error
+	})
+}
+
+// CreateOverride creates an override of an on-call schedule
+func (r *onCallRepository) CreateOverride(override *models.OnCallOverride) error {
+	return r.db.Create(override).Error
+}
+
+// FindOverrideByID finds an on-call override by ID
+func (r *onCallRepository) FindOverrideByID(id uuid.UUID) (*models.OnCallOverride, error) {
+	var override models.OnCallOverride
+	err := r.db.Where("id = ?", id).First(&override).Error
+	if err != nil {
+		return nil, err
+	}
+	return &override, nil
+}
+
+// DeleteOverride deletes an on-call override by ID
+func (r *onCallRepository) DeleteOverride(id uuid.UUID) error {
+	return r.db.Delete(&models.OnCallOverride{}, id).Error
+}
+
+// preload loads the layers in order and the overrides of schedules
+func (r *onCallRepository) preload() *gorm.DB {
+	return r.db.Preload("Layers", func(db *gorm.DB) *gorm.DB {
+		return db.Order("level ASC")
+	}).Preload("Overrides", func(db *gorm.DB) *gorm.DB {
+		return db.Order("starts_at ASC")
+	})
+}
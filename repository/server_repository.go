@@ -3,9 +3,11 @@ package repository
 import (
 	"NetGuardServer/config"
 	"NetGuardServer/models"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ServerRepository defines the interface for server data operations
@@ -14,8 +16,18 @@ type ServerRepository interface {
 	FindByID(id uuid.UUID) (*models.Server, error)
 	GetAllServers() ([]models.Server, error)
 	FindByUserID(userID uuid.UUID) ([]models.Server, error)
+	FindByIDs(ids []uuid.UUID) ([]models.Server, error)
+	FindByHeartbeatToken(token string) (*models.Server, error)
+	FindOverdueHeartbeats(now time.Time) ([]models.Server, error)
 	Update(server *models.Server) error
+	UpdateStatus(server *models.Server) error
+	IncrementCounter(server *models.Server, status string) error
+	UpdateCertificate(server *models.Server) error
+	UpdateHeartbeat(server *models.Server) error
 	Delete(id uuid.UUID) error
+	FindDependencies(serverID uuid.UUID) ([]uuid.UUID, error)
+	FindAllDependencies() ([]models.ServerDependency, error)
+	ReplaceDependencies(serverID uuid.UUID, dependsOn []uuid.UUID) error
 }
 
 // serverRepository implements ServerRepository
@@ -59,12 +71,137 @@ func (r *serverRepository) FindByUserID(userID uuid.UUID) ([]models.Server, erro
 	return servers, err
 }
 
-// Update updates a server
+// FindByIDs finds the servers with the given IDs; unknown IDs are skipped
+func (r *serverRepository) FindByIDs(ids []uuid.UUID) ([]models.Server, error) {
+	var servers []models.Server
+	err := r.db.Where("id IN ?", ids).Find(&servers).Error
+	return servers, err
+}
+
+// FindByHeartbeatToken finds the heartbeat server with the given ping token
+func (r *serverRepository) FindByHeartbeatToken(token string) (*models.Server, error) {
+	var server models.Server
+	err := r.db.Where("heartbeat_token = ? AND check_type = ?", token, models.CheckTypeHeartbeat).First(&server).Error
+	if err != nil {
+		return nil, err
+	}
+	return &server, nil
+}
+
+// FindOverdueHeartbeats finds heartbeat servers whose next ping is overdue and that are not DOWN yet.
+// MAINTENANCE servers are included: whether their window is still active is not known here.
+func (r *serverRepository) FindOverdueHeartbeats(now time.Time) ([]models.Server, error) {
+	var servers []models.Server
+	err := r.db.Where("check_type = ? AND heartbeat_expected_by < ? AND status <> ?",
+		models.CheckTypeHeartbeat, now, models.ServerStatusDown).Find(&servers).Error
+	return servers, err
+}
+
+// Update updates only the settings columns of a server, so the status, counters and
+// heartbeat pings written concurrently by status reports are not overwritten
 func (r *serverRepository) Update(server *models.Server) error {
-	return r.db.Save(server).Error
+	return r.db.Model(&models.Server{}).Where("id = ?", server.ID).Select(
+		"name", "url", "check_type", "host", "port", "dns_record_type", "dns_expected", "timeout_seconds",
+		"assert_status_codes", "assert_body_contains", "assert_body_not_contains", "assert_body_regex",
+		"assert_json_path", "assert_json_path_value", "assert_headers", "assert_max_response_time_ms",
+		"heartbeat_token", "heartbeat_period_seconds", "heartbeat_grace_seconds", "heartbeat_expected_by",
+		"confirm_reporters", "confirm_window_seconds", "failure_threshold", "success_threshold",
+		"escalation_policy_id", "on_call_schedule_id", "tags", "division", "severity",
+	).Updates(server).Error
+}
+
+// UpdateStatus updates only the latest status columns of a server,
+// so concurrent edits of name/URL are not overwritten
+func (r *serverRepository) UpdateStatus(server *models.Server) error {
+	return r.db.Model(&models.Server{}).Where("id = ?", server.ID).Updates(map[string]interface{}{
+		"status":            server.Status,
+		"response_time":     server.ResponseTime,
+		"last_checked":      server.LastChecked,
+		"last_checked_by":   server.LastCheckedBy,
+		"last_check_source": server.LastCheckSource,
+		"suspected_since":   server.SuspectedSince,
+		"flapping":          server.Flapping,
+		"flapping_since":    server.FlappingSince,
+	}).Error
 }
 
-// Delete deletes a server by ID
+// IncrementCounter increments the consecutive failure (DOWN) or success (UP) counter of a
+// server and resets the other one in SQL, so concurrent reports cannot lose increments.
+// Both counters are loaded into the server afterwards.
+func (r *serverRepository) IncrementCounter(server *models.Server, status string) error {
+	increment, reset := "consecutive_failures", "consecutive_successes"
+	if status == models.ServerStatusUp {
+		increment, reset = reset, increment
+	}
+
+	res := r.db.Model(server).Clauses(clause.Returning{Columns: []clause.Column{
+		{Name: "consecutive_failures"}, {Name: "consecutive_successes"},
+	}}).Updates(map[string]interface{}{
+		increment: gorm.Expr(increment + " + 1"),
+		reset:     0,
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UpdateCertificate updates only the TLS certificate columns of a server
+func (r *serverRepository) UpdateCertificate(server *models.Server) error {
+	return r.db.Model(&models.Server{}).Where("id = ?", server.ID).Select(
+		"cert_expires_at", "cert_issuer", "cert_subject", "cert_sans", "cert_checked_at", "cert_warned_days",
+	).Updates(server).Error
+}
+
+// UpdateHeartbeat updates only the heartbeat ping columns of a server
+func (r *serverRepository) UpdateHeartbeat(server *models.Server) error {
+	return r.db.Model(&models.Server{}).Where("id = ?", server.ID).Updates(map[string]interface{}{
+		"last_heartbeat_at":     server.LastHeartbeatAt,
+		"heartbeat_expected_by": server.HeartbeatExpectedBy,
+	}).Error
+}
+
+// Delete deletes a server by ID together with its dependency links
 func (r *serverRepository) Delete(id uuid.UUID) error {
-	return r.db.Delete(&models.Server{}, id).Error
-}
\ No newline at end of file
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("server_id = ? OR depends_on_id = ?", id, id).Delete(&models.ServerDependency{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Server{}, id).Error
+	})
+}
+
+// FindDependencies finds the IDs of the parent servers a server depends on
+func (r *serverRepository) FindDependencies(serverID uuid.UUID) ([]uuid.UUID, error) {
+	var dependsOn []uuid.UUID
+	err := r.db.Model(&models.ServerDependency{}).Where("server_id = ?", serverID).Pluck("depends_on_id", &dependsOn).Error
+	return dependsOn, err
+}
+
+// FindAllDependencies finds all dependency links between servers
+func (r *serverRepository) FindAllDependencies() ([]models.ServerDependency, error) {
+	var dependencies []models.ServerDependency
+	err := r.db.Find(&dependencies).Error
+	return dependencies, err
+}
+
+// ReplaceDependencies sets the parent servers a server depends on
+func (r *serverRepository) ReplaceDependencies(serverID uuid.UUID, dependsOn []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("server_id = ?", serverID).Delete(&models.ServerDependency{}).Error; err != nil {
+			return err
+		}
+		if len(dependsOn) == 0 {
+			return nil
+		}
+
+		dependencies := make([]models.ServerDependency, len(dependsOn))
+		for i, parentID := range dependsOn {
+			dependencies[i] = models.ServerDependency{ServerID: serverID, DependsOnID: parentID}
+		}
+		return tx.Create(&dependencies).Error
+	})
+}
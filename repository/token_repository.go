@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TokenRepository defines the interface for refresh token and token revocation data operations
+type TokenRepository interface {
+	CreateRefreshToken(token *models.RefreshToken) error
+	FindRefreshTokenByHash(hash string) (*models.RefreshToken, error)
+	FindRefreshTokenByAccessTokenID(tokenID uuid.UUID) (*models.RefreshToken, error)
+	Rotate(used *models.RefreshToken, next *models.RefreshToken, now time.Time) (bool, error)
+	RevokeFamily(familyID uuid.UUID, now time.Time) error
+	Revoke(token *models.RevokedToken) error
+	IsRevoked(tokenID uuid.UUID) (bool, error)
+	DeleteExpired(now time.Time) error
+}
+
+// tokenRepository implements TokenRepository
+type tokenRepository struct {
+	db *gorm.DB
+}
+
+// NewTokenRepository creates a new token repository instance
+func NewTokenRepository() TokenRepository {
+	return &tokenRepository{
+		db: config.AppConfig.DB,
+	}
+}
+
+// CreateRefreshToken stores a new refresh token
+func (r *tokenRepository) CreateRefreshToken(token *models.RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+// FindRefreshTokenByHash finds a refresh token by its hash
+func (r *tokenRepository) FindRefreshTokenByHash(hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := r.db.Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FindRefreshTokenByAccessTokenID finds the refresh token issued together with an access token
+func (r *tokenRepository) FindRefreshTokenByAccessTokenID(tokenID uuid.UUID) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := r.db.Where("access_token_id = ?", tokenID).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Rotate marks a refresh token used and stores its successor in the same transaction.
+// It returns false if the token was already used or revoked, e.g. by a concurrent refresh.
+func (r *tokenRepository) Rotate(used *models.RefreshToken, next *models.RefreshToken, now time.Time) (bool, error) {
+	rotated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", used.ID).
+			Update("used_at", now)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		rotated = true
+		return nil
+	})
+	return rotated, err
+}
+
+// RevokeFamily revokes every refresh token of a family together with the unexpired
+// access tokens issued with them
+func (r *tokenRepository) RevokeFamily(familyID uuid.UUID, now time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var tokens []models.RefreshToken
+		err := tx.Where("family_id = ? AND access_expires_at > ?", familyID, now).Find(&tokens).Error
+		if err != nil {
+			return err
+		}
+
+		if len(tokens) > 0 {
+			revoked := make([]models.RevokedToken, len(tokens))
+			for i, token := range tokens {
+				revoked[i] = models.RevokedToken{
+					TokenID:   token.AccessTokenID,
+					UserID:    token.UserID,
+					ExpiresAt: token.AccessExpiresAt,
+					RevokedAt: now,
+				}
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&revoked).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&models.RefreshToken{}).
+			Where("family_id = ? AND revoked_at IS NULL", familyID).
+			Update("revoked_at", now).Error
+	})
+}
+
+// Revoke adds an access token to the revocation list
+func (r *tokenRepository) Revoke(token *models.RevokedToken) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(token).Error
+}
+
+// IsRevoked tells whether an access token is on the revocation list
+func (r *tokenRepository) IsRevoked(tokenID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.RevokedToken{}).Where("token_id = ?", tokenID).Count(&count).Error
+	return count > 0, err
+}
+
+// DeleteExpired deletes expired refresh tokens and revocations of expired access tokens
+func (r *tokenRepository) DeleteExpired(now time.Time) error {
+	if err := r.db.Where("expires_at <= ?", now).Delete(&models.RefreshToken{}).Error; err != nil {
+		return err
+	}
+	return r.db.Where("expires_at <= ?", now).Delete(&models.RevokedToken{}).Error
+}
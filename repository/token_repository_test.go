@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"NetGuardServer/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTokenFamilyRevocation(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewTokenRepository()
+	userID, familyID := uuid.New(), uuid.New()
+	now := time.Now()
+	t.Cleanup(func() {
+		db.Where("family_id = ?", familyID).Delete(&models.RefreshToken{})
+		db.Where("user_id = ?", userID).Delete(&models.RevokedToken{})
+	})
+
+	token := func(accessExpiresAt time.Time) *models.RefreshToken {
+		return &models.RefreshToken{
+			UserID:          userID,
+			FamilyID:        familyID,
+			TokenHash:       uuid.NewString(),
+			AccessTokenID:   uuid.New(),
+			AccessExpiresAt: accessExpiresAt,
+			ExpiresAt:       now.AddDate(0, 0, 30),
+		}
+	}
+	first, next := token(now.Add(-time.Minute)), token(now.Add(15*time.Minute))
+	if err := repo.CreateRefreshToken(first); err != nil {
+		t.Fatalf("failed to create refresh token: %v", err)
+	}
+
+	if rotated, err := repo.Rotate(first, next, now); err != nil || !rotated {
+		t.Fatalf("rotate = %v, %v, want rotated", rotated, err)
+	}
+	if rotated, err := repo.Rotate(first, token(now.Add(15*time.Minute)), now); err != nil || rotated {
+		t.Errorf("second rotate = %v, %v, want the used token refused", rotated, err)
+	}
+
+	if err := repo.RevokeFamily(familyID, now); err != nil {
+		t.Fatalf("failed to revoke family: %v", err)
+	}
+	for _, tt := range []struct {
+		name    string
+		tokenID uuid.UUID
+		want    bool
+	}{
+		{name: "live access token", tokenID: next.AccessTokenID, want: true},
+		{name: "expired access token is not kept", tokenID: first.AccessTokenID, want: false},
+	} {
+		revoked, err := repo.IsRevoked(tt.tokenID)
+		if err != nil || revoked != tt.want {
+			t.Errorf("%s: revoked = %v, %v, want %v", tt.name, revoked, err, tt.want)
+		}
+	}
+
+	stored, err := repo.FindRefreshTokenByHash(next.TokenHash)
+	if err != nil || stored.RevokedAt == nil {
+		t.Errorf("refresh token of the family not revoked: %v", err)
+	}
+}
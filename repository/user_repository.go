@@ -13,6 +13,9 @@ type UserRepository interface {
 	Create(user *models.User) error
 	FindByEmail(email string) (*models.User, error)
 	FindByID(id uuid.UUID) (*models.User, error)
+	FindByIDs(ids []uuid.UUID) ([]models.User, error)
+	FindActiveByDivisions(divisions []string) ([]models.User, error)
+	FindActive() ([]models.User, error)
 	Update(user *models.User) error
 	Delete(id uuid.UUID) error
 }
@@ -54,6 +57,33 @@ func (r *userRepository) FindByID(id uuid.UUID) (*models.User, error) {
 	return &user, nil
 }
 
+// FindByIDs finds the users with the given IDs
+func (r *userRepository) FindByIDs(ids []uuid.UUID) ([]models.User, error) {
+	var users []models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&users).Error
+	return users, err
+}
+
+// FindActiveByDivisions finds the active users of the given divisions
+func (r *userRepository) FindActiveByDivisions(divisions []string) ([]models.User, error) {
+	var users []models.User
+	if len(divisions) == 0 {
+		return users, nil
+	}
+	err := r.db.Where("division IN ? AND is_active = ?", divisions, true).Find(&users).Error
+	return users, err
+}
+
+// FindActive finds all active users
+func (r *userRepository) FindActive() ([]models.User, error) {
+	var users []models.User
+	err := r.db.Where("is_active = ?", true).Find(&users).Error
+	return users, err
+}
+
 // Update updates a user
 func (r *userRepository) Update(user *models.User) error {
 	return r.db.Save(user).Error
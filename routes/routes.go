@@ -8,13 +8,7 @@ import (
 )
 
 // SetupRoutes configures all application routes
-func SetupRoutes(app *fiber.App) {
-	// Initialize all dependencies using Wire
-	appContainer, err := di.InitializeApp()
-	if err != nil {
-		panic("Failed to initialize application: " + err.Error())
-	}
-
+func SetupRoutes(app *fiber.App, appContainer *di.App) {
 	// API routes
 	api := app.Group("/api")
 
@@ -22,24 +16,100 @@ func SetupRoutes(app *fiber.App) {
 	auth := api.Group("/auth")
 	auth.Post("/register", appContainer.AuthController.Register)
 	auth.Post("/login", appContainer.AuthController.Login)
+	auth.Post("/refresh", appContainer.AuthController.Refresh)
+
+	// Heartbeat pings are authenticated by their secret token
+	api.Post("/heartbeat/:token", appContainer.HeartbeatController.Ping)
 
 	// Protected routes
 	protected := api.Group("", middleware.JWTMiddleware)
 	protected.Get("/auth/me", appContainer.AuthController.GetProfile)
 	protected.Put("/auth/profile", appContainer.AuthController.UpdateProfile)
+	protected.Post("/auth/logout", appContainer.AuthController.Logout)
+
+	// Device token routes
+	devices := protected.Group("/devices")
+	devices.Post("", appContainer.DeviceController.RegisterDevice)
+	devices.Get("", appContainer.DeviceController.GetDevices)
+	devices.Delete("", appContainer.DeviceController.UnregisterDevice)
+
+	// Notification preference routes
+	preferences := protected.Group("/notification-preferences")
+	preferences.Get("", appContainer.NotificationPreferenceController.GetPreferences)
+	preferences.Put("", appContainer.NotificationPreferenceController.UpdatePreferences)
+
+	// Notification channel routes
+	channels := protected.Group("/notification-channels")
+	channels.Post("", appContainer.NotificationChannelController.CreateChannel)
+	channels.Get("", appContainer.NotificationChannelController.GetChannels)
+	channels.Get("/:id", appContainer.NotificationChannelController.GetChannel)
+	channels.Put("/:id", appContainer.NotificationChannelController.UpdateChannel)
+	channels.Delete("/:id", appContainer.NotificationChannelController.DeleteChannel)
+	channels.Post("/:id/test", appContainer.NotificationChannelController.TestChannel)
+
+	// Notification outbox routes, admin only
+	notifications := protected.Group("/notifications", middleware.AdminMiddleware)
+	notifications.Get("", appContainer.NotificationController.GetOutbox)
+	notifications.Post("/:id/retry", appContainer.NotificationController.RetryNotification)
+
+	// Notification template routes, admin only
+	templates := protected.Group("/notification-templates", middleware.AdminMiddleware)
+	templates.Get("", appContainer.NotificationTemplateController.GetTemplates)
+	templates.Post("/preview", appContainer.NotificationTemplateController.PreviewTemplate)
+	templates.Put("/:event/:channel/:locale", appContainer.NotificationTemplateController.UpdateTemplate)
+	templates.Delete("/:event/:channel/:locale", appContainer.NotificationTemplateController.DeleteTemplate)
 
 	// Server routes
 	servers := protected.Group("/servers")
 	servers.Post("", appContainer.ServerController.CreateServer)
 	servers.Get("", appContainer.ServerController.GetServers)
+	servers.Get("/graph", appContainer.ServerController.GetDependencyGraph) // before /:id
 	servers.Get("/:id", appContainer.ServerController.GetServer)
 	servers.Put("/:id", appContainer.ServerController.UpdateServer)
 	servers.Delete("/:id", appContainer.ServerController.DeleteServer)
 	servers.Patch("/:id/status", appContainer.ServerController.UpdateServerStatus)
+	servers.Post("/:id/check", appContainer.ServerController.CheckServer)
+	servers.Post("/:id/heartbeat-token", appContainer.ServerController.RotateHeartbeatToken)
+	servers.Get("/:id/metrics", appContainer.MetricsController.GetServerMetrics)
+	servers.Get("/:id/heartbeats", appContainer.HeartbeatController.GetPings)
+
+	// Maintenance window routes
+	maintenance := protected.Group("/maintenance")
+	maintenance.Post("", appContainer.MaintenanceController.CreateWindow)
+	maintenance.Get("", appContainer.MaintenanceController.GetWindows)
+	maintenance.Get("/:id", appContainer.MaintenanceController.GetWindow)
+	maintenance.Put("/:id", appContainer.MaintenanceController.UpdateWindow)
+	maintenance.Delete("/:id", appContainer.MaintenanceController.DeleteWindow)
+
+	// Escalation policy routes
+	escalation := protected.Group("/escalation-policies")
+	escalation.Post("", appContainer.EscalationController.CreatePolicy)
+	escalation.Get("", appContainer.EscalationController.GetPolicies)
+	escalation.Get("/:id", appContainer.EscalationController.GetPolicy)
+	escalation.Put("/:id", appContainer.EscalationController.UpdatePolicy)
+	escalation.Delete("/:id", appContainer.EscalationController.DeletePolicy)
+
+	// On-call schedule routes
+	onCall := protected.Group("/oncall")
+	onCall.Get("", appContainer.OnCallController.GetAllOnCall)
+	onCall.Post("/schedules", appContainer.OnCallController.CreateSchedule)
+	onCall.Get("/schedules", appContainer.OnCallController.GetSchedules)
+	onCall.Get("/schedules/:id", appContainer.OnCallController.GetSchedule)
+	onCall.Put("/schedules/:id", appContainer.OnCallController.UpdateSchedule)
+	onCall.Delete("/schedules/:id", appContainer.OnCallController.DeleteSchedule)
+	onCall.Get("/schedules/:id/oncall", appContainer.OnCallController.GetOnCall)
+	onCall.Post("/schedules/:id/overrides", appContainer.OnCallController.CreateOverride)
+	onCall.Delete("/schedules/:id/overrides/:overrideId", appContainer.OnCallController.DeleteOverride)
 
 	// History routes
 	history := protected.Group("/history")
 	history.Get("", appContainer.HistoryController.GetHistory)
-	history.Patch("/:id/resolve", appContainer.HistoryController.ResolveHistory)
 	history.Get("/report/monthly", appContainer.HistoryController.GetMonthlyReport)
+	history.Get("/:id", appContainer.HistoryController.GetHistoryByID)
+	history.Get("/:id/timeline", appContainer.HistoryController.GetTimeline)
+	history.Post("/:id/timeline", appContainer.HistoryController.AddComment)
+	history.Get("/:id/notifications", appContainer.HistoryController.GetDeliveryLog)
+	history.Patch("/:id/acknowledge", appContainer.HistoryController.AcknowledgeHistory)
+	history.Patch("/:id/assign", appContainer.HistoryController.AssignHistory)
+	history.Patch("/:id/resolve", appContainer.HistoryController.ResolveHistory)
 }
@@ -1,58 +1,66 @@
 package services
 
 import (
+	"NetGuardServer/config"
+	"NetGuardServer/dto"
 	"NetGuardServer/models"
 	"NetGuardServer/repository"
 	"NetGuardServer/utils"
 	"errors"
+	"log"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 // AuthService defines the interface for authentication business logic
 type AuthService interface {
-	Register(name, email, password, division, phone, role string) (*models.User, string, error)
-	Login(email, password string) (*models.User, string, error)
+	Register(name, email, password, division, phone, role string) (*models.User, *dto.TokenResponse, error)
+	Login(email, password string) (*models.User, *dto.TokenResponse, error)
+	Refresh(refreshToken string) (*dto.TokenResponse, error)
+	Logout(userID, tokenID uuid.UUID) error
 	GetProfile(userID uuid.UUID) (*models.User, error)
-	UpdateProfile(userID uuid.UUID, name, division, phone string) (*models.User, error)
+	UpdateProfile(userID uuid.UUID, name, division, phone, locale string) (*models.User, error)
 }
 
 // authService implements AuthService
 type authService struct {
-	userRepo repository.UserRepository
+	userRepo  repository.UserRepository
+	tokenRepo repository.TokenRepository
 }
 
 // NewAuthService creates a new auth service instance
-func NewAuthService(userRepo repository.UserRepository) AuthService {
+func NewAuthService(userRepo repository.UserRepository, tokenRepo repository.TokenRepository) AuthService {
 	return &authService{
-		userRepo: userRepo,
+		userRepo:  userRepo,
+		tokenRepo: tokenRepo,
 	}
 }
 
 // Register handles user registration business logic
-func (s *authService) Register(name, email, password, division, phone, role string) (*models.User, string, error) {
+func (s *authService) Register(name, email, password, division, phone, role string) (*models.User, *dto.TokenResponse, error) {
 	// Validate input
 	if strings.TrimSpace(name) == "" {
-		return nil, "", errors.New("name is required")
+		return nil, nil, errors.New("name is required")
 	}
 	if strings.TrimSpace(email) == "" {
-		return nil, "", errors.New("email is required")
+		return nil, nil, errors.New("email is required")
 	}
 	if len(password) < 6 {
-		return nil, "", errors.New("password must be at least 6 characters long")
+		return nil, nil, errors.New("password must be at least 6 characters long")
 	}
 
 	// Check if user already exists
 	existingUser, err := s.userRepo.FindByEmail(strings.ToLower(email))
 	if err == nil && existingUser != nil {
-		return nil, "", errors.New("user with this email already exists")
+		return nil, nil, errors.New("user with this email already exists")
 	}
 
 	// Hash password
 	hashedPassword, err := utils.HashPassword(password)
 	if err != nil {
-		return nil, "", errors.New("failed to hash password")
+		return nil, nil, errors.New("failed to hash password")
 	}
 
 	// Create user
@@ -67,49 +75,184 @@ func (s *authService) Register(name, email, password, division, phone, role stri
 	}
 
 	if err := s.userRepo.Create(user); err != nil {
-		return nil, "", errors.New("failed to create user")
+		return nil, nil, errors.New("failed to create user")
 	}
 
-	// Generate JWT token
-	token, err := utils.GenerateJWT(*user)
+	// Generate access and refresh tokens
+	tokens, err := s.createSession(user)
 	if err != nil {
-		return nil, "", errors.New("failed to generate token")
+		return nil, nil, errors.New("failed to generate token")
 	}
 
 	// Remove password hash from response
 	user.PasswordHash = ""
 
-	return user, token, nil
+	return user, tokens, nil
 }
 
 // Login handles user authentication business logic
-func (s *authService) Login(email, password string) (*models.User, string, error) {
+func (s *authService) Login(email, password string) (*models.User, *dto.TokenResponse, error) {
 	// Validate input
 	if strings.TrimSpace(email) == "" || strings.TrimSpace(password) == "" {
-		return nil, "", errors.New("email and password are required")
+		return nil, nil, errors.New("email and password are required")
 	}
 
 	// Find user
 	user, err := s.userRepo.FindByEmail(strings.ToLower(email))
 	if err != nil {
-		return nil, "", errors.New("invalid email or password")
+		return nil, nil, errors.New("invalid email or password")
 	}
 
 	// Check password
 	if !utils.CheckPasswordHash(password, user.PasswordHash) {
-		return nil, "", errors.New("invalid email or password")
+		return nil, nil, errors.New("invalid email or password")
 	}
 
-	// Generate JWT token
-	token, err := utils.GenerateJWT(*user)
+	// Generate access and refresh tokens
+	tokens, err := s.createSession(user)
 	if err != nil {
-		return nil, "", errors.New("failed to generate token")
+		return nil, nil, errors.New("failed to generate token")
 	}
 
 	// Remove password hash from response
 	user.PasswordHash = ""
 
-	return user, token, nil
+	return user, tokens, nil
+}
+
+// Refresh rotates a refresh token: it issues a new access token and refresh token and
+// invalidates the presented one. Presenting a refresh token that was already used means it
+// was stolen, so every token of its family is revoked and the user has to log in again.
+func (s *authService) Refresh(refreshToken string) (*dto.TokenResponse, error) {
+	stored, err := s.tokenRepo.FindRefreshTokenByHash(utils.HashToken(refreshToken))
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	now := time.Now()
+	if stored.UsedAt != nil {
+		s.revokeFamily(stored)
+		return nil, errors.New("refresh token reuse detected")
+	}
+	if stored.RevokedAt != nil || !now.Before(stored.ExpiresAt) {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	user, err := s.userRepo.FindByID(stored.UserID)
+	if err != nil || !user.IsActive {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	next, tokens, err := issueTokens(user, stored.FamilyID, now)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	rotated, err := s.tokenRepo.Rotate(stored, next, now)
+	if err != nil {
+		return nil, errors.New("failed to refresh token")
+	}
+	if !rotated {
+		// Used by a concurrent refresh in the meantime
+		s.revokeFamily(stored)
+		return nil, errors.New("refresh token reuse detected")
+	}
+
+	return tokens, nil
+}
+
+// Logout revokes the session of an access token: the access token itself and every
+// refresh token issued with the same login
+func (s *authService) Logout(userID, tokenID uuid.UUID) error {
+	now := time.Now()
+
+	session, err := s.tokenRepo.FindRefreshTokenByAccessTokenID(tokenID)
+	if err == nil && session.UserID == userID {
+		if err := s.tokenRepo.RevokeFamily(session.FamilyID, now); err != nil {
+			return errors.New("failed to logout")
+		}
+		return nil
+	}
+
+	// Without its session only the access token can be revoked, until it expires at the latest
+	err = s.tokenRepo.Revoke(&models.RevokedToken{
+		TokenID:   tokenID,
+		UserID:    userID,
+		ExpiresAt: now.Add(accessTokenLifetime()),
+		RevokedAt: now,
+	})
+	if err != nil {
+		return errors.New("failed to logout")
+	}
+	return nil
+}
+
+// createSession issues the first access and refresh token of a new token family
+func (s *authService) createSession(user *models.User) (*dto.TokenResponse, error) {
+	now := time.Now()
+	if err := s.tokenRepo.DeleteExpired(now); err != nil {
+		log.Printf("WARN: Failed to delete expired tokens: %v", err)
+	}
+
+	refresh, tokens, err := issueTokens(user, uuid.New(), now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tokenRepo.CreateRefreshToken(refresh); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// revokeFamily revokes every token of the family of a refresh token
+func (s *authService) revokeFamily(token *models.RefreshToken) {
+	log.Printf("WARN: Refresh token reuse for user %s, revoking token family %s", token.UserID, token.FamilyID)
+	if err := s.tokenRepo.RevokeFamily(token.FamilyID, time.Now()); err != nil {
+		log.Printf("ERROR: Failed to revoke token family %s: %v", token.FamilyID, err)
+	}
+}
+
+// issueTokens generates an access token and a refresh token of a token family.
+// It returns the refresh token to store, which holds only the hash of the refresh token.
+func issueTokens(user *models.User, familyID uuid.UUID, now time.Time) (*models.RefreshToken, *dto.TokenResponse, error) {
+	accessTokenID := uuid.New()
+	accessExpiresAt := now.Add(accessTokenLifetime())
+	accessToken, err := utils.GenerateJWT(*user, accessTokenID, accessExpiresAt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refreshToken, hash, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refreshDays := config.AppConfig.JWT.RefreshTokenExpDays
+	if refreshDays <= 0 {
+		refreshDays = 30
+	}
+
+	stored := &models.RefreshToken{
+		UserID:          user.ID,
+		FamilyID:        familyID,
+		TokenHash:       hash,
+		AccessTokenID:   accessTokenID,
+		AccessExpiresAt: accessExpiresAt,
+		ExpiresAt:       now.AddDate(0, 0, refreshDays),
+	}
+	return stored, &dto.TokenResponse{
+		Token:        accessToken,
+		ExpiresAt:    accessExpiresAt.Format(time.RFC3339),
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// accessTokenLifetime returns how long an access token is valid
+func accessTokenLifetime() time.Duration {
+	if minutes := config.AppConfig.JWT.AccessTokenExpMinutes; minutes > 0 {
+		return time.Duration(minutes) * time.Minute
+	}
+	return 15 * time.Minute
 }
 
 // GetProfile handles getting user profile business logic
@@ -126,7 +269,7 @@ func (s *authService) GetProfile(userID uuid.UUID) (*models.User, error) {
 }
 
 // UpdateProfile handles updating user profile business logic
-func (s *authService) UpdateProfile(userID uuid.UUID, name, division, phone string) (*models.User, error) {
+func (s *authService) UpdateProfile(userID uuid.UUID, name, division, phone, locale string) (*models.User, error) {
 	// Get current user
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
@@ -143,6 +286,9 @@ func (s *authService) UpdateProfile(userID uuid.UUID, name, division, phone stri
 	if phone != "" {
 		user.Phone = phone
 	}
+	if locale != "" {
+		user.Locale = locale
+	}
 
 	// Save updated user
 	if err := s.userRepo.Update(user); err != nil {
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/dto"
+	"NetGuardServer/models"
+	"NetGuardServer/utils"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// newAuthTest returns an auth service on in-memory repositories and a session of a new user
+func newAuthTest(t *testing.T) (*authService, *fakeTokenRepository, *models.User, *dto.TokenResponse) {
+	t.Helper()
+	previous := config.AppConfig.JWT
+	t.Cleanup(func() { config.AppConfig.JWT = previous })
+	config.AppConfig.JWT.Secret = "test-secret"
+
+	tokenRepo := newFakeTokenRepository()
+	service := &authService{userRepo: &fakeUserRepository{}, tokenRepo: tokenRepo}
+	user := &models.User{ID: uuid.New(), Email: "alice@company.com", IsActive: true}
+	tokens, err := service.createSession(user)
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	return service, tokenRepo, user, tokens
+}
+
+// accessTokenID returns the token ID (jti) of an access token
+func accessTokenID(t *testing.T, token string) uuid.UUID {
+	t.Helper()
+	claims, err := utils.ValidateJWT(token)
+	if err != nil {
+		t.Fatalf("invalid access token: %v", err)
+	}
+	id, err := uuid.Parse(fmt.Sprint(claims["jti"]))
+	if err != nil {
+		t.Fatalf("access token without token ID: %v", err)
+	}
+	return id
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	service, tokenRepo, _, first := newAuthTest(t)
+
+	next, err := service.Refresh(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+	if next.RefreshToken == first.RefreshToken || next.Token == first.Token {
+		t.Error("refresh returned the presented tokens, want a new pair")
+	}
+
+	used, _ := tokenRepo.FindRefreshTokenByHash(utils.HashToken(first.RefreshToken))
+	if used.UsedAt == nil {
+		t.Error("presented refresh token not marked used")
+	}
+	issued, err := tokenRepo.FindRefreshTokenByHash(utils.HashToken(next.RefreshToken))
+	if err != nil {
+		t.Fatalf("new refresh token not stored: %v", err)
+	}
+	if issued.FamilyID != used.FamilyID || issued.AccessTokenID != accessTokenID(t, next.Token) {
+		t.Error("new refresh token not issued in the family with the new access token")
+	}
+	if revoked, _ := tokenRepo.IsRevoked(accessTokenID(t, first.Token)); revoked {
+		t.Error("access token of the rotated refresh token revoked, want it valid until it expires")
+	}
+
+	if _, err := service.Refresh(next.RefreshToken); err != nil {
+		t.Errorf("refresh with the new token failed: %v", err)
+	}
+}
+
+func TestRefreshReuseRevokesFamily(t *testing.T) {
+	service, tokenRepo, _, first := newAuthTest(t)
+	next, err := service.Refresh(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+
+	// A replayed refresh token was stolen: the thief and the user both lose the session
+	if _, err := service.Refresh(first.RefreshToken); err == nil || err.Error() != "refresh token reuse detected" {
+		t.Fatalf("replay error = %v, want reuse detected", err)
+	}
+
+	for _, token := range tokenRepo.refreshTokens {
+		if token.RevokedAt == nil {
+			t.Errorf("refresh token %s of the family not revoked", token.ID)
+		}
+	}
+	for _, access := range []string{first.Token, next.Token} {
+		if revoked, _ := tokenRepo.IsRevoked(accessTokenID(t, access)); !revoked {
+			t.Error("live access token of the family not revoked")
+		}
+	}
+	if _, err := service.Refresh(next.RefreshToken); err == nil || err.Error() != "invalid refresh token" {
+		t.Errorf("refresh with the latest token error = %v, want invalid refresh token", err)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	tests := []struct {
+		name        string
+		withSession bool
+	}{
+		{name: "revokes the session", withSession: true},
+		{name: "revokes an access token without session"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, tokenRepo, user, tokens := newAuthTest(t)
+			if !tt.withSession {
+				tokenRepo.refreshTokens = nil
+			}
+			tokenID := accessTokenID(t, tokens.Token)
+
+			if err := service.Logout(user.ID, tokenID); err != nil {
+				t.Fatalf("logout failed: %v", err)
+			}
+
+			if revoked, _ := tokenRepo.IsRevoked(tokenID); !revoked {
+				t.Error("access token not revoked")
+			}
+			if !tt.withSession {
+				return
+			}
+			if _, err := service.Refresh(tokens.RefreshToken); err == nil {
+				t.Error("refresh token still valid after logout")
+			}
+		})
+	}
+}
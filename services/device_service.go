@@ -0,0 +1,68 @@
+package services
+
+import (
+	"NetGuardServer/dto"
+	"NetGuardServer/models"
+	"NetGuardServer/repository"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// DeviceService defines the interface for FCM device token business logic
+type DeviceService interface {
+	RegisterDevice(userID uuid.UUID, req dto.RegisterDeviceRequest) (*models.DeviceToken, error)
+	GetDevices(userID uuid.UUID) ([]models.DeviceToken, error)
+	UnregisterDevice(userID uuid.UUID, token string) error
+}
+
+// deviceService implements DeviceService
+type deviceService struct {
+	deviceTokenRepo repository.DeviceTokenRepository
+}
+
+// NewDeviceService creates a new device service instance
+func NewDeviceService(deviceTokenRepo repository.DeviceTokenRepository) DeviceService {
+	return &deviceService{
+		deviceTokenRepo: deviceTokenRepo,
+	}
+}
+
+// RegisterDevice registers the FCM token of a device of the user. Clients register
+// on every start and whenever FCM rotates the token.
+func (s *deviceService) RegisterDevice(userID uuid.UUID, req dto.RegisterDeviceRequest) (*models.DeviceToken, error) {
+	token := &models.DeviceToken{
+		UserID:     userID,
+		Token:      req.Token,
+		Platform:   req.Platform,
+		AppVersion: req.AppVersion,
+	}
+	if err := s.deviceTokenRepo.Upsert(token); err != nil {
+		return nil, errors.New("failed to register device")
+	}
+
+	return token, nil
+}
+
+// GetDevices gets the registered devices of the user
+func (s *deviceService) GetDevices(userID uuid.UUID) ([]models.DeviceToken, error) {
+	tokens, err := s.deviceTokenRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, errors.New("failed to get devices")
+	}
+
+	return tokens, nil
+}
+
+// UnregisterDevice unregisters a device of the user, e.g. on sign out
+func (s *deviceService) UnregisterDevice(userID uuid.UUID, token string) error {
+	deleted, err := s.deviceTokenRepo.DeleteByToken(userID, token)
+	if err != nil {
+		return errors.New("failed to unregister device")
+	}
+	if !deleted {
+		return errors.New("device not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,333 @@
+package services
+
+import (
+	"NetGuardServer/dto"
+	"NetGuardServer/models"
+	"NetGuardServer/repository"
+	"NetGuardServer/utils"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EscalationService defines the interface for escalation policy business logic
+type EscalationService interface {
+	CreatePolicy(userID uuid.UUID, req dto.CreateEscalationPolicyRequest) (*models.EscalationPolicy, error)
+	GetPolicies() ([]models.EscalationPolicy, error)
+	GetPolicyByID(id uuid.UUID) (*models.EscalationPolicy, error)
+	UpdatePolicy(id, userID uuid.UUID, req dto.UpdateEscalationPolicyRequest) (*models.EscalationPolicy, error)
+	DeletePolicy(id, userID uuid.UUID) error
+	StartEscalation(history *models.ServerDownHistory, policyID *uuid.UUID) error
+	RunDue(ctx context.Context)
+}
+
+// escalationService implements EscalationService
+type escalationService struct {
+	escalationRepo      repository.EscalationRepository
+	historyRepo         repository.HistoryRepository
+	userRepo            repository.UserRepository
+	historyService      HistoryService
+	notificationService NotificationService
+	onCallService       OnCallService
+}
+
+// NewEscalationService creates a new escalation service instance
+func NewEscalationService(escalationRepo repository.EscalationRepository, historyRepo repository.HistoryRepository, userRepo repository.UserRepository, historyService HistoryService, notificationService NotificationService, onCallService OnCallService) EscalationService {
+	return &escalationService{
+		escalationRepo:      escalationRepo,
+		historyRepo:         historyRepo,
+		userRepo:            userRepo,
+		historyService:      historyService,
+		notificationService: notificationService,
+		onCallService:       onCallService,
+	}
+}
+
+// CreatePolicy handles escalation policy creation business logic
+func (s *escalationService) CreatePolicy(userID uuid.UUID, req dto.CreateEscalationPolicyRequest) (*models.EscalationPolicy, error) {
+	levels, err := s.toLevels(req.Levels)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &models.EscalationPolicy{
+		Name:        req.Name,
+		Description: req.Description,
+		Levels:      levels,
+		CreatedBy:   userID,
+	}
+	if err := s.escalationRepo.Create(policy); err != nil {
+		return nil, errors.New("failed to create escalation policy")
+	}
+
+	return s.GetPolicyByID(policy.ID)
+}
+
+// GetPolicies gets all escalation policies
+func (s *escalationService) GetPolicies() ([]models.EscalationPolicy, error) {
+	policies, err := s.escalationRepo.FindAll()
+	if err != nil {
+		return nil, errors.New("failed to get escalation policies")
+	}
+	return policies, nil
+}
+
+// GetPolicyByID gets an escalation policy by ID
+func (s *escalationService) GetPolicyByID(id uuid.UUID) (*models.EscalationPolicy, error) {
+	policy, err := s.escalationRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("escalation policy not found")
+	}
+	return policy, nil
+}
+
+// UpdatePolicy updates an escalation policy. Incidents already escalating with the policy
+// continue with its new levels.
+func (s *escalationService) UpdatePolicy(id, userID uuid.UUID, req dto.UpdateEscalationPolicyRequest) (*models.EscalationPolicy, error) {
+	policy, err := s.escalationRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("escalation policy not found")
+	}
+
+	if policy.CreatedBy != userID {
+		return nil, errors.New("access denied")
+	}
+
+	// Update fields if provided
+	if req.Name != "" {
+		policy.Name = req.Name
+	}
+	if req.Description != nil {
+		policy.Description = *req.Description
+	}
+
+	var levels []models.EscalationLevel
+	if req.Levels != nil {
+		if levels, err = s.toLevels(req.Levels); err != nil {
+			return nil, err
+		}
+	}
+
+	policy.Levels = nil
+	if err := s.escalationRepo.Update(policy, levels); err != nil {
+		return nil, errors.New("failed to update escalation policy")
+	}
+
+	return s.GetPolicyByID(policy.ID)
+}
+
+// DeletePolicy deletes an escalation policy; its servers no longer escalate
+func (s *escalationService) DeletePolicy(id, userID uuid.UUID) error {
+	policy, err := s.escalationRepo.FindByID(id)
+	if err != nil {
+		return errors.New("escalation policy not found")
+	}
+
+	if policy.CreatedBy != userID {
+		return errors.New("access denied")
+	}
+
+	if err := s.escalationRepo.Delete(id); err != nil {
+		return errors.New("failed to delete escalation policy")
+	}
+
+	return nil
+}
+
+// StartEscalation schedules the first level of an escalation policy for a newly opened incident.
+// The schedule is stored on the incident, so pending escalations survive restarts.
+func (s *escalationService) StartEscalation(history *models.ServerDownHistory, policyID *uuid.UUID) error {
+	if policyID == nil {
+		return nil
+	}
+
+	policy, err := s.escalationRepo.FindByID(*policyID)
+	if err != nil {
+		return errors.New("escalation policy not found")
+	}
+	if len(policy.Levels) == 0 {
+		return nil
+	}
+
+	nextAt := time.Now().Add(time.Duration(policy.Levels[0].DelayMinutes) * time.Minute)
+	history.EscalationPolicyID = &policy.ID
+	history.EscalationLevel = 0
+	history.NextEscalationAt = &nextAt
+
+	if _, err := s.historyRepo.UpdateEscalation(history); err != nil {
+		return errors.New("failed to schedule escalation")
+	}
+	return nil
+}
+
+// RunDue notifies the next level of every incident whose escalation is due
+func (s *escalationService) RunDue(ctx context.Context) {
+	now := time.Now()
+	histories, err := s.historyRepo.FindDueEscalations(now)
+	if err != nil {
+		log.Printf("ERROR: Failed to load due escalations: %v", err)
+		return
+	}
+
+	for i := range histories {
+		if ctx.Err() != nil {
+			return
+		}
+		s.escalate(&histories[i], now)
+	}
+}
+
+// escalate notifies the targets of the next level of an incident and schedules the level after it.
+// The schedule is advanced before notifying, so an incident acknowledged in the meantime is skipped
+// and a failing notification is not retried in a loop.
+func (s *escalationService) escalate(history *models.ServerDownHistory, now time.Time) {
+	var levels []models.EscalationLevel
+	if history.EscalationPolicyID != nil {
+		if policy, err := s.escalationRepo.FindByID(*history.EscalationPolicyID); err == nil {
+			levels = policy.Levels
+		}
+	}
+
+	if history.EscalationLevel >= len(levels) {
+		// Policy deleted or shortened: nothing left to escalate
+		history.NextEscalationAt = nil
+		if _, err := s.historyRepo.UpdateEscalation(history); err != nil {
+			log.Printf("ERROR: Failed to stop escalation of history %s: %v", history.ID, err)
+		}
+		return
+	}
+
+	level := levels[history.EscalationLevel]
+	history.EscalationLevel++
+	history.NextEscalationAt = nil
+	if history.EscalationLevel < len(levels) {
+		nextAt := now.Add(time.Duration(levels[history.EscalationLevel].DelayMinutes) * time.Minute)
+		history.NextEscalationAt = &nextAt
+	}
+
+	updated, err := s.historyRepo.UpdateEscalation(history)
+	if err != nil {
+		log.Printf("ERROR: Failed to advance escalation of history %s: %v", history.ID, err)
+		return
+	}
+	if !updated {
+		return
+	}
+
+	targets, err := s.resolveTargets(level)
+	if err != nil {
+		log.Printf("ERROR: Failed to resolve escalation targets of history %s: %v", history.ID, err)
+	}
+
+	userIDs := make([]uuid.UUID, len(targets))
+	names := make([]string, len(targets))
+	for i, user := range targets {
+		userIDs[i] = user.ID
+		names[i] = user.Name
+	}
+
+	message := fmt.Sprintf("Escalated to level %d: %s", level.Level, strings.Join(names, ", "))
+	if len(targets) == 0 {
+		message = fmt.Sprintf("Escalated to level %d: no users to notify", level.Level)
+	}
+	log.Printf("INFO: Incident %s of %s escalated to level %d (%d users)", history.ID, history.ServerName, level.Level, len(targets))
+
+	if len(targets) > 0 {
+		err = s.notificationService.SendEscalationNotification(history, level.Level, userIDs)
+		if err != nil {
+			log.Printf("ERROR: Failed to queue escalation notification for history %s: %v", history.ID, err)
+			message += " (notification failed)"
+		}
+	}
+
+	if err := s.historyService.AddEvent(history.ID, models.HistoryEventEscalated, models.SystemUserID, message); err != nil {
+		log.Printf("ERROR: Failed to record escalation event for history %s: %v", history.ID, err)
+	}
+}
+
+// resolveTargets finds the users a level notifies: its target users, the users on call
+// for its target schedules and the active users of its target divisions, without duplicates
+func (s *escalationService) resolveTargets(level models.EscalationLevel) ([]models.User, error) {
+	onCall, err := s.onCallService.OnCallUsers(level.TargetSchedules, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.userRepo.FindByIDs(append(append([]uuid.UUID(nil), level.TargetUserIDs...), onCall...))
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.userRepo.FindActiveByDivisions(level.TargetDivisions)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uuid.UUID]bool, len(users)+len(members))
+	targets := make([]models.User, 0, len(users)+len(members))
+	for _, user := range append(users, members...) {
+		if !seen[user.ID] {
+			seen[user.ID] = true
+			targets = append(targets, user)
+		}
+	}
+	return targets, nil
+}
+
+// toLevels converts the levels of a request into the model, numbered in order,
+// and checks that every level has a target and every target user exists
+func (s *escalationService) toLevels(req []dto.EscalationLevelRequest) ([]models.EscalationLevel, error) {
+	levels := make([]models.EscalationLevel, len(req))
+	for i, levelReq := range req {
+		if len(levelReq.TargetUserIDs) == 0 && len(levelReq.TargetDivisions) == 0 && len(levelReq.TargetSchedules) == 0 {
+			return nil, utils.ValidationError(fmt.Sprintf("level %d needs target_user_ids, target_divisions or target_schedule_ids", i+1))
+		}
+
+		userIDs := make([]uuid.UUID, 0, len(levelReq.TargetUserIDs))
+		for _, id := range levelReq.TargetUserIDs {
+			userID, err := uuid.Parse(id)
+			if err != nil {
+				return nil, utils.ValidationError("invalid user ID " + id)
+			}
+			userIDs = append(userIDs, userID)
+		}
+
+		users, err := s.userRepo.FindByIDs(userIDs)
+		if err != nil {
+			return nil, errors.New("failed to get users")
+		}
+		found := make(map[uuid.UUID]bool, len(users))
+		for _, user := range users {
+			found[user.ID] = true
+		}
+		for _, userID := range userIDs {
+			if !found[userID] {
+				return nil, utils.ValidationError("user " + userID.String() + " not found")
+			}
+		}
+
+		scheduleIDs := make([]uuid.UUID, 0, len(levelReq.TargetSchedules))
+		for _, id := range levelReq.TargetSchedules {
+			scheduleID, err := uuid.Parse(id)
+			if err != nil {
+				return nil, utils.ValidationError("invalid on-call schedule ID " + id)
+			}
+			if _, err := s.onCallService.GetScheduleByID(scheduleID); err != nil {
+				return nil, utils.ValidationError("on-call schedule " + id + " not found")
+			}
+			scheduleIDs = append(scheduleIDs, scheduleID)
+		}
+
+		levels[i] = models.EscalationLevel{
+			Level:           i + 1,
+			DelayMinutes:    levelReq.DelayMinutes,
+			TargetUserIDs:   userIDs,
+			TargetDivisions: levelReq.TargetDivisions,
+			TargetSchedules: scheduleIDs,
+		}
+	}
+	return levels, nil
+}
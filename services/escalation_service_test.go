@@ -0,0 +1,143 @@
+package services
+
+import (
+	"NetGuardServer/models"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// changedHistoryRepository applies a change to the records after the due escalations were loaded,
+// like a user acting on the incident while the escalation worker runs
+type changedHistoryRepository struct {
+	*fakeHistoryRepository
+	change func()
+}
+
+func (r *changedHistoryRepository) FindDueEscalations(now time.Time) ([]models.ServerDownHistory, error) {
+	due, err := r.fakeHistoryRepository.FindDueEscalations(now)
+	if r.change != nil {
+		r.change()
+	}
+	return due, err
+}
+
+func TestEscalationRunDue(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	policy := models.EscalationPolicy{ID: uuid.New(), Levels: []models.EscalationLevel{
+		{Level: 1, DelayMinutes: 0, TargetUserIDs: []uuid.UUID{alice}},
+		{Level: 2, DelayMinutes: 10, TargetUserIDs: []uuid.UUID{bob}},
+	}}
+
+	tests := []struct {
+		name      string
+		level     int                                       // levels notified before the run
+		deleted   bool                                      // policy deleted since the escalation started
+		change    func(mt *monitorTest, id uuid.UUID) error // applied once the due escalations were loaded
+		wantLevel int                                       // level notified by the run, 0 for none
+		wantNext  bool                                      // whether a later level is scheduled
+	}{
+		{name: "first level schedules the next", wantLevel: 1, wantNext: true},
+		{name: "last level ends the schedule", level: 1, wantLevel: 2},
+		{name: "deleted policy ends the schedule", deleted: true},
+		{
+			name: "acknowledged in the meantime",
+			change: func(mt *monitorTest, id uuid.UUID) error {
+				_, err := mt.history.AcknowledgeHistory(id, alice)
+				return err
+			},
+		},
+		{
+			name: "resolved in the meantime",
+			change: func(mt *monitorTest, id uuid.UUID) error {
+				_, err := mt.history.ResolveHistory(id, alice, "Restarted")
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer()
+			mt := newMonitorTest(server)
+			if _, err := mt.report(server.ID, models.ServerStatusDown, alice); err != nil {
+				t.Fatalf("report: %v", err)
+			}
+			id := mt.historyRepo.histories[0].ID
+			due := time.Now().Add(-time.Minute)
+			mt.historyRepo.histories[0].EscalationPolicyID = &policy.ID
+			mt.historyRepo.histories[0].EscalationLevel = tt.level
+			mt.historyRepo.histories[0].NextEscalationAt = &due
+
+			policies := &fakeEscalationRepository{policies: map[uuid.UUID]models.EscalationPolicy{}}
+			if !tt.deleted {
+				policies.policies[policy.ID] = policy
+			}
+			historyRepo := &changedHistoryRepository{fakeHistoryRepository: mt.historyRepo}
+			if tt.change != nil {
+				historyRepo.change = func() {
+					if err := tt.change(mt, id); err != nil {
+						t.Errorf("change: %v", err)
+					}
+				}
+			}
+			service := NewEscalationService(policies, historyRepo, &fakeUserRepository{}, mt.history, mt.notifications, &fakeOnCallService{})
+
+			// The schedule must already be advanced when the level is notified
+			var notified *models.ServerDownHistory
+			mt.notifications.onEscalation = func(level int) {
+				notified, _ = mt.historyRepo.FindByID(id)
+			}
+			before := time.Now()
+			service.RunDue(context.Background())
+
+			stored := mt.historyRepo.histories[0]
+			if tt.wantLevel == 0 {
+				if len(mt.notifications.escalations) != 0 {
+					t.Errorf("notified levels %v, want none", mt.notifications.escalations)
+				}
+				if stored.EscalationLevel != tt.level {
+					t.Errorf("escalation level = %d, want %d", stored.EscalationLevel, tt.level)
+				}
+				if tt.change == nil && stored.NextEscalationAt != nil {
+					t.Errorf("next escalation at %v, want none", stored.NextEscalationAt)
+				}
+				for _, event := range mt.eventRepo.events {
+					if event.Type == models.HistoryEventEscalated {
+						t.Errorf("escalation event %q recorded, want none", event.Message)
+					}
+				}
+				return
+			}
+
+			if len(mt.notifications.escalations) != 1 || mt.notifications.escalations[0] != tt.wantLevel {
+				t.Fatalf("notified levels %v, want [%d]", mt.notifications.escalations, tt.wantLevel)
+			}
+			if notified.EscalationLevel != tt.wantLevel {
+				t.Errorf("escalation level when notified = %d, want %d", notified.EscalationLevel, tt.wantLevel)
+			}
+			if tt.wantNext {
+				wantAt := before.Add(10 * time.Minute)
+				if notified.NextEscalationAt == nil || notified.NextEscalationAt.Before(wantAt) || notified.NextEscalationAt.After(time.Now().Add(10*time.Minute)) {
+					t.Errorf("next escalation when notified at %v, want about %v", notified.NextEscalationAt, wantAt)
+				}
+			} else if notified.NextEscalationAt != nil {
+				t.Errorf("next escalation when notified at %v, want none", notified.NextEscalationAt)
+			}
+
+			var escalated []string
+			for _, event := range mt.eventRepo.events {
+				if event.Type == models.HistoryEventEscalated {
+					escalated = append(escalated, event.Message)
+				}
+			}
+			if len(escalated) != 1 || !strings.HasPrefix(escalated[0], fmt.Sprintf("Escalated to level %d:", tt.wantLevel)) {
+				t.Errorf("escalation events %q, want one for level %d", escalated, tt.wantLevel)
+			}
+		})
+	}
+}
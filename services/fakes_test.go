@@ -0,0 +1,833 @@
+package services
+
+import (
+	"NetGuardServer/models"
+	"NetGuardServer/repository"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// In-memory fakes of the repositories and services the service tests depend on.
+// Each fake embeds its interface, so calling a method a test does not expect panics.
+
+// fakeServerRepository stores servers and their dependencies in memory
+type fakeServerRepository struct {
+	repository.ServerRepository
+	mu           sync.Mutex
+	servers      map[uuid.UUID]models.Server
+	dependencies []models.ServerDependency
+}
+
+func newFakeServerRepository(servers ...models.Server) *fakeServerRepository {
+	repo := &fakeServerRepository{servers: make(map[uuid.UUID]models.Server)}
+	for _, server := range servers {
+		repo.servers[server.ID] = server
+	}
+	return repo
+}
+
+// persisted returns the columns of a server, without its non-persisted fields
+func persisted(server *models.Server) models.Server {
+	columns := *server
+	columns.IssuedHeartbeatToken = ""
+	columns.DependsOn = nil
+	return columns
+}
+
+func (r *fakeServerRepository) Create(server *models.Server) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	server.ID = uuid.New()
+	r.servers[server.ID] = persisted(server)
+	return nil
+}
+
+func (r *fakeServerRepository) FindByID(id uuid.UUID) (*models.Server, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	server, ok := r.servers[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return &server, nil
+}
+
+func (r *fakeServerRepository) FindByIDs(ids []uuid.UUID) ([]models.Server, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var servers []models.Server
+	for _, id := range ids {
+		if server, ok := r.servers[id]; ok {
+			servers = append(servers, server)
+		}
+	}
+	return servers, nil
+}
+
+func (r *fakeServerRepository) Update(server *models.Server) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.servers[server.ID] = persisted(server)
+	return nil
+}
+
+func (r *fakeServerRepository) FindOverdueHeartbeats(now time.Time) ([]models.Server, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var servers []models.Server
+	for _, server := range r.servers {
+		if server.CheckType == models.CheckTypeHeartbeat && server.HeartbeatExpectedBy != nil &&
+			server.HeartbeatExpectedBy.Before(now) && server.Status != models.ServerStatusDown {
+			servers = append(servers, server)
+		}
+	}
+	return servers, nil
+}
+
+func (r *fakeServerRepository) UpdateStatus(server *models.Server) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	columns := r.servers[server.ID]
+	columns.Status = server.Status
+	columns.ResponseTime = server.ResponseTime
+	columns.LastChecked = server.LastChecked
+	columns.LastCheckedBy = server.LastCheckedBy
+	columns.LastCheckSource = server.LastCheckSource
+	columns.SuspectedSince = server.SuspectedSince
+	columns.Flapping = server.Flapping
+	columns.FlappingSince = server.FlappingSince
+	r.servers[server.ID] = columns
+	return nil
+}
+
+func (r *fakeServerRepository) IncrementCounter(server *models.Server, status string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	columns, ok := r.servers[server.ID]
+	if !ok {
+		return errors.New("record not found")
+	}
+	if status == models.ServerStatusUp {
+		columns.ConsecutiveSuccesses++
+		columns.ConsecutiveFailures = 0
+	} else {
+		columns.ConsecutiveFailures++
+		columns.ConsecutiveSuccesses = 0
+	}
+	r.servers[server.ID] = columns
+	server.ConsecutiveFailures = columns.ConsecutiveFailures
+	server.ConsecutiveSuccesses = columns.ConsecutiveSuccesses
+	return nil
+}
+
+func (r *fakeServerRepository) FindDependencies(serverID uuid.UUID) ([]uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var dependsOn []uuid.UUID
+	for _, dependency := range r.dependencies {
+		if dependency.ServerID == serverID {
+			dependsOn = append(dependsOn, dependency.DependsOnID)
+		}
+	}
+	return dependsOn, nil
+}
+
+func (r *fakeServerRepository) FindAllDependencies() ([]models.ServerDependency, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.dependencies), nil
+}
+
+// fakeCheckResultRepository stores check results in memory
+type fakeCheckResultRepository struct {
+	repository.CheckResultRepository
+	mu      sync.Mutex
+	results []models.CheckResult
+}
+
+func (r *fakeCheckResultRepository) Create(result *models.CheckResult) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results = append(r.results, *result)
+	return nil
+}
+
+func (r *fakeCheckResultRepository) FindReporters(serverID uuid.UUID, status string, since time.Time) ([]uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	seen := make(map[uuid.UUID]bool)
+	var reporters []uuid.UUID
+	for _, result := range r.results {
+		if result.ServerID == serverID && result.Status == status && !result.CheckedAt.Before(since) && !seen[result.ReportedBy] {
+			seen[result.ReportedBy] = true
+			reporters = append(reporters, result.ReportedBy)
+		}
+	}
+	return reporters, nil
+}
+
+// fakeHistoryRepository stores history records, their reporters and the notifications
+// queued with them in memory, with the open-incident semantics of CreateOrAttach
+type fakeHistoryRepository struct {
+	repository.HistoryRepository
+	mu            sync.Mutex
+	histories     []*models.ServerDownHistory
+	notifications []models.NotificationOutbox
+}
+
+func (r *fakeHistoryRepository) CreateOrAttach(history *models.ServerDownHistory, reportedBy uuid.UUID, notifications []models.NotificationOutbox) (*models.ServerDownHistory, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+
+	for _, open := range r.histories {
+		if open.ServerID != history.ServerID || open.ResolvedAt != nil {
+			continue
+		}
+		known := false
+		for i := range open.Reporters {
+			if open.Reporters[i].UserID == reportedBy {
+				open.Reporters[i].ReportCount++
+				open.Reporters[i].LastReportedAt = now
+				known = true
+			}
+		}
+		if !known {
+			open.Reporters = append(open.Reporters, models.HistoryReporter{
+				HistoryID: open.ID, UserID: reportedBy, ReportCount: 1, FirstReportedAt: now, LastReportedAt: now,
+			})
+			open.ReporterCount++
+		}
+		open.LastReportedAt = &now
+		result := *open
+		return &result, false, nil
+	}
+
+	history.ID = uuid.New()
+	history.Timestamp = now
+	history.ReporterCount = 1
+	history.LastReportedAt = &now
+	history.Reporters = []models.HistoryReporter{{
+		HistoryID: history.ID, UserID: reportedBy, ReportCount: 1, FirstReportedAt: now, LastReportedAt: now,
+	}}
+	stored := *history
+	r.histories = append(r.histories, &stored)
+	for i := range notifications {
+		notifications[i].HistoryID = &stored.ID
+	}
+	r.notifications = append(r.notifications, notifications...)
+	return history, true, nil
+}
+
+func (r *fakeHistoryRepository) FindByID(id uuid.UUID) (*models.ServerDownHistory, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, history := range r.histories {
+		if history.ID == id {
+			result := *history
+			return &result, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+// update applies a column-scoped update to the stored record if it passes the guard
+func (r *fakeHistoryRepository) update(id uuid.UUID, guard func(*models.ServerDownHistory) bool, apply func(*models.ServerDownHistory)) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, stored := range r.histories {
+		if stored.ID == id {
+			if !guard(stored) {
+				return false, nil
+			}
+			apply(stored)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeHistoryRepository) Acknowledge(history *models.ServerDownHistory) (bool, error) {
+	return r.update(history.ID, func(stored *models.ServerDownHistory) bool {
+		return stored.AcknowledgedAt == nil && stored.ResolvedAt == nil
+	}, func(stored *models.ServerDownHistory) {
+		stored.Status = history.Status
+		stored.AcknowledgedBy = history.AcknowledgedBy
+		stored.AcknowledgedAt = history.AcknowledgedAt
+		stored.AckSeconds = history.AckSeconds
+		stored.NextEscalationAt = history.NextEscalationAt
+	})
+}
+
+func (r *fakeHistoryRepository) Assign(history *models.ServerDownHistory) (bool, error) {
+	return r.update(history.ID, func(stored *models.ServerDownHistory) bool {
+		return stored.ResolvedAt == nil
+	}, func(stored *models.ServerDownHistory) {
+		stored.AssigneeID = history.AssigneeID
+		stored.AssignedBy = history.AssignedBy
+		stored.AssignedAt = history.AssignedAt
+	})
+}
+
+func (r *fakeHistoryRepository) Resolve(history *models.ServerDownHistory) (bool, error) {
+	return r.update(history.ID, func(stored *models.ServerDownHistory) bool {
+		return stored.ResolvedAt == nil
+	}, func(stored *models.ServerDownHistory) {
+		stored.Status = history.Status
+		stored.ResolvedBy = history.ResolvedBy
+		stored.ResolvedAt = history.ResolvedAt
+		stored.ResolveNote = history.ResolveNote
+		stored.AutoResolved = history.AutoResolved
+		stored.DowntimeSeconds = history.DowntimeSeconds
+		stored.MaintenanceSeconds = history.MaintenanceSeconds
+		stored.NextEscalationAt = history.NextEscalationAt
+	})
+}
+
+func (r *fakeHistoryRepository) AddResolveNote(history *models.ServerDownHistory) (bool, error) {
+	return r.update(history.ID, func(stored *models.ServerDownHistory) bool {
+		return stored.AutoResolved && stored.ResolveNote == ""
+	}, func(stored *models.ServerDownHistory) {
+		stored.ResolvedBy = history.ResolvedBy
+		stored.ResolveNote = history.ResolveNote
+	})
+}
+
+func (r *fakeHistoryRepository) Promote(history *models.ServerDownHistory) (bool, error) {
+	return r.update(history.ID, func(stored *models.ServerDownHistory) bool {
+		return stored.ResolvedAt == nil
+	}, func(stored *models.ServerDownHistory) {
+		if stored.Status == models.HistoryStatusImpacted {
+			stored.Status = models.HistoryStatusDown
+		}
+		stored.ParentID = nil
+	})
+}
+
+func (r *fakeHistoryRepository) FindByPeriod(from, to time.Time) ([]models.ServerDownHistory, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var histories []models.ServerDownHistory
+	for _, history := range r.histories {
+		if !history.Timestamp.Before(from) && history.Timestamp.Before(to) {
+			histories = append(histories, *history)
+		}
+	}
+	return histories, nil
+}
+
+func (r *fakeHistoryRepository) FindOpenByServerID(serverID uuid.UUID) ([]models.ServerDownHistory, error) {
+	return r.FindOpenByServerIDs([]uuid.UUID{serverID})
+}
+
+func (r *fakeHistoryRepository) FindOpenByServerIDs(serverIDs []uuid.UUID) ([]models.ServerDownHistory, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var open []models.ServerDownHistory
+	for _, history := range r.histories {
+		for _, id := range serverIDs {
+			if history.ServerID == id && history.ResolvedAt == nil {
+				open = append(open, *history)
+			}
+		}
+	}
+	return open, nil
+}
+
+func (r *fakeHistoryRepository) CountStateChanges(serverID uuid.UUID, since time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *fakeHistoryRepository) FindDueEscalations(now time.Time) ([]models.ServerDownHistory, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []models.ServerDownHistory
+	for _, history := range r.histories {
+		if history.NextEscalationAt != nil && !history.NextEscalationAt.After(now) && history.AcknowledgedAt == nil && history.ResolvedAt == nil {
+			due = append(due, *history)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakeHistoryRepository) UpdateEscalation(history *models.ServerDownHistory) (bool, error) {
+	return r.update(history.ID, func(stored *models.ServerDownHistory) bool {
+		return stored.AcknowledgedAt == nil && stored.ResolvedAt == nil
+	}, func(stored *models.ServerDownHistory) {
+		stored.EscalationPolicyID = history.EscalationPolicyID
+		stored.EscalationLevel = history.EscalationLevel
+		stored.NextEscalationAt = history.NextEscalationAt
+	})
+}
+
+// fakeHistoryEventRepository stores timeline events in memory
+type fakeHistoryEventRepository struct {
+	repository.HistoryEventRepository
+	mu     sync.Mutex
+	events []models.HistoryEvent
+}
+
+func (r *fakeHistoryEventRepository) Create(event *models.HistoryEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, *event)
+	return nil
+}
+
+// eventTypes returns the types of the recorded events in order
+func (r *fakeHistoryEventRepository) eventTypes() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	types := make([]string, len(r.events))
+	for i, event := range r.events {
+		types[i] = event.Type
+	}
+	return types
+}
+
+// fakeNotificationService records the server alerts it is asked to build or send
+type fakeNotificationService struct {
+	NotificationService
+	mu        sync.Mutex
+	down      int
+	recovered int
+
+	escalations  []int           // levels of the escalation notifications sent
+	onEscalation func(level int) // called with each escalation notification, if set
+}
+
+func (s *fakeNotificationService) ServerDownNotifications(server *models.Server, reportedBy uuid.UUID, recipients []uuid.UUID) []models.NotificationOutbox {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.down++
+	serverID := server.ID
+	return []models.NotificationOutbox{{
+		Target:   models.OutboxTargetPush,
+		Event:    models.NotificationEventDown,
+		ServerID: &serverID,
+		Status:   models.OutboxStatusPending,
+	}}
+}
+
+func (s *fakeNotificationService) SendServerDownNotification(historyID uuid.UUID, server *models.Server, reportedBy uuid.UUID, recipients []uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.down++
+	return nil
+}
+
+func (s *fakeNotificationService) SendServerRecoveredNotification(historyID uuid.UUID, server *models.Server, downtime time.Duration, recipients []uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recovered++
+	return nil
+}
+
+func (s *fakeNotificationService) SendIncidentAcknowledgedNotification(history *models.ServerDownHistory, server *models.Server, acknowledgedBy *models.User, recipients []uuid.UUID) error {
+	return nil
+}
+
+func (s *fakeNotificationService) SendIncidentResolvedNotification(history *models.ServerDownHistory, server *models.Server, resolvedBy *models.User, recipients []uuid.UUID) error {
+	return nil
+}
+
+func (s *fakeNotificationService) SendIncidentAssignedNotification(history *models.ServerDownHistory, assignee *models.User, assignedBy string) error {
+	return nil
+}
+
+func (s *fakeNotificationService) SendEscalationNotification(history *models.ServerDownHistory, level int, userIDs []uuid.UUID) error {
+	if s.onEscalation != nil {
+		s.onEscalation(level)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.escalations = append(s.escalations, level)
+	return nil
+}
+
+// fakeUserRepository finds every user it is asked for, active and with the role in roles or USER.
+// The users in active are the active ones.
+type fakeUserRepository struct {
+	repository.UserRepository
+	roles  map[uuid.UUID]string
+	active []models.User
+}
+
+func (r *fakeUserRepository) FindActive() ([]models.User, error) {
+	return r.active, nil
+}
+
+func (r *fakeUserRepository) FindByID(id uuid.UUID) (*models.User, error) {
+	role, ok := r.roles[id]
+	if !ok {
+		role = "USER"
+	}
+	return &models.User{ID: id, Name: "Test User", Role: role, IsActive: true}, nil
+}
+
+// fakeMaintenanceService reports every server in or out of maintenance
+type fakeMaintenanceService struct {
+	MaintenanceService
+	inMaintenance bool
+}
+
+func (s *fakeMaintenanceService) InMaintenance(serverID uuid.UUID, at time.Time) bool {
+	return s.inMaintenance
+}
+
+// fakeEscalationService counts the escalations started
+type fakeEscalationService struct {
+	EscalationService
+	mu      sync.Mutex
+	started int
+}
+
+func (s *fakeEscalationService) StartEscalation(history *models.ServerDownHistory, policyID *uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.started++
+	return nil
+}
+
+// fakeOnCallService routes every server alert to everyone
+type fakeOnCallService struct {
+	OnCallService
+}
+
+func (s *fakeOnCallService) ServerRecipients(server *models.Server) []uuid.UUID {
+	return nil
+}
+
+func (s *fakeOnCallService) OnCallUsers(scheduleIDs []uuid.UUID, at time.Time) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+// fakeDeviceTokenRepository stores registered device tokens in memory
+type fakeDeviceTokenRepository struct {
+	repository.DeviceTokenRepository
+	mu      sync.Mutex
+	devices []models.DeviceToken
+}
+
+func (r *fakeDeviceTokenRepository) FindByUserIDs(userIDs []uuid.UUID) ([]models.DeviceToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var devices []models.DeviceToken
+	for _, device := range r.devices {
+		if slices.Contains(userIDs, device.UserID) {
+			devices = append(devices, device)
+		}
+	}
+	return devices, nil
+}
+
+func (r *fakeDeviceTokenRepository) DeleteTokens(tokens []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.devices = slices.DeleteFunc(r.devices, func(device models.DeviceToken) bool {
+		return slices.Contains(tokens, device.Token)
+	})
+	return nil
+}
+
+// fakeNotificationChannelRepository stores notification channels in memory
+type fakeNotificationChannelRepository struct {
+	repository.NotificationChannelRepository
+	channels map[uuid.UUID]models.NotificationChannel
+}
+
+func (r *fakeNotificationChannelRepository) Create(channel *models.NotificationChannel, serverIDs []uuid.UUID) error {
+	channel.ID = uuid.New()
+	r.channels[channel.ID] = *channel
+	return nil
+}
+
+func (r *fakeNotificationChannelRepository) FindByID(id uuid.UUID) (*models.NotificationChannel, error) {
+	channel, ok := r.channels[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return &channel, nil
+}
+
+func (r *fakeNotificationChannelRepository) FindEnabled() ([]models.NotificationChannel, error) {
+	var channels []models.NotificationChannel
+	for _, channel := range r.channels {
+		if !channel.Disabled {
+			channels = append(channels, channel)
+		}
+	}
+	return channels, nil
+}
+
+func (r *fakeNotificationChannelRepository) Update(channel *models.NotificationChannel, serverIDs []uuid.UUID) error {
+	r.channels[channel.ID] = *channel
+	return nil
+}
+
+func (r *fakeUserRepository) FindActiveByDivisions(divisions []string) ([]models.User, error) {
+	return nil, nil
+}
+
+func (r *fakeUserRepository) FindByIDs(ids []uuid.UUID) ([]models.User, error) {
+	users := make([]models.User, 0, len(ids))
+	for _, id := range ids {
+		user, _ := r.FindByID(id)
+		users = append(users, *user)
+	}
+	return users, nil
+}
+
+// fakeNotificationOutboxRepository records the delivery attempts of outbox notifications
+type fakeNotificationOutboxRepository struct {
+	repository.NotificationOutboxRepository
+	attempts []models.NotificationAttempt
+}
+
+func (r *fakeNotificationOutboxRepository) SaveAttempt(notification *models.NotificationOutbox, attempt *models.NotificationAttempt) error {
+	r.attempts = append(r.attempts, *attempt)
+	return nil
+}
+
+// fakeNotificationDigestRepository keeps rate limit counters with fixed windows and
+// digest items in memory. Every held back item is due for its digest right away.
+type fakeNotificationDigestRepository struct {
+	repository.NotificationDigestRepository
+	counters map[string]models.NotificationRateCounter
+	items    []models.NotificationDigestItem
+	digests  []models.NotificationOutbox
+}
+
+func (r *fakeNotificationDigestRepository) Hit(keys []string, now time.Time, window time.Duration) (map[string]int, error) {
+	counts := make(map[string]int, len(keys))
+	for _, key := range keys {
+		counter, ok := r.counters[key]
+		if !ok || !counter.WindowStart.After(now.Add(-window)) {
+			counter = models.NotificationRateCounter{Key: key, WindowStart: now}
+		}
+		counter.Count++
+		r.counters[key] = counter
+		counts[key] = counter.Count
+	}
+	return counts, nil
+}
+
+func (r *fakeNotificationDigestRepository) Defer(notification *models.NotificationOutbox, items []models.NotificationDigestItem) error {
+	for _, item := range items {
+		item.ID = uuid.New()
+		item.CreatedAt = time.Now()
+		r.items = append(r.items, item)
+	}
+	return nil
+}
+
+func (r *fakeNotificationDigestRepository) FindDue(before time.Time) ([]models.NotificationDigestItem, error) {
+	return slices.Clone(r.items), nil
+}
+
+func (r *fakeNotificationDigestRepository) Flush(items []models.NotificationDigestItem, digest *models.NotificationOutbox) (bool, error) {
+	r.items = slices.DeleteFunc(r.items, func(stored models.NotificationDigestItem) bool {
+		return slices.ContainsFunc(items, func(item models.NotificationDigestItem) bool { return item.ID == stored.ID })
+	})
+	if digest != nil {
+		r.digests = append(r.digests, *digest)
+	}
+	return true, nil
+}
+
+// fakeNotificationTemplateRepository has no stored templates, the built-in ones are used
+type fakeNotificationTemplateRepository struct {
+	repository.NotificationTemplateRepository
+}
+
+func (r *fakeNotificationTemplateRepository) Find(event, channel, locale string) (*models.NotificationTemplate, error) {
+	return nil, errors.New("record not found")
+}
+
+// fakeNotificationPreferenceRepository returns the stored notification preferences
+type fakeNotificationPreferenceRepository struct {
+	repository.NotificationPreferenceRepository
+	preferences []models.NotificationPreference
+}
+
+func (r *fakeNotificationPreferenceRepository) FindByUserIDs(userIDs []uuid.UUID) ([]models.NotificationPreference, error) {
+	var preferences []models.NotificationPreference
+	for _, preference := range r.preferences {
+		if slices.Contains(userIDs, preference.UserID) {
+			preferences = append(preferences, preference)
+		}
+	}
+	return preferences, nil
+}
+
+// fakeMaintenanceRepository stores maintenance windows in memory
+type fakeMaintenanceRepository struct {
+	repository.MaintenanceRepository
+	windows map[uuid.UUID]models.MaintenanceWindow
+}
+
+func (r *fakeMaintenanceRepository) Create(window *models.MaintenanceWindow, serverIDs []uuid.UUID) error {
+	window.ID = uuid.New()
+	for _, serverID := range serverIDs {
+		window.Servers = append(window.Servers, models.Server{ID: serverID})
+	}
+	r.windows[window.ID] = *window
+	return nil
+}
+
+func (r *fakeMaintenanceRepository) FindByID(id uuid.UUID) (*models.MaintenanceWindow, error) {
+	window, ok := r.windows[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return &window, nil
+}
+
+func (r *fakeMaintenanceRepository) FindAll(serverID *uuid.UUID) ([]models.MaintenanceWindow, error) {
+	var windows []models.MaintenanceWindow
+	for _, window := range r.windows {
+		if serverID == nil || slices.ContainsFunc(window.Servers, func(s models.Server) bool { return s.ID == *serverID }) {
+			windows = append(windows, window)
+		}
+	}
+	return windows, nil
+}
+
+func (r *fakeMaintenanceRepository) FindByServerID(serverID uuid.UUID) ([]models.MaintenanceWindow, error) {
+	return r.FindAll(&serverID)
+}
+
+func (r *fakeMaintenanceRepository) Update(window *models.MaintenanceWindow, serverIDs []uuid.UUID) error {
+	for _, serverID := range serverIDs {
+		window.Servers = append(window.Servers, models.Server{ID: serverID})
+	}
+	r.windows[window.ID] = *window
+	return nil
+}
+
+// fakeTokenRepository stores refresh tokens and revoked access tokens in memory
+type fakeTokenRepository struct {
+	repository.TokenRepository
+	refreshTokens []*models.RefreshToken
+	revoked       map[uuid.UUID]models.RevokedToken
+}
+
+func newFakeTokenRepository() *fakeTokenRepository {
+	return &fakeTokenRepository{revoked: make(map[uuid.UUID]models.RevokedToken)}
+}
+
+func (r *fakeTokenRepository) CreateRefreshToken(token *models.RefreshToken) error {
+	token.ID = uuid.New()
+	r.refreshTokens = append(r.refreshTokens, token)
+	return nil
+}
+
+func (r *fakeTokenRepository) FindRefreshTokenByHash(hash string) (*models.RefreshToken, error) {
+	for _, token := range r.refreshTokens {
+		if token.TokenHash == hash {
+			stored := *token
+			return &stored, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeTokenRepository) FindRefreshTokenByAccessTokenID(tokenID uuid.UUID) (*models.RefreshToken, error) {
+	for _, token := range r.refreshTokens {
+		if token.AccessTokenID == tokenID {
+			stored := *token
+			return &stored, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeTokenRepository) Rotate(used *models.RefreshToken, next *models.RefreshToken, now time.Time) (bool, error) {
+	for _, token := range r.refreshTokens {
+		if token.ID == used.ID {
+			if token.UsedAt != nil || token.RevokedAt != nil {
+				return false, nil
+			}
+			token.UsedAt = &now
+			return true, r.CreateRefreshToken(next)
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeTokenRepository) RevokeFamily(familyID uuid.UUID, now time.Time) error {
+	for _, token := range r.refreshTokens {
+		if token.FamilyID != familyID {
+			continue
+		}
+		if token.AccessExpiresAt.After(now) {
+			r.revoked[token.AccessTokenID] = models.RevokedToken{TokenID: token.AccessTokenID, UserID: token.UserID, ExpiresAt: token.AccessExpiresAt, RevokedAt: now}
+		}
+		if token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeTokenRepository) Revoke(token *models.RevokedToken) error {
+	r.revoked[token.TokenID] = *token
+	return nil
+}
+
+func (r *fakeTokenRepository) IsRevoked(tokenID uuid.UUID) (bool, error) {
+	_, ok := r.revoked[tokenID]
+	return ok, nil
+}
+
+func (r *fakeTokenRepository) DeleteExpired(now time.Time) error {
+	return nil
+}
+
+// fakeOnCallRepository stores on-call schedules in memory
+type fakeOnCallRepository struct {
+	repository.OnCallRepository
+	schedules map[uuid.UUID]models.OnCallSchedule
+}
+
+func (r *fakeOnCallRepository) Create(schedule *models.OnCallSchedule) error {
+	schedule.ID = uuid.New()
+	r.schedules[schedule.ID] = *schedule
+	return nil
+}
+
+func (r *fakeOnCallRepository) FindByID(id uuid.UUID) (*models.OnCallSchedule, error) {
+	schedule, ok := r.schedules[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return &schedule, nil
+}
+
+func (r *fakeOnCallRepository) FindByIDs(ids []uuid.UUID) ([]models.OnCallSchedule, error) {
+	var schedules []models.OnCallSchedule
+	for _, id := range ids {
+		if schedule, ok := r.schedules[id]; ok {
+			schedules = append(schedules, schedule)
+		}
+	}
+	return schedules, nil
+}
+
+// fakeEscalationRepository stores escalation policies in memory
+type fakeEscalationRepository struct {
+	repository.EscalationRepository
+	policies map[uuid.UUID]models.EscalationPolicy
+}
+
+func (r *fakeEscalationRepository) FindByID(id uuid.UUID) (*models.EscalationPolicy, error) {
+	policy, ok := r.policies[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return &policy, nil
+}
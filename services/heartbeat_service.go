@@ -0,0 +1,164 @@
+package services
+
+import (
+	"NetGuardServer/dto"
+	"NetGuardServer/models"
+	"NetGuardServer/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxHeartbeatLogBytes limits the log tail stored with a heartbeat ping
+const maxHeartbeatLogBytes = 10 * 1024
+
+// HeartbeatService defines the interface for heartbeat (push) monitor business logic
+type HeartbeatService interface {
+	RecordPing(token string, req dto.HeartbeatPingRequest, remoteAddr string) (*models.HeartbeatPing, error)
+	CheckMissed(ctx context.Context)
+	GetPings(serverID uuid.UUID, historyID *uuid.UUID, limit int) ([]models.HeartbeatPing, error)
+}
+
+// heartbeatService implements HeartbeatService
+type heartbeatService struct {
+	serverRepo         repository.ServerRepository
+	heartbeatRepo      repository.HeartbeatRepository
+	historyRepo        repository.HistoryRepository
+	monitorService     MonitorService
+	maintenanceService MaintenanceService
+}
+
+// NewHeartbeatService creates a new heartbeat service instance
+func NewHeartbeatService(serverRepo repository.ServerRepository, heartbeatRepo repository.HeartbeatRepository, historyRepo repository.HistoryRepository, monitorService MonitorService, maintenanceService MaintenanceService) HeartbeatService {
+	return &heartbeatService{
+		serverRepo:         serverRepo,
+		heartbeatRepo:      heartbeatRepo,
+		historyRepo:        historyRepo,
+		monitorService:     monitorService,
+		maintenanceService: maintenanceService,
+	}
+}
+
+// RecordPing handles a ping of a heartbeat server. The ping moves the deadline of the next
+// one and is reported as UP, which resolves an open incident, or as DOWN when the job
+// reports a non-zero exit code. The ping is stored with the incident it opened or resolved.
+func (s *heartbeatService) RecordPing(token string, req dto.HeartbeatPingRequest, remoteAddr string) (*models.HeartbeatPing, error) {
+	server, err := s.serverRepo.FindByHeartbeatToken(token)
+	if err != nil {
+		return nil, errors.New("heartbeat not found")
+	}
+
+	now := time.Now()
+	server.LastHeartbeatAt = &now
+	server.ScheduleHeartbeat(now)
+	if err := s.serverRepo.UpdateHeartbeat(server); err != nil {
+		return nil, errors.New("failed to record heartbeat")
+	}
+
+	ping := &models.HeartbeatPing{
+		ServerID:   server.ID,
+		Status:     models.ServerStatusUp,
+		ExitCode:   req.ExitCode,
+		DurationMs: req.DurationMs,
+		LogTail:    logTail(req.Log),
+		RemoteAddr: remoteAddr,
+		ReceivedAt: now,
+	}
+	report := StatusReport{
+		Status:     models.ServerStatusUp,
+		ReportedBy: models.SystemUserID,
+		Source:     models.CheckSourceHeartbeat,
+	}
+	if req.DurationMs != nil {
+		report.ResponseTime = *req.DurationMs
+	}
+	if req.ExitCode != nil && *req.ExitCode != 0 {
+		ping.Status = models.ServerStatusDown
+		report.Status = models.ServerStatusDown
+		report.Message = fmt.Sprintf("job exited with code %d", *req.ExitCode)
+	}
+
+	// An UP ping belongs to the incident it resolves, so look it up before reporting
+	var open []models.ServerDownHistory
+	if ping.Status == models.ServerStatusUp {
+		open, _ = s.historyRepo.FindOpenByServerID(server.ID)
+	}
+
+	if err := s.monitorService.ReportStatus(server, report); err != nil {
+		return nil, err
+	}
+
+	if ping.Status == models.ServerStatusDown {
+		open, _ = s.historyRepo.FindOpenByServerID(server.ID)
+	}
+	if len(open) > 0 {
+		ping.HistoryID = &open[0].ID
+	}
+
+	if err := s.heartbeatRepo.Create(ping); err != nil {
+		// The status is already handled, a missing ping payload is not fatal
+		log.Printf("ERROR: Failed to store heartbeat ping for server %s: %v", server.ID, err)
+	}
+
+	return ping, nil
+}
+
+// CheckMissed reports every heartbeat server whose ping is overdue as DOWN,
+// which opens an incident and notifies users like any other DOWN report.
+// A server already MAINTENANCE is reported once its maintenance window ended.
+func (s *heartbeatService) CheckMissed(ctx context.Context) {
+	now := time.Now()
+	servers, err := s.serverRepo.FindOverdueHeartbeats(now)
+	if err != nil {
+		log.Printf("ERROR: Failed to load overdue heartbeats: %v", err)
+		return
+	}
+
+	for i := range servers {
+		if ctx.Err() != nil {
+			return
+		}
+		server := &servers[i]
+		if server.Status == models.ServerStatusMaintenance && s.maintenanceService.InMaintenance(server.ID, now) {
+			continue
+		}
+
+		message := "no heartbeat received since the monitor was set up"
+		if server.LastHeartbeatAt != nil {
+			message = fmt.Sprintf("no heartbeat received since %s", server.LastHeartbeatAt.Format(time.RFC3339))
+		}
+
+		err := s.monitorService.ReportStatus(server, StatusReport{
+			Status:     models.ServerStatusDown,
+			ReportedBy: models.SystemUserID,
+			Source:     models.CheckSourceHeartbeat,
+			Message:    message,
+		})
+		if err != nil {
+			log.Printf("ERROR: Failed to report missed heartbeat for server %s: %v", server.ID, err)
+		}
+	}
+}
+
+// GetPings gets the latest pings of a heartbeat server, optionally only those of an incident
+func (s *heartbeatService) GetPings(serverID uuid.UUID, historyID *uuid.UUID, limit int) ([]models.HeartbeatPing, error) {
+	pings, err := s.heartbeatRepo.FindByServerID(serverID, historyID, limit)
+	if err != nil {
+		return nil, errors.New("failed to get heartbeat pings")
+	}
+	return pings, nil
+}
+
+// logTail keeps the last maxHeartbeatLogBytes of a job log as valid text
+func logTail(output string) string {
+	if len(output) > maxHeartbeatLogBytes {
+		output = output[len(output)-maxHeartbeatLogBytes:]
+	}
+	// Postgres text columns reject NUL bytes and invalid UTF-8
+	return strings.ToValidUTF8(strings.ReplaceAll(output, "\x00", ""), "")
+}
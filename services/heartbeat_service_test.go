@@ -0,0 +1,51 @@
+package services
+
+import (
+	"NetGuardServer/models"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCheckMissed(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        string
+		inMaintenance bool
+		wantStatus    string
+		wantFailures  int // DOWN reports filed by both sweeps
+		wantIncident  bool
+	}{
+		{name: "missed ping opens an incident", status: models.ServerStatusUp, wantStatus: models.ServerStatusDown, wantFailures: 1, wantIncident: true},
+		{name: "server in maintenance stays in maintenance", status: models.ServerStatusUp, inMaintenance: true, wantStatus: models.ServerStatusMaintenance, wantFailures: 1},
+		{name: "ended maintenance opens an incident", status: models.ServerStatusMaintenance, wantStatus: models.ServerStatusDown, wantFailures: 1, wantIncident: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expectedBy := time.Now().Add(-time.Minute)
+			server := newTestServer()
+			server.CheckType = models.CheckTypeHeartbeat
+			server.Status = tt.status
+			server.HeartbeatExpectedBy = &expectedBy
+			mt := newMonitorTest(server)
+			mt.maintenance.inMaintenance = tt.inMaintenance
+			service := NewHeartbeatService(mt.serverRepo, nil, mt.historyRepo, mt.service, mt.maintenance)
+
+			// The ping stays overdue across sweeps
+			service.CheckMissed(context.Background())
+			service.CheckMissed(context.Background())
+
+			stored, _ := mt.serverRepo.FindByID(server.ID)
+			if stored.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", stored.Status, tt.wantStatus)
+			}
+			if stored.ConsecutiveFailures != tt.wantFailures {
+				t.Errorf("%d DOWN reports filed, want %d", stored.ConsecutiveFailures, tt.wantFailures)
+			}
+			if incident := len(mt.historyRepo.histories) > 0; incident != tt.wantIncident {
+				t.Errorf("incident opened = %v, want %v", incident, tt.wantIncident)
+			}
+		})
+	}
+}
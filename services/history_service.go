@@ -4,6 +4,9 @@ import (
 	"NetGuardServer/models"
 	"NetGuardServer/repository"
 	"errors"
+	"fmt"
+	"log"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,41 +14,168 @@ import (
 
 // HistoryService defines the interface for history business logic
 type HistoryService interface {
-	CreateHistory(serverID uuid.UUID, serverName, url, status string, createdBy uuid.UUID) (*models.ServerDownHistory, error)
+	CreateHistory(serverID uuid.UUID, serverName, url, status, description string, createdBy uuid.UUID, notifications []models.NotificationOutbox) (*models.ServerDownHistory, bool, error)
+	CreateImpactedHistory(serverID uuid.UUID, serverName, url, description string, createdBy, parentID uuid.UUID) (*models.ServerDownHistory, bool, error)
+	PromoteImpacted(history *models.ServerDownHistory) error
+	FindRootIncident(serverIDs []uuid.UUID) (*models.ServerDownHistory, error)
+	HasOpenHistory(serverID uuid.UUID) (bool, error)
 	GetHistoryByID(id uuid.UUID) (*models.ServerDownHistory, error)
+	GetHistoryDetail(id uuid.UUID) (*models.HistoryResponse, error)
+	GetTimeline(historyID uuid.UUID) ([]models.HistoryEventResponse, error)
+	GetDeliveryLog(historyID uuid.UUID) ([]models.NotificationOutbox, error)
+	AddComment(historyID uuid.UUID, authorID uuid.UUID, message string) (*models.HistoryEventResponse, error)
+	AddEvent(historyID uuid.UUID, eventType string, authorID uuid.UUID, message string) error
 	GetHistoryByServerID(serverID uuid.UUID) ([]models.HistoryResponse, error)
 	GetAllHistory(limit int) ([]models.HistoryResponse, error)
+	AcknowledgeHistory(id uuid.UUID, acknowledgedBy uuid.UUID) (*models.ServerDownHistory, error)
+	AssignHistory(id uuid.UUID, assigneeID uuid.UUID, assignedBy uuid.UUID) (*models.ServerDownHistory, error)
 	ResolveHistory(id uuid.UUID, resolvedBy uuid.UUID, resolveNote string) (*models.ServerDownHistory, error)
-	GetMonthlyReport(year, month int) ([]map[string]interface{}, error)
+	AutoResolveServer(serverID uuid.UUID) ([]models.ServerDownHistory, error)
+	CountStateChanges(serverID uuid.UUID, since time.Time) (int64, error)
+	GetMonthlyReport(year, month int, loc *time.Location) ([]models.MonthlyReportEntry, error)
 }
 
 // historyService implements HistoryService
 type historyService struct {
-	historyRepo repository.HistoryRepository
+	historyRepo         repository.HistoryRepository
+	eventRepo           repository.HistoryEventRepository
+	maintenanceRepo     repository.MaintenanceRepository
+	userRepo            repository.UserRepository
+	serverRepo          repository.ServerRepository
+	outboxRepo          repository.NotificationOutboxRepository
+	notificationService NotificationService
+	onCallService       OnCallService
 }
 
 // NewHistoryService creates a new history service instance
-func NewHistoryService(historyRepo repository.HistoryRepository) HistoryService {
+func NewHistoryService(historyRepo repository.HistoryRepository, eventRepo repository.HistoryEventRepository, maintenanceRepo repository.MaintenanceRepository, userRepo repository.UserRepository, serverRepo repository.ServerRepository, outboxRepo repository.NotificationOutboxRepository, notificationService NotificationService, onCallService OnCallService) HistoryService {
 	return &historyService{
-		historyRepo: historyRepo,
+		historyRepo:         historyRepo,
+		eventRepo:           eventRepo,
+		maintenanceRepo:     maintenanceRepo,
+		userRepo:            userRepo,
+		serverRepo:          serverRepo,
+		outboxRepo:          outboxRepo,
+		notificationService: notificationService,
+		onCallService:       onCallService,
 	}
 }
 
-// CreateHistory handles history record creation business logic
-func (s *historyService) CreateHistory(serverID uuid.UUID, serverName, url, status string, createdBy uuid.UUID) (*models.ServerDownHistory, error) {
+// CreateHistory handles history record creation business logic.
+// A server has at most one open incident: repeated DOWN reports are attached to it
+// as reporters instead of creating new records. The returned flag tells whether a
+// new incident was opened. The description (e.g. the failing check assertion) and the
+// notifications to send about it are only stored when the incident is opened.
+func (s *historyService) CreateHistory(serverID uuid.UUID, serverName, url, status, description string, createdBy uuid.UUID, notifications []models.NotificationOutbox) (*models.ServerDownHistory, bool, error) {
 	history := &models.ServerDownHistory{
-		ServerID:   serverID,
-		ServerName: serverName,
-		URL:        url,
-		Status:     status,
-		CreatedBy:  createdBy,
+		ServerID:    serverID,
+		ServerName:  serverName,
+		URL:         url,
+		Status:      status,
+		Description: description,
+		CreatedBy:   createdBy,
 	}
 
-	if err := s.historyRepo.Create(history); err != nil {
-		return nil, errors.New("failed to create history record")
+	open, created, err := s.historyRepo.CreateOrAttach(history, createdBy, notifications)
+	if err != nil {
+		return nil, false, errors.New("failed to create history record")
 	}
 
-	return history, nil
+	message := "Server reported " + status
+	if description != "" {
+		message += ": " + description
+	}
+	if created {
+		s.recordEvent(open.ID, models.HistoryEventOpened, createdBy, message)
+	} else {
+		s.recordEvent(open.ID, models.HistoryEventReported, createdBy, message)
+	}
+
+	return open, created, nil
+}
+
+// CreateImpactedHistory records a server as impacted by the incident of a parent server.
+// Like CreateHistory, the report is attached to the server's open record if there is one.
+func (s *historyService) CreateImpactedHistory(serverID uuid.UUID, serverName, url, description string, createdBy, parentID uuid.UUID) (*models.ServerDownHistory, bool, error) {
+	history := &models.ServerDownHistory{
+		ServerID:    serverID,
+		ServerName:  serverName,
+		URL:         url,
+		Status:      models.HistoryStatusImpacted,
+		ParentID:    &parentID,
+		Description: description,
+		CreatedBy:   createdBy,
+	}
+
+	open, created, err := s.historyRepo.CreateOrAttach(history, createdBy, nil)
+	if err != nil {
+		return nil, false, errors.New("failed to create history record")
+	}
+
+	if created {
+		s.recordEvent(open.ID, models.HistoryEventOpened, createdBy, fmt.Sprintf("Server reported DOWN, impacted by incident %s", parentID))
+	} else {
+		s.recordEvent(open.ID, models.HistoryEventReported, createdBy, "Server reported DOWN")
+	}
+
+	return open, created, nil
+}
+
+// PromoteImpacted turns an impacted record into an incident of its own,
+// used when the server is still DOWN after its parents recovered.
+// An acknowledged record keeps its acknowledgement, a record resolved in the meantime is left alone.
+func (s *historyService) PromoteImpacted(history *models.ServerDownHistory) error {
+	promoted, err := s.historyRepo.Promote(history)
+	if err != nil {
+		return errors.New("failed to update history record")
+	}
+	if !promoted {
+		return nil
+	}
+
+	if history.Status == models.HistoryStatusImpacted {
+		history.Status = models.HistoryStatusDown
+	}
+	history.ParentID = nil
+
+	s.recordEvent(history.ID, models.HistoryEventPromoted, models.SystemUserID, "Parent dependencies recovered, server still DOWN")
+	return nil
+}
+
+// FindRootIncident finds the open root incident of any of the given (parent) servers.
+// A parent that is itself impacted leads to the incident it is linked to.
+// It returns nil if none of the servers has an open incident.
+func (s *historyService) FindRootIncident(serverIDs []uuid.UUID) (*models.ServerDownHistory, error) {
+	if len(serverIDs) == 0 {
+		return nil, nil
+	}
+
+	open, err := s.historyRepo.FindOpenByServerIDs(serverIDs)
+	if err != nil {
+		return nil, errors.New("failed to get open history records")
+	}
+
+	for i := range open {
+		if open[i].ParentID == nil {
+			return &open[i], nil
+		}
+	}
+	for _, history := range open {
+		root, err := s.historyRepo.FindByID(*history.ParentID)
+		if err == nil && root.ResolvedAt == nil {
+			return root, nil
+		}
+	}
+	return nil, nil
+}
+
+// HasOpenHistory tells whether a server has an open history record, impacted or not
+func (s *historyService) HasOpenHistory(serverID uuid.UUID) (bool, error) {
+	open, err := s.historyRepo.FindOpenByServerID(serverID)
+	if err != nil {
+		return false, errors.New("failed to get open history records")
+	}
+	return len(open) > 0, nil
 }
 
 // GetHistoryByID gets a history record by ID
@@ -57,38 +187,387 @@ func (s *historyService) GetHistoryByID(id uuid.UUID) (*models.ServerDownHistory
 	return history, nil
 }
 
-// ResolveHistory resolves a history record
-func (s *historyService) ResolveHistory(id uuid.UUID, resolvedBy uuid.UUID, resolveNote string) (*models.ServerDownHistory, error) {
+// AcknowledgeHistory marks an open history record as acknowledged by a user
+// and tells the users who were notified of the incident
+func (s *historyService) AcknowledgeHistory(id uuid.UUID, acknowledgedBy uuid.UUID) (*models.ServerDownHistory, error) {
 	history, err := s.historyRepo.FindByID(id)
 	if err != nil {
 		return nil, errors.New("history record not found")
 	}
 
-	// Check if already resolved
 	if history.ResolvedAt != nil {
 		return nil, errors.New("history record already resolved")
 	}
+	if history.AcknowledgedAt != nil {
+		return nil, errors.New("history record already acknowledged")
+	}
+
+	history.Acknowledge(acknowledgedBy, time.Now())
+
+	acknowledged, err := s.historyRepo.Acknowledge(history)
+	if err != nil {
+		return nil, errors.New("failed to acknowledge history record")
+	}
+	if !acknowledged {
+		// Acknowledged or resolved concurrently, tell which
+		return nil, s.conflict(id)
+	}
+
+	s.recordEvent(history.ID, models.HistoryEventAcknowledged, acknowledgedBy, "")
+	s.notifyOwnership(history, acknowledgedBy, models.NotificationEventAcknowledged)
+
+	return history, nil
+}
+
+// conflict tells why an open history record could no longer be updated
+func (s *historyService) conflict(id uuid.UUID) error {
+	history, err := s.historyRepo.FindByID(id)
+	if err == nil && history.ResolvedAt == nil {
+		return errors.New("history record already acknowledged")
+	}
+	return errors.New("history record already resolved")
+}
+
+// AssignHistory assigns an open history record to a user and notifies the assignee
+func (s *historyService) AssignHistory(id uuid.UUID, assigneeID uuid.UUID, assignedBy uuid.UUID) (*models.ServerDownHistory, error) {
+	history, err := s.historyRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("history record not found")
+	}
+
+	if history.ResolvedAt != nil {
+		return nil, errors.New("history record already resolved")
+	}
+
+	assignee, err := s.userRepo.FindByID(assigneeID)
+	if err != nil {
+		return nil, errors.New("assignee not found")
+	}
 
 	now := time.Now()
-	history.Status = "RESOLVED"
-	history.ResolvedBy = &resolvedBy
-	history.ResolvedAt = &now
-	history.ResolveNote = resolveNote
+	history.AssigneeID = &assignee.ID
+	history.AssignedBy = &assignedBy
+	history.AssignedAt = &now
+
+	assigned, err := s.historyRepo.Assign(history)
+	if err != nil {
+		return nil, errors.New("failed to assign history record")
+	}
+	if !assigned {
+		return nil, errors.New("history record already resolved")
+	}
+
+	s.recordEvent(history.ID, models.HistoryEventAssigned, assignedBy, "Assigned to "+assignee.Name)
+
+	assignedByName, err := s.getUserName(assignedBy, make(map[uuid.UUID]string))
+	if err != nil {
+		assignedByName = "Unknown User"
+	}
+	// Delivery is recorded on the timeline by the notification outbox
+	if err := s.notificationService.SendIncidentAssignedNotification(history, assignee, assignedByName); err != nil {
+		// Log error but don't fail the request
+		log.Printf("ERROR: Failed to queue assignment notification for history %s: %v", history.ID, err)
+	}
+
+	return history, nil
+}
+
+// GetHistoryDetail gets a history record by ID with user names and its timeline
+func (s *historyService) GetHistoryDetail(id uuid.UUID) (*models.HistoryResponse, error) {
+	history, err := s.historyRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("history record not found")
+	}
+
+	timeline, err := s.GetTimeline(id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := s.convertToHistoryResponse([]models.ServerDownHistory{*history})[0]
+	response.Timeline = timeline
+	return &response, nil
+}
+
+// GetTimeline gets the timeline of a history record, oldest event first
+func (s *historyService) GetTimeline(historyID uuid.UUID) ([]models.HistoryEventResponse, error) {
+	if _, err := s.historyRepo.FindByID(historyID); err != nil {
+		return nil, errors.New("history record not found")
+	}
+
+	events, err := s.eventRepo.FindByHistoryID(historyID)
+	if err != nil {
+		return nil, errors.New("failed to get timeline")
+	}
+
+	userCache := make(map[uuid.UUID]string)
+	timeline := make([]models.HistoryEventResponse, len(events))
+	for i, event := range events {
+		timeline[i] = s.convertToEventResponse(event, userCache)
+	}
+	return timeline, nil
+}
+
+// GetDeliveryLog gets the notifications of an incident, one per target,
+// with the outcome of every delivery attempt
+func (s *historyService) GetDeliveryLog(historyID uuid.UUID) ([]models.NotificationOutbox, error) {
+	if _, err := s.historyRepo.FindByID(historyID); err != nil {
+		return nil, errors.New("history record not found")
+	}
+
+	notifications, err := s.outboxRepo.FindByHistoryID(historyID)
+	if err != nil {
+		return nil, errors.New("failed to get notifications")
+	}
+	return notifications, nil
+}
+
+// AddComment appends a free-text comment of a user to the timeline of a history record.
+// Resolved records can still be commented on, e.g. for a post-mortem.
+func (s *historyService) AddComment(historyID uuid.UUID, authorID uuid.UUID, message string) (*models.HistoryEventResponse, error) {
+	if _, err := s.historyRepo.FindByID(historyID); err != nil {
+		return nil, errors.New("history record not found")
+	}
+
+	event := &models.HistoryEvent{
+		HistoryID: historyID,
+		Type:      models.HistoryEventComment,
+		AuthorID:  authorID,
+		Message:   message,
+	}
+	if err := s.eventRepo.Create(event); err != nil {
+		return nil, errors.New("failed to add comment")
+	}
+
+	response := s.convertToEventResponse(*event, make(map[uuid.UUID]string))
+	return &response, nil
+}
+
+// AddEvent appends an event to the timeline of a history record
+func (s *historyService) AddEvent(historyID uuid.UUID, eventType string, authorID uuid.UUID, message string) error {
+	event := &models.HistoryEvent{
+		HistoryID: historyID,
+		Type:      eventType,
+		AuthorID:  authorID,
+		Message:   message,
+	}
+	if err := s.eventRepo.Create(event); err != nil {
+		return errors.New("failed to add timeline event")
+	}
+	return nil
+}
 
-	if err := s.historyRepo.Update(history); err != nil {
+// recordEvent appends an event to a timeline as a side effect of a lifecycle change;
+// a missing event is only logged
+func (s *historyService) recordEvent(historyID uuid.UUID, eventType string, authorID uuid.UUID, message string) {
+	if err := s.AddEvent(historyID, eventType, authorID, message); err != nil {
+		log.Printf("ERROR: Failed to record %s event for history %s: %v", eventType, historyID, err)
+	}
+}
+
+// ResolveHistory resolves a history record and tells the users who were notified of the incident.
+// Records auto-resolved by the system can still get a resolve note from a user afterwards,
+// users already got the recovery notification then.
+func (s *historyService) ResolveHistory(id uuid.UUID, resolvedBy uuid.UUID, resolveNote string) (*models.ServerDownHistory, error) {
+	history, err := s.historyRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("history record not found")
+	}
+
+	resolving := history.ResolvedAt == nil
+	if !resolving {
+		// Allow attaching a note to an auto-resolved record, once
+		if !history.AutoResolved || history.ResolveNote != "" {
+			return nil, errors.New("history record already resolved")
+		}
+		history.ResolvedBy = &resolvedBy
+		history.ResolveNote = resolveNote
+	} else {
+		history.Resolve(resolvedBy, time.Now())
+		history.ResolveNote = resolveNote
+		s.recordMaintenance(history)
+	}
+
+	var updated bool
+	if resolving {
+		updated, err = s.historyRepo.Resolve(history)
+	} else {
+		updated, err = s.historyRepo.AddResolveNote(history)
+	}
+	if err != nil {
 		return nil, errors.New("failed to resolve history record")
 	}
+	if !updated {
+		return nil, errors.New("history record already resolved")
+	}
+
+	s.recordEvent(history.ID, models.HistoryEventResolved, resolvedBy, resolveNote)
+	if resolving {
+		s.notifyOwnership(history, resolvedBy, models.NotificationEventResolved)
+	}
 
 	return history, nil
 }
 
-// GetMonthlyReport gets monthly server down statistics
-func (s *historyService) GetMonthlyReport(year, month int) ([]map[string]interface{}, error) {
-	results, err := s.historyRepo.GetMonthlyReport(year, month)
+// notifyOwnership queues the notification that a user acknowledged or resolved an incident.
+// Like the DOWN notification, it goes to the on-call user of the server or to all users;
+// impacted records were never notified and are left out.
+func (s *historyService) notifyOwnership(history *models.ServerDownHistory, userID uuid.UUID, event string) {
+	if history.ParentID != nil {
+		return
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		log.Printf("ERROR: Failed to get user %s to notify %s of history %s: %v", userID, event, history.ID, err)
+		return
+	}
+	server, err := s.serverRepo.FindByID(history.ServerID)
+	if err != nil {
+		log.Printf("ERROR: Failed to get server of history %s to notify %s: %v", history.ID, event, err)
+		return
+	}
+
+	recipients := s.onCallService.ServerRecipients(server)
+	if event == models.NotificationEventAcknowledged {
+		err = s.notificationService.SendIncidentAcknowledgedNotification(history, server, user, recipients)
+	} else {
+		err = s.notificationService.SendIncidentResolvedNotification(history, server, user, recipients)
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to queue %s notification for history %s: %v", event, history.ID, err)
+	}
+}
+
+// AutoResolveServer resolves every open history record of a server on behalf of the system,
+// used when the server reports UP again. It returns the records that were resolved.
+func (s *historyService) AutoResolveServer(serverID uuid.UUID) ([]models.ServerDownHistory, error) {
+	histories, err := s.historyRepo.FindOpenByServerID(serverID)
+	if err != nil {
+		return nil, errors.New("failed to get open history records")
+	}
+
+	now := time.Now()
+	resolved := make([]models.ServerDownHistory, 0, len(histories))
+	for i := range histories {
+		history := &histories[i]
+		history.Resolve(models.SystemUserID, now)
+		history.AutoResolved = true
+		s.recordMaintenance(history)
+
+		updated, err := s.historyRepo.Resolve(history)
+		if err != nil {
+			return resolved, errors.New("failed to resolve history record")
+		}
+		if !updated {
+			// Resolved by a user in the meantime
+			continue
+		}
+		s.recordEvent(history.ID, models.HistoryEventResolved, models.SystemUserID, "Server reported UP")
+		resolved = append(resolved, *history)
+	}
+
+	return resolved, nil
+}
+
+// CountStateChanges counts the DOWN/UP transitions of a server since a time
+func (s *historyService) CountStateChanges(serverID uuid.UUID, since time.Time) (int64, error) {
+	count, err := s.historyRepo.CountStateChanges(serverID, since)
+	if err != nil {
+		return 0, errors.New("failed to count state changes")
+	}
+	return count, nil
+}
+
+// recordMaintenance records on a history record being resolved how long its server spent in
+// maintenance windows while it was open, so that later changes to the windows leave it as is.
+// Errors are only logged: the record is resolved without maintenance time.
+func (s *historyService) recordMaintenance(history *models.ServerDownHistory) {
+	windows, err := s.maintenanceRepo.FindByServerID(history.ServerID)
+	if err != nil {
+		log.Printf("ERROR: Failed to get maintenance windows of history %s: %v", history.ID, err)
+		return
+	}
+	history.MaintenanceSeconds = int64(models.MaintenanceTime(windows, history.Timestamp, *history.ResolvedAt).Seconds())
+}
+
+// GetMonthlyReport gets monthly server down statistics of the incidents opened in the month,
+// in the given time zone. Downtime of an incident runs until it was resolved (or now, if still
+// open) and excludes the time its server spent in maintenance windows: the time recorded when
+// the incident was resolved, or the current windows while it is open.
+func (s *historyService) GetMonthlyReport(year, month int, loc *time.Location) ([]models.MonthlyReportEntry, error) {
+	from := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, loc)
+	to := from.AddDate(0, 1, 0)
+
+	histories, err := s.historyRepo.FindByPeriod(from, to)
 	if err != nil {
 		return nil, errors.New("failed to get monthly report")
 	}
-	return results, nil
+
+	windows, err := s.maintenanceRepo.FindAll(nil)
+	if err != nil {
+		return nil, errors.New("failed to get monthly report")
+	}
+	windowsByServer := make(map[uuid.UUID][]models.MaintenanceWindow)
+	for _, window := range windows {
+		for _, server := range window.Servers {
+			windowsByServer[server.ID] = append(windowsByServer[server.ID], window)
+		}
+	}
+
+	now := time.Now()
+	entries := make(map[uuid.UUID]*models.MonthlyReportEntry)
+	report := make([]models.MonthlyReportEntry, 0)
+	var order []uuid.UUID
+	for _, history := range histories {
+		entry, ok := entries[history.ServerID]
+		if !ok {
+			entry = &models.MonthlyReportEntry{ServerID: history.ServerID}
+			entries[history.ServerID] = entry
+			order = append(order, history.ServerID)
+		}
+		// Latest name and URL of the server
+		entry.ServerName = history.ServerName
+		entry.URL = history.URL
+
+		if history.ParentID != nil {
+			entry.ImpactedCount++
+			continue
+		}
+
+		end := now
+		if history.ResolvedAt != nil {
+			end = *history.ResolvedAt
+			entry.ResolvedCount++
+		}
+		entry.DownCount++
+		if history.AckSeconds != nil {
+			entry.AcknowledgedCount++
+			entry.AvgAckTime += float64(*history.AckSeconds)
+		}
+
+		maintenance := time.Duration(history.MaintenanceSeconds) * time.Second
+		if history.ResolvedAt == nil {
+			maintenance = models.MaintenanceTime(windowsByServer[history.ServerID], history.Timestamp, end)
+		}
+
+		entry.TotalDowntimeSeconds += int64((end.Sub(history.Timestamp) - maintenance).Seconds())
+		entry.MaintenanceSeconds += int64(maintenance.Seconds())
+	}
+
+	for _, serverID := range order {
+		entry := entries[serverID]
+		if entry.DownCount > 0 {
+			entry.AvgResolutionTime = float64(entry.TotalDowntimeSeconds) / float64(entry.DownCount)
+		}
+		if entry.AcknowledgedCount > 0 {
+			entry.AvgAckTime /= float64(entry.AcknowledgedCount)
+		}
+		report = append(report, *entry)
+	}
+	sort.SliceStable(report, func(i, j int) bool { return report[i].DownCount > report[j].DownCount })
+
+	return report, nil
 }
 
 // GetHistoryByServerID gets history records for a specific server
@@ -133,34 +612,98 @@ func (s *historyService) convertToHistoryResponse(histories []models.ServerDownH
 			resolvedByName = &name
 		}
 
+		// Get acknowledged_by and assignee user names (if exist)
+		var acknowledgedByName *string
+		if history.AcknowledgedBy != nil {
+			name, err := s.getUserName(*history.AcknowledgedBy, userCache)
+			if err != nil {
+				name = "Unknown User"
+			}
+			acknowledgedByName = &name
+		}
+		var assigneeName *string
+		if history.AssigneeID != nil {
+			name, err := s.getUserName(*history.AssigneeID, userCache)
+			if err != nil {
+				name = "Unknown User"
+			}
+			assigneeName = &name
+		}
+
+		reporters := make([]models.HistoryReporterResponse, len(history.Reporters))
+		for j, reporter := range history.Reporters {
+			name, err := s.getUserName(reporter.UserID, userCache)
+			if err != nil {
+				name = "Unknown User"
+			}
+			reporters[j] = models.HistoryReporterResponse{
+				Name:            name,
+				ReportCount:     reporter.ReportCount,
+				FirstReportedAt: reporter.FirstReportedAt,
+				LastReportedAt:  reporter.LastReportedAt,
+			}
+		}
+
 		responses[i] = models.HistoryResponse{
-			ID:          history.ID,
-			ServerID:    history.ServerID,
-			ServerName:  history.ServerName,
-			URL:         history.URL,
-			Status:      history.Status,
-			Timestamp:   history.Timestamp,
-			CreatedBy:   createdByName,
-			ResolvedBy:  resolvedByName,
-			ResolvedAt:  history.ResolvedAt,
-			ResolveNote: history.ResolveNote,
-			Description: history.Description,
+			ID:              history.ID,
+			ServerID:        history.ServerID,
+			ServerName:      history.ServerName,
+			URL:             history.URL,
+			Status:          history.Status,
+			ParentID:        history.ParentID,
+			Timestamp:       history.Timestamp,
+			CreatedBy:       createdByName,
+			AcknowledgedBy:  acknowledgedByName,
+			AcknowledgedAt:  history.AcknowledgedAt,
+			AckSeconds:      history.AckSeconds,
+			AssigneeID:      history.AssigneeID,
+			Assignee:        assigneeName,
+			AssignedAt:      history.AssignedAt,
+			EscalationLevel: history.EscalationLevel,
+			ResolvedBy:      resolvedByName,
+			ResolvedAt:      history.ResolvedAt,
+			ResolveNote:     history.ResolveNote,
+			Description:     history.Description,
+			AutoResolved:    history.AutoResolved,
+			DowntimeSeconds: history.DowntimeSeconds,
+			ReporterCount:   history.ReporterCount,
+			LastReportedAt:  history.LastReportedAt,
+			Reporters:       reporters,
 		}
 	}
 
 	return responses
 }
 
+// convertToEventResponse converts a HistoryEvent to a HistoryEventResponse with the author name
+func (s *historyService) convertToEventResponse(event models.HistoryEvent, userCache map[uuid.UUID]string) models.HistoryEventResponse {
+	author, err := s.getUserName(event.AuthorID, userCache)
+	if err != nil {
+		author = "Unknown User"
+	}
+	return models.HistoryEventResponse{
+		ID:        event.ID,
+		Type:      event.Type,
+		Author:    author,
+		Message:   event.Message,
+		CreatedAt: event.CreatedAt,
+	}
+}
+
 // getUserName gets user name by ID with caching
 func (s *historyService) getUserName(userID uuid.UUID, cache map[uuid.UUID]string) (string, error) {
+	// Actions performed by the backend itself have no user record
+	if userID == models.SystemUserID {
+		return "System", nil
+	}
+
 	// Check cache first
 	if name, exists := cache[userID]; exists {
 		return name, nil
 	}
 
 	// Query from database
-	userRepo := repository.NewUserRepository()
-	user, err := userRepo.FindByID(userID)
+	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
 		return "", err
 	}
@@ -168,4 +711,4 @@ func (s *historyService) getUserName(userID uuid.UUID, cache map[uuid.UUID]strin
 	// Cache the result
 	cache[userID] = user.Name
 	return user.Name, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"NetGuardServer/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestHistoryLifecycleKeepsConcurrentChanges(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		change  func(mt *monitorTest, id uuid.UUID) error
+		wantErr string
+		check   func(t *testing.T, stored *models.ServerDownHistory)
+	}{
+		{
+			name: "acknowledge",
+			change: func(mt *monitorTest, id uuid.UUID) error {
+				_, err := mt.history.AcknowledgeHistory(id, alice)
+				return err
+			},
+			check: func(t *testing.T, stored *models.ServerDownHistory) {
+				if stored.Status != models.HistoryStatusAcknowledged || stored.AcknowledgedAt == nil {
+					t.Errorf("status = %s, want acknowledged", stored.Status)
+				}
+			},
+		},
+		{
+			name: "assign",
+			change: func(mt *monitorTest, id uuid.UUID) error {
+				_, err := mt.history.AssignHistory(id, bob, alice)
+				return err
+			},
+			check: func(t *testing.T, stored *models.ServerDownHistory) {
+				if stored.AssigneeID == nil || *stored.AssigneeID != bob {
+					t.Errorf("assignee = %v, want %s", stored.AssigneeID, bob)
+				}
+			},
+		},
+		{
+			name: "resolve",
+			change: func(mt *monitorTest, id uuid.UUID) error {
+				_, err := mt.history.ResolveHistory(id, alice, "Restarted")
+				return err
+			},
+			check: func(t *testing.T, stored *models.ServerDownHistory) {
+				if stored.ResolvedAt == nil || stored.ResolveNote != "Restarted" {
+					t.Errorf("resolved at %v with note %q, want resolved with the note", stored.ResolvedAt, stored.ResolveNote)
+				}
+			},
+		},
+		{
+			name: "acknowledge twice",
+			change: func(mt *monitorTest, id uuid.UUID) error {
+				if _, err := mt.history.AcknowledgeHistory(id, alice); err != nil {
+					return err
+				}
+				_, err := mt.history.AcknowledgeHistory(id, bob)
+				return err
+			},
+			wantErr: "history record already acknowledged",
+		},
+		{
+			name: "acknowledge after auto-resolve",
+			change: func(mt *monitorTest, id uuid.UUID) error {
+				mt.history.AutoResolveServer(mt.historyRepo.histories[0].ServerID)
+				_, err := mt.history.AcknowledgeHistory(id, alice)
+				return err
+			},
+			wantErr: "history record already resolved",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer()
+			mt := newMonitorTest(server)
+			if _, err := mt.report(server.ID, models.ServerStatusDown, alice); err != nil {
+				t.Fatalf("report: %v", err)
+			}
+			id := mt.historyRepo.histories[0].ID
+
+			// The escalation and a second reporter changed the record since it was opened
+			mt.historyRepo.histories[0].EscalationLevel = 2
+			if _, err := mt.report(server.ID, models.ServerStatusDown, bob); err != nil {
+				t.Fatalf("report: %v", err)
+			}
+
+			err := tt.change(mt, id)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			stored := mt.historyRepo.histories[0]
+			if stored.ReporterCount != 2 || stored.EscalationLevel != 2 {
+				t.Errorf("reporter count %d and escalation level %d, want 2 and 2", stored.ReporterCount, stored.EscalationLevel)
+			}
+			tt.check(t, stored)
+		})
+	}
+}
+
+func TestMonthlyReportKeepsRecordedMaintenance(t *testing.T) {
+	alice := uuid.New()
+	server := newTestServer()
+	mt := newMonitorTest(server)
+
+	now := time.Now().UTC()
+	history := &models.ServerDownHistory{ID: uuid.New(), ServerID: server.ID, Status: models.HistoryStatusDown, Timestamp: now.Add(-2 * time.Hour)}
+	mt.historyRepo.histories = append(mt.historyRepo.histories, history)
+	window := models.MaintenanceWindow{
+		ID:              uuid.New(),
+		StartsAt:        now.Add(-90 * time.Minute),
+		DurationMinutes: 60,
+		Timezone:        "UTC",
+		Recurrence:      models.RecurrenceNone,
+		Servers:         []models.Server{{ID: server.ID}},
+	}
+	mt.windows.windows[window.ID] = window
+
+	month := func() models.MonthlyReportEntry {
+		t.Helper()
+		report, err := mt.history.GetMonthlyReport(history.Timestamp.Year(), int(history.Timestamp.Month()), time.UTC)
+		if err != nil {
+			t.Fatalf("report: %v", err)
+		}
+		if len(report) != 1 {
+			t.Fatalf("got %d report entries, want 1", len(report))
+		}
+		return report[0]
+	}
+
+	if entry := month(); entry.MaintenanceSeconds != 3600 {
+		t.Errorf("open incident: maintenance = %ds, want 3600s from the current window", entry.MaintenanceSeconds)
+	}
+
+	if _, err := mt.history.ResolveHistory(history.ID, alice, "Restarted"); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if history.MaintenanceSeconds != 3600 {
+		t.Errorf("recorded maintenance = %ds, want 3600s", history.MaintenanceSeconds)
+	}
+
+	// Deleting the window afterwards leaves the report of the resolved incident as is
+	delete(mt.windows.windows, window.ID)
+	entry := month()
+	if entry.MaintenanceSeconds != 3600 {
+		t.Errorf("maintenance = %ds after deleting the window, want 3600s", entry.MaintenanceSeconds)
+	}
+	if entry.TotalDowntimeSeconds < 3599 || entry.TotalDowntimeSeconds > 3601 {
+		t.Errorf("downtime = %ds, want about 3600s", entry.TotalDowntimeSeconds)
+	}
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"NetGuardServer/dto"
+	"NetGuardServer/models"
+	"NetGuardServer/repository"
+	"NetGuardServer/utils"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// localTimeLayout is accepted for maintenance times given in the window's time zone
+const localTimeLayout = "2006-01-02T15:04:05"
+
+// MaintenanceService defines the interface for maintenance window business logic
+type MaintenanceService interface {
+	CreateWindow(userID uuid.UUID, req dto.CreateMaintenanceWindowRequest) (*models.MaintenanceWindow, error)
+	GetWindows(serverID *uuid.UUID, activeOnly bool) ([]models.MaintenanceWindow, error)
+	GetWindowByID(id uuid.UUID) (*models.MaintenanceWindow, error)
+	UpdateWindow(id, userID uuid.UUID, req dto.UpdateMaintenanceWindowRequest) (*models.MaintenanceWindow, error)
+	DeleteWindow(id, userID uuid.UUID) error
+	InMaintenance(serverID uuid.UUID, at time.Time) bool
+}
+
+// maintenanceService implements MaintenanceService
+type maintenanceService struct {
+	maintenanceRepo repository.MaintenanceRepository
+	serverRepo      repository.ServerRepository
+	userRepo        repository.UserRepository
+}
+
+// NewMaintenanceService creates a new maintenance service instance
+func NewMaintenanceService(maintenanceRepo repository.MaintenanceRepository, serverRepo repository.ServerRepository, userRepo repository.UserRepository) MaintenanceService {
+	return &maintenanceService{
+		maintenanceRepo: maintenanceRepo,
+		serverRepo:      serverRepo,
+		userRepo:        userRepo,
+	}
+}
+
+// CreateWindow handles maintenance window creation business logic
+func (s *maintenanceService) CreateWindow(userID uuid.UUID, req dto.CreateMaintenanceWindowRequest) (*models.MaintenanceWindow, error) {
+	window := &models.MaintenanceWindow{
+		Name:            req.Name,
+		Description:     req.Description,
+		DurationMinutes: req.DurationMinutes,
+		Timezone:        req.Timezone,
+		Recurrence:      req.Recurrence,
+		CreatedBy:       userID,
+	}
+	if window.Timezone == "" {
+		window.Timezone = "UTC"
+	}
+	if window.Recurrence == "" {
+		window.Recurrence = models.RecurrenceNone
+	}
+
+	if err := s.setTimes(window, req.StartsAt, &req.RecurrenceEnd); err != nil {
+		return nil, err
+	}
+
+	serverIDs, err := resolveServerIDs(s.serverRepo, req.ServerIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkServerAccess(userID, serverIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.maintenanceRepo.Create(window, serverIDs); err != nil {
+		return nil, errors.New("failed to create maintenance window")
+	}
+
+	return s.GetWindowByID(window.ID)
+}
+
+// GetWindows gets all maintenance windows, optionally only those of a server or in progress
+func (s *maintenanceService) GetWindows(serverID *uuid.UUID, activeOnly bool) ([]models.MaintenanceWindow, error) {
+	windows, err := s.maintenanceRepo.FindAll(serverID)
+	if err != nil {
+		return nil, errors.New("failed to get maintenance windows")
+	}
+
+	now := time.Now()
+	result := make([]models.MaintenanceWindow, 0, len(windows))
+	for _, window := range windows {
+		window.Active = window.ActiveAt(now)
+		if activeOnly && !window.Active {
+			continue
+		}
+		result = append(result, window)
+	}
+	return result, nil
+}
+
+// GetWindowByID gets a maintenance window by ID
+func (s *maintenanceService) GetWindowByID(id uuid.UUID) (*models.MaintenanceWindow, error) {
+	window, err := s.maintenanceRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("maintenance window not found")
+	}
+	window.Active = window.ActiveAt(time.Now())
+	return window, nil
+}
+
+// UpdateWindow updates a maintenance window
+func (s *maintenanceService) UpdateWindow(id, userID uuid.UUID, req dto.UpdateMaintenanceWindowRequest) (*models.MaintenanceWindow, error) {
+	window, err := s.maintenanceRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("maintenance window not found")
+	}
+
+	if window.CreatedBy != userID {
+		return nil, errors.New("access denied")
+	}
+
+	// Update fields if provided
+	if req.Name != "" {
+		window.Name = req.Name
+	}
+	if req.Description != nil {
+		window.Description = *req.Description
+	}
+	if req.DurationMinutes != nil {
+		window.DurationMinutes = *req.DurationMinutes
+	}
+	if req.Timezone != "" {
+		window.Timezone = req.Timezone
+	}
+	if req.Recurrence != "" {
+		window.Recurrence = req.Recurrence
+	}
+
+	if err := s.setTimes(window, req.StartsAt, req.RecurrenceEnd); err != nil {
+		return nil, err
+	}
+
+	var serverIDs []uuid.UUID
+	if req.ServerIDs != nil {
+		if serverIDs, err = resolveServerIDs(s.serverRepo, req.ServerIDs); err != nil {
+			return nil, err
+		}
+		if err := s.checkServerAccess(userID, serverIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	window.Servers = nil
+	if err := s.maintenanceRepo.Update(window, serverIDs); err != nil {
+		return nil, errors.New("failed to update maintenance window")
+	}
+
+	return s.GetWindowByID(window.ID)
+}
+
+// DeleteWindow deletes a maintenance window
+func (s *maintenanceService) DeleteWindow(id, userID uuid.UUID) error {
+	window, err := s.maintenanceRepo.FindByID(id)
+	if err != nil {
+		return errors.New("maintenance window not found")
+	}
+
+	if window.CreatedBy != userID {
+		return errors.New("access denied")
+	}
+
+	if err := s.maintenanceRepo.Delete(id); err != nil {
+		return errors.New("failed to delete maintenance window")
+	}
+
+	return nil
+}
+
+// InMaintenance reports whether a maintenance window of the server is in progress.
+// Errors are only logged: monitoring goes on as if there was no maintenance.
+func (s *maintenanceService) InMaintenance(serverID uuid.UUID, at time.Time) bool {
+	windows, err := s.maintenanceRepo.FindByServerID(serverID)
+	if err != nil {
+		log.Printf("ERROR: Failed to get maintenance windows of server %s: %v", serverID, err)
+		return false
+	}
+
+	for i := range windows {
+		if windows[i].ActiveAt(at) {
+			return true
+		}
+	}
+	return false
+}
+
+// setTimes parses the start and recurrence end of a window in its time zone.
+// An empty start keeps the current one; a nil end keeps it and an empty end removes it.
+func (s *maintenanceService) setTimes(window *models.MaintenanceWindow, startsAt string, recurrenceEnd *string) error {
+	loc := window.Location()
+
+	if startsAt != "" {
+		start, err := parseMaintenanceTime(startsAt, loc)
+		if err != nil {
+			return utils.ValidationError("starts_at must be RFC3339 or local time (2006-01-02T15:04:05)")
+		}
+		window.StartsAt = start
+	}
+
+	if recurrenceEnd != nil {
+		window.RecurrenceEnd = nil
+		if *recurrenceEnd != "" {
+			end, err := parseMaintenanceTime(*recurrenceEnd, loc)
+			if err != nil {
+				return utils.ValidationError("recurrence_end must be RFC3339 or local time (2006-01-02T15:04:05)")
+			}
+			window.RecurrenceEnd = &end
+		}
+	}
+
+	if window.RecurrenceEnd != nil && window.RecurrenceEnd.Before(window.StartsAt) {
+		return utils.ValidationError("recurrence_end must not be before starts_at")
+	}
+	return nil
+}
+
+// checkServerAccess checks that the user may put the servers in maintenance:
+// admins may put any server in maintenance, other users only their own servers
+func (s *maintenanceService) checkServerAccess(userID uuid.UUID, serverIDs []uuid.UUID) error {
+	servers, err := s.serverRepo.FindByIDs(serverIDs)
+	if err != nil {
+		return errors.New("failed to get servers")
+	}
+	for _, server := range servers {
+		if server.CreatedBy == userID {
+			continue
+		}
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil || user.Role != "ADMIN" {
+			return errors.New("access denied")
+		}
+		return nil
+	}
+	return nil
+}
+
+// resolveServerIDs parses server IDs and checks that every server exists
+func resolveServerIDs(serverRepo repository.ServerRepository, ids []string) ([]uuid.UUID, error) {
+	serverIDs := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		serverID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, utils.ValidationError("invalid server ID " + id)
+		}
+		serverIDs = append(serverIDs, serverID)
+	}
+
+	servers, err := serverRepo.FindByIDs(serverIDs)
+	if err != nil {
+		return nil, errors.New("failed to get servers")
+	}
+	found := make(map[uuid.UUID]bool, len(servers))
+	for _, server := range servers {
+		found[server.ID] = true
+	}
+	for _, serverID := range serverIDs {
+		if !found[serverID] {
+			return nil, utils.ValidationError("server " + serverID.String() + " not found")
+		}
+	}
+
+	return serverIDs, nil
+}
+
+// parseMaintenanceTime parses an RFC3339 time, or a local time in the given location
+func parseMaintenanceTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(localTimeLayout, value, loc)
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"NetGuardServer/dto"
+	"NetGuardServer/models"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestMaintenanceServerAccess(t *testing.T) {
+	owner, admin, other := uuid.New(), uuid.New(), uuid.New()
+	own := models.Server{ID: uuid.New(), Name: "API Server", CreatedBy: owner}
+	foreign := models.Server{ID: uuid.New(), Name: "Database", CreatedBy: other}
+
+	tests := []struct {
+		name      string
+		userID    uuid.UUID
+		serverIDs []string
+		wantErr   string
+	}{
+		{name: "own server", userID: owner, serverIDs: []string{own.ID.String()}},
+		{name: "server of another user", userID: owner, serverIDs: []string{own.ID.String(), foreign.ID.String()}, wantErr: "access denied"},
+		{name: "admin", userID: admin, serverIDs: []string{own.ID.String(), foreign.ID.String()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maintenanceRepo := &fakeMaintenanceRepository{windows: make(map[uuid.UUID]models.MaintenanceWindow)}
+			service := NewMaintenanceService(maintenanceRepo, newFakeServerRepository(own, foreign), &fakeUserRepository{roles: map[uuid.UUID]string{admin: "ADMIN"}})
+
+			_, err := service.CreateWindow(tt.userID, dto.CreateMaintenanceWindowRequest{
+				Name:            "Patch night",
+				StartsAt:        "2026-03-01T22:00:00Z",
+				DurationMinutes: 60,
+				ServerIDs:       tt.serverIDs,
+			})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("create error = %v, want %q", err, tt.wantErr)
+				}
+				if len(maintenanceRepo.windows) != 0 {
+					t.Error("window created without access to its servers")
+				}
+			} else if err != nil {
+				t.Fatalf("failed to create window: %v", err)
+			}
+
+			// The window of the user's own server cannot be moved to servers they do not own either
+			window, err := service.CreateWindow(tt.userID, dto.CreateMaintenanceWindowRequest{
+				Name:            "Patch night",
+				StartsAt:        "2026-03-01T22:00:00Z",
+				DurationMinutes: 60,
+				ServerIDs:       []string{own.ID.String()},
+			})
+			if err != nil {
+				t.Fatalf("failed to create window: %v", err)
+			}
+			_, err = service.UpdateWindow(window.ID, tt.userID, dto.UpdateMaintenanceWindowRequest{ServerIDs: tt.serverIDs})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("update error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Errorf("failed to update window: %v", err)
+			}
+		})
+	}
+}
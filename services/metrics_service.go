@@ -0,0 +1,101 @@
+package services
+
+import (
+	"NetGuardServer/dto"
+	"NetGuardServer/models"
+	"NetGuardServer/repository"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxRawMetricsRange limits raw (unaggregated) queries to keep responses small
+const maxRawMetricsRange = 7 * 24 * time.Hour
+
+// MetricsService defines the interface for server metrics business logic
+type MetricsService interface {
+	GetServerMetrics(serverID uuid.UUID, from, to time.Time, resolution string) (*dto.ServerMetricsDTO, error)
+}
+
+// metricsService implements MetricsService
+type metricsService struct {
+	checkResultRepo repository.CheckResultRepository
+}
+
+// NewMetricsService creates a new metrics service instance
+func NewMetricsService(checkResultRepo repository.CheckResultRepository) MetricsService {
+	return &metricsService{
+		checkResultRepo: checkResultRepo,
+	}
+}
+
+// GetServerMetrics gets the metrics series of a server within [from, to) at the given resolution
+func (s *metricsService) GetServerMetrics(serverID uuid.UUID, from, to time.Time, resolution string) (*dto.ServerMetricsDTO, error) {
+	if !to.After(from) {
+		return nil, errors.New("from must be before to")
+	}
+
+	metrics := &dto.ServerMetricsDTO{
+		ServerID:   serverID.String(),
+		Resolution: resolution,
+		From:       from.Format(time.RFC3339),
+		To:         to.Format(time.RFC3339),
+		Points:     []dto.MetricPointDTO{},
+	}
+
+	switch resolution {
+	case models.ResolutionRaw:
+		if to.Sub(from) > maxRawMetricsRange {
+			return nil, errors.New("raw resolution supports ranges up to 7 days")
+		}
+
+		results, err := s.checkResultRepo.FindByServerID(serverID, from, to)
+		if err != nil {
+			return nil, errors.New("failed to get metrics")
+		}
+
+		for _, result := range results {
+			var up int64
+			if result.Status == "UP" {
+				up = 1
+			}
+			rt := float64(result.ResponseTime)
+			metrics.Points = append(metrics.Points, dto.MetricPointDTO{
+				Timestamp:       result.CheckedAt.Format(time.RFC3339),
+				Status:          result.Status,
+				Count:           1,
+				UpCount:         up,
+				MinResponseTime: rt,
+				AvgResponseTime: rt,
+				MaxResponseTime: rt,
+				P95ResponseTime: rt,
+				UpRatio:         float64(up),
+			})
+		}
+
+	case models.ResolutionHour, models.ResolutionDay:
+		rollups, err := s.checkResultRepo.FindRollups(serverID, resolution, from, to)
+		if err != nil {
+			return nil, errors.New("failed to get metrics")
+		}
+
+		for _, rollup := range rollups {
+			metrics.Points = append(metrics.Points, dto.MetricPointDTO{
+				Timestamp:       rollup.BucketStart.Format(time.RFC3339),
+				Count:           rollup.Count,
+				UpCount:         rollup.UpCount,
+				MinResponseTime: rollup.MinResponseTime,
+				AvgResponseTime: rollup.AvgResponseTime,
+				MaxResponseTime: rollup.MaxResponseTime,
+				P95ResponseTime: rollup.P95ResponseTime,
+				UpRatio:         rollup.UpRatio,
+			})
+		}
+
+	default:
+		return nil, errors.New("invalid resolution. Must be raw, hour, or day")
+	}
+
+	return metrics, nil
+}
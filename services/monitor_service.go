@@ -0,0 +1,453 @@
+package services
+
+import (
+	"NetGuardServer/checker"
+	"NetGuardServer/config"
+	"NetGuardServer/models"
+	"NetGuardServer/repository"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatusReport represents a single status observation for a server
+type StatusReport struct {
+	Status       string    // UP, DOWN, UNKNOWN
+	ResponseTime int64     // milliseconds
+	ReportedBy   uuid.UUID // user ID, or models.SystemUserID for backend checks
+	Source       string    // models.CheckSourceClient, models.CheckSourceProbe
+	Message      string    // optional reason of a DOWN observation
+}
+
+// MonitorService defines the interface for processing server status observations
+// coming from mobile clients or the backend probe scheduler
+type MonitorService interface {
+	ReportStatus(server *models.Server, report StatusReport) error
+	RunCheck(ctx context.Context, server *models.Server, requestedBy uuid.UUID) (checker.Result, error)
+}
+
+// monitorService implements MonitorService
+type monitorService struct {
+	serverRepo          repository.ServerRepository
+	checkResultRepo     repository.CheckResultRepository
+	checkRunner         *checker.Runner
+	historyService      HistoryService
+	notificationService NotificationService
+	maintenanceService  MaintenanceService
+	escalationService   EscalationService
+	onCallService       OnCallService
+}
+
+// NewMonitorService creates a new monitor service instance
+func NewMonitorService(serverRepo repository.ServerRepository, checkResultRepo repository.CheckResultRepository, checkRunner *checker.Runner, historyService HistoryService, notificationService NotificationService, maintenanceService MaintenanceService, escalationService EscalationService, onCallService OnCallService) MonitorService {
+	return &monitorService{
+		serverRepo:          serverRepo,
+		checkResultRepo:     checkResultRepo,
+		checkRunner:         checkRunner,
+		historyService:      historyService,
+		notificationService: notificationService,
+		maintenanceService:  maintenanceService,
+		escalationService:   escalationService,
+		onCallService:       onCallService,
+	}
+}
+
+// RunCheck runs the server's check from the backend and reports the result like any
+// other observation. It is used by the probe scheduler and on-demand checks from the API.
+// requestedBy is the user who asked for the check, or models.SystemUserID for the scheduler.
+func (s *monitorService) RunCheck(ctx context.Context, server *models.Server, requestedBy uuid.UUID) (checker.Result, error) {
+	result := s.checkRunner.Check(ctx, server)
+
+	// Results of checks interrupted by shutdown are not trustworthy
+	if ctx.Err() == context.Canceled {
+		return result, ctx.Err()
+	}
+
+	err := s.ReportStatus(server, StatusReport{
+		Status:       result.Status,
+		ResponseTime: result.ResponseTime,
+		ReportedBy:   requestedBy,
+		Source:       models.CheckSourceProbe,
+		Message:      result.Message,
+	})
+
+	if result.Certificate != nil {
+		s.handleCertificate(server, result.Certificate)
+	}
+	return result, err
+}
+
+// handleCertificate stores the TLS certificate seen by a check and sends an expiry
+// warning each time a configured threshold (CERT_WARNING_DAYS) is crossed.
+// An expired certificate fails verification, so it is reported as DOWN by the check itself.
+func (s *monitorService) handleCertificate(server *models.Server, cert *models.CertificateInfo) {
+	previous := server.Certificate.ExpiresAt
+	if previous == nil || !previous.Equal(*cert.ExpiresAt) {
+		// New or renewed certificate, warnings start over
+		server.CertWarnedDays = 0
+	}
+	server.Certificate = *cert
+
+	daysLeft := int(time.Until(*cert.ExpiresAt).Hours() / 24)
+	threshold := certWarningThreshold(daysLeft, config.AppConfig.Cert.WarningDays)
+	warn := daysLeft >= 0 && threshold > 0 && (server.CertWarnedDays == 0 || threshold < server.CertWarnedDays)
+	if warn {
+		server.CertWarnedDays = threshold
+	}
+
+	if err := s.serverRepo.UpdateCertificate(server); err != nil {
+		log.Printf("ERROR: Failed to update certificate of server %s: %v", server.ID, err)
+		return
+	}
+	if !warn {
+		return
+	}
+
+	log.Printf("INFO: TLS certificate of server %s expires in %d day(s)", server.Name, daysLeft)
+	err := s.notificationService.SendCertificateExpiryNotification(server, *cert.ExpiresAt, daysLeft, s.onCallService.ServerRecipients(server))
+	if err != nil {
+		log.Printf("ERROR: Failed to queue certificate notification for server %s: %v", server.ID, err)
+	}
+}
+
+// certWarningThreshold returns the smallest warning threshold (in days) that daysLeft
+// has reached, or 0 if none. thresholds must be sorted in descending order.
+func certWarningThreshold(daysLeft int, thresholds []int) int {
+	reached := 0
+	for _, threshold := range thresholds {
+		if daysLeft <= threshold {
+			reached = threshold
+		}
+	}
+	return reached
+}
+
+// ReportStatus handles a single status observation for a server.
+// The observation is appended to the check result series and the server's latest status
+// is persisted. A DOWN observation only counts once the server's failure threshold and
+// confirmation policy are met (until then the server is SUSPECTED); a confirmed DOWN creates
+// a history record and sends FCM notifications. A DOWN server recovers after SuccessThreshold
+// consecutive UP observations, which auto-resolves open history records and sends a recovery
+// notification. While the server is flapping, those notifications are collapsed into one alert.
+// During a maintenance window a DOWN observation only sets the status to MAINTENANCE.
+// An UNKNOWN observation leaves a DOWN server DOWN, its incident stays open.
+// Failures of the latter are only logged so the caller (HTTP handler or probe) is never blocked.
+func (s *monitorService) ReportStatus(server *models.Server, report StatusReport) error {
+	now := time.Now()
+	reportedBy := report.ReportedBy
+
+	result := &models.CheckResult{
+		ServerID:     server.ID,
+		Status:       report.Status,
+		ResponseTime: report.ResponseTime,
+		Source:       report.Source,
+		Message:      report.Message,
+		ReportedBy:   reportedBy,
+		CheckedAt:    now,
+	}
+	if err := s.checkResultRepo.Create(result); err != nil {
+		// A missing series point is not fatal for status handling
+		log.Printf("ERROR: Failed to record check result for server %s: %v", server.ID, err)
+	}
+
+	// Counters are incremented in the database, concurrent reports may have counted since the server was loaded
+	if report.Status == models.ServerStatusDown || report.Status == models.ServerStatusUp {
+		if err := s.serverRepo.IncrementCounter(server, report.Status); err != nil {
+			return errors.New("failed to update server status")
+		}
+	}
+
+	previous := server.Status
+	status := report.Status
+	inMaintenance := s.maintenanceService.InMaintenance(server.ID, now)
+	var reporters []uuid.UUID
+	switch status {
+	case models.ServerStatusDown:
+		// During maintenance DOWN is expected: no incident, no notification
+		if inMaintenance {
+			status = models.ServerStatusMaintenance
+			break
+		}
+
+		var confirmed bool
+		confirmed, reporters = s.confirmDown(server, reportedBy, now)
+		if !confirmed {
+			status = models.ServerStatusSuspected
+		}
+	case models.ServerStatusUp:
+		// A DOWN server only recovers after enough consecutive UP observations
+		if previous == models.ServerStatusDown && server.ConsecutiveSuccesses < server.SuccessThreshold {
+			status = models.ServerStatusDown
+		}
+	case models.ServerStatusUnknown:
+		// An inconclusive observation neither ends the incident of a DOWN server
+		// nor lets the next DOWN report count as a new state change
+		if previous == models.ServerStatusDown {
+			status = models.ServerStatusDown
+		}
+	}
+
+	transition := (previous != models.ServerStatusDown && status == models.ServerStatusDown) ||
+		(previous == models.ServerStatusDown && status == models.ServerStatusUp)
+	var flapStarted, flapStopped bool
+	var stateChanges int64
+	if !inMaintenance {
+		flapStarted, flapStopped, stateChanges = s.detectFlapping(server, transition, now)
+	}
+
+	server.Status = status
+	server.ResponseTime = report.ResponseTime
+	server.LastChecked = &now
+	server.LastCheckedBy = &reportedBy
+	server.LastCheckSource = report.Source
+	if status != models.ServerStatusSuspected {
+		server.SuspectedSince = nil
+	} else if server.SuspectedSince == nil {
+		server.SuspectedSince = &now
+	}
+
+	if err := s.serverRepo.UpdateStatus(server); err != nil {
+		return errors.New("failed to update server status")
+	}
+
+	switch {
+	case status == models.ServerStatusDown && report.Status == models.ServerStatusDown:
+		s.handleDown(server, reportedBy, reporters, report.Message)
+	case status == models.ServerStatusDown && report.Status == models.ServerStatusUnknown:
+		log.Printf("INFO: Server UNKNOWN reported, incident stays open: %s", server.Name)
+	case status == models.ServerStatusDown:
+		log.Printf("INFO: Server UP reported, waiting for recovery: %s (%d/%d consecutive)", server.Name, server.ConsecutiveSuccesses, server.SuccessThreshold)
+	case status == models.ServerStatusMaintenance:
+		log.Printf("INFO: Server DOWN during maintenance, no incident opened: %s", server.Name)
+	case status == models.ServerStatusSuspected:
+		log.Printf("INFO: Server DOWN suspected, waiting for confirmation: %s (%d/%d consecutive, %d/%d reporters)",
+			server.Name, server.ConsecutiveFailures, server.FailureThreshold, len(reporters), server.ConfirmReporters)
+	case status == models.ServerStatusUp:
+		s.handleUp(server)
+	}
+
+	s.notifyFlapping(server, flapStarted, flapStopped && !transition, stateChanges)
+
+	return nil
+}
+
+// detectFlapping updates the flapping state of a server from its state change rate:
+// a server starts flapping at FLAP_START_THRESHOLD state changes within FLAP_WINDOW_MINUTES
+// and stops at FLAP_STOP_THRESHOLD or fewer. transition tells whether the current
+// observation changes the state itself; it is not recorded as an incident change yet.
+func (s *monitorService) detectFlapping(server *models.Server, transition bool, now time.Time) (started, stopped bool, stateChanges int64) {
+	if !transition && !server.Flapping {
+		return false, false, 0
+	}
+
+	cfg := config.AppConfig.Flap
+	if cfg.StartThreshold <= 0 {
+		return false, false, 0
+	}
+
+	window := time.Duration(cfg.WindowMinutes) * time.Minute
+	stateChanges, err := s.historyService.CountStateChanges(server.ID, now.Add(-window))
+	if err != nil {
+		log.Printf("ERROR: Failed to count state changes for server %s: %v", server.ID, err)
+		return false, false, 0
+	}
+	if transition {
+		stateChanges++
+	}
+
+	switch {
+	case !server.Flapping && stateChanges >= int64(cfg.StartThreshold):
+		server.Flapping = true
+		server.FlappingSince = &now
+		return true, false, stateChanges
+	case server.Flapping && stateChanges <= int64(cfg.StopThreshold):
+		server.Flapping = false
+		server.FlappingSince = nil
+		return false, true, stateChanges
+	}
+	return false, false, stateChanges
+}
+
+// notifyFlapping sends the single alert of a server that started flapping, and tells
+// users the status a server settled on when it stopped flapping without a state change
+// (a state change sends its own DOWN/UP notification)
+func (s *monitorService) notifyFlapping(server *models.Server, started, stopped bool, stateChanges int64) {
+	switch {
+	case started:
+		log.Printf("INFO: Server flapping: %s (%d state changes)", server.Name, stateChanges)
+		window := time.Duration(config.AppConfig.Flap.WindowMinutes) * time.Minute
+		err := s.notificationService.SendServerFlappingNotification(server, stateChanges, window, s.onCallService.ServerRecipients(server))
+		if err != nil {
+			log.Printf("ERROR: Failed to queue flapping notification for server %s: %v", server.ID, err)
+		}
+	case stopped:
+		log.Printf("INFO: Server stopped flapping: %s (%s)", server.Name, server.Status)
+		err := s.notificationService.SendServerStableNotification(server, s.onCallService.ServerRecipients(server))
+		if err != nil {
+			log.Printf("ERROR: Failed to queue stable notification for server %s: %v", server.ID, err)
+		}
+	}
+}
+
+// confirmDown evaluates the server's confirmation policy for a DOWN report.
+// A server that is already DOWN stays confirmed; otherwise the server needs
+// FailureThreshold consecutive DOWN observations and at least ConfirmReporters
+// distinct reporters must have reported DOWN within the confirmation window.
+// It also returns the distinct reporters found in the window.
+func (s *monitorService) confirmDown(server *models.Server, reportedBy uuid.UUID, now time.Time) (bool, []uuid.UUID) {
+	reporters := []uuid.UUID{reportedBy}
+	if server.Status == models.ServerStatusDown {
+		return true, reporters
+	}
+	if server.ConsecutiveFailures < server.FailureThreshold {
+		return false, reporters
+	}
+	if server.ConfirmReporters <= 1 {
+		return true, reporters
+	}
+
+	since := now.Add(-time.Duration(server.ConfirmWindowSeconds) * time.Second)
+	found, err := s.checkResultRepo.FindReporters(server.ID, models.ServerStatusDown, since)
+	if err != nil {
+		log.Printf("ERROR: Failed to count DOWN reporters for server %s: %v", server.ID, err)
+	}
+	for _, id := range found {
+		if id != reportedBy {
+			reporters = append(reporters, id)
+		}
+	}
+
+	return len(reporters) >= server.ConfirmReporters, reporters
+}
+
+// handleDown opens (or joins) the incident of a DOWN server and notifies users
+// once, when the incident is opened, then escalates it along the server's escalation policy.
+// The notifications are added to the outbox in the transaction opening the incident.
+// The description of the DOWN observation (e.g. the failing assertion) is stored on a new
+// incident. Reporters that confirmed the DOWN state while the server was suspected are
+// attached to a newly opened incident as well.
+// While a parent dependency has an open incident, the server is only recorded as
+// impacted by that root incident and no notification is sent.
+func (s *monitorService) handleDown(server *models.Server, reportedBy uuid.UUID, reporters []uuid.UUID, description string) {
+	root := s.findRootIncident(server)
+
+	// Create history record or attach the report to the open one
+	var history *models.ServerDownHistory
+	var created bool
+	var err error
+	if root != nil {
+		history, created, err = s.historyService.CreateImpactedHistory(server.ID, server.Name, server.URL, description, reportedBy, root.ID)
+	} else {
+		// Notifications are only stored with a new incident, further reports need none
+		var notifications []models.NotificationOutbox
+		if open, err := s.historyService.HasOpenHistory(server.ID); !server.Flapping && (err != nil || !open) {
+			notifications = s.notificationService.ServerDownNotifications(server, reportedBy, s.onCallService.ServerRecipients(server))
+		}
+		history, created, err = s.historyService.CreateHistory(server.ID, server.Name, server.URL, models.HistoryStatusDown, description, reportedBy, notifications)
+	}
+	if err != nil {
+		// Log error but don't fail the request
+		log.Printf("ERROR: Failed to create history record for server %s: %v", server.ID, err)
+		return
+	}
+
+	switch {
+	case !created && history.ParentID != nil && root == nil:
+		// The parents recovered but the server is still DOWN: it becomes an incident of its own
+		if err := s.historyService.PromoteImpacted(history); err != nil {
+			log.Printf("ERROR: Failed to promote impacted history record %s: %v", history.ID, err)
+			return
+		}
+		log.Printf("INFO: Impacted record %s became an incident, server still DOWN: %s", history.ID, server.Name)
+		if !server.Flapping {
+			if err := s.notificationService.SendServerDownNotification(history.ID, server, reportedBy, s.onCallService.ServerRecipients(server)); err != nil {
+				log.Printf("ERROR: Failed to queue DOWN notification for server %s: %v", server.ID, err)
+			}
+		}
+	case !created:
+		log.Printf("INFO: DOWN report attached to open incident %s: %s (%d reporters)", history.ID, server.Name, history.ReporterCount)
+		return
+	case root != nil:
+		log.Printf("INFO: Server DOWN impacted by incident %s of %s: %s", root.ID, root.ServerName, server.Name)
+	default:
+		log.Printf("INFO: History record created for server DOWN: %s (%s)", server.Name, server.URL)
+	}
+
+	for _, reporter := range reporters {
+		if reporter == reportedBy {
+			continue
+		}
+		if _, _, err := s.historyService.CreateHistory(server.ID, server.Name, server.URL, models.HistoryStatusDown, description, reporter, nil); err != nil {
+			log.Printf("ERROR: Failed to attach reporter to history record %s: %v", history.ID, err)
+		}
+	}
+
+	if history.ParentID != nil {
+		return
+	}
+
+	// Re-notify along the server's escalation policy until someone acknowledges
+	if err := s.escalationService.StartEscalation(history, server.EscalationPolicyID); err != nil {
+		log.Printf("ERROR: Failed to start escalation for history %s: %v", history.ID, err)
+	}
+
+	if server.Flapping {
+		log.Printf("INFO: DOWN notification collapsed into flapping alert: %s", server.Name)
+	}
+}
+
+// findRootIncident finds the open root incident of the server's parent dependencies, if any
+func (s *monitorService) findRootIncident(server *models.Server) *models.ServerDownHistory {
+	dependsOn, err := s.serverRepo.FindDependencies(server.ID)
+	if err != nil {
+		log.Printf("ERROR: Failed to get dependencies of server %s: %v", server.ID, err)
+		return nil
+	}
+
+	root, err := s.historyService.FindRootIncident(dependsOn)
+	if err != nil {
+		log.Printf("ERROR: Failed to find root incident for server %s: %v", server.ID, err)
+		return nil
+	}
+	return root
+}
+
+// handleUp resolves open history records of a server that is back UP and notifies users
+func (s *monitorService) handleUp(server *models.Server) {
+	resolved, err := s.historyService.AutoResolveServer(server.ID)
+	if err != nil {
+		log.Printf("ERROR: Failed to auto-resolve history records for server %s: %v", server.ID, err)
+	}
+	if len(resolved) == 0 {
+		return
+	}
+
+	// The oldest open record tells how long the server was down
+	downtime := time.Duration(*resolved[0].DowntimeSeconds) * time.Second
+	log.Printf("INFO: Auto-resolved %d history record(s) for server UP: %s (down for %s)", len(resolved), server.Name, downtime)
+
+	// Users were only notified of incidents of the server itself, not of its impacted records
+	var incidentID uuid.UUID
+	for _, history := range resolved {
+		if history.ParentID == nil {
+			incidentID = history.ID
+			break
+		}
+	}
+	if incidentID == uuid.Nil {
+		return
+	}
+
+	if server.Flapping {
+		log.Printf("INFO: Recovery notification collapsed into flapping alert: %s", server.Name)
+		return
+	}
+
+	err = s.notificationService.SendServerRecoveredNotification(incidentID, server, downtime, s.onCallService.ServerRecipients(server))
+	if err != nil {
+		log.Printf("ERROR: Failed to queue recovery notification for server %s: %v", server.ID, err)
+	}
+}
@@ -0,0 +1,326 @@
+package services
+
+import (
+	"NetGuardServer/models"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// monitorTest wires a monitor service to in-memory fakes and a real history service
+type monitorTest struct {
+	service       MonitorService
+	history       HistoryService
+	serverRepo    *fakeServerRepository
+	historyRepo   *fakeHistoryRepository
+	eventRepo     *fakeHistoryEventRepository
+	windows       *fakeMaintenanceRepository
+	notifications *fakeNotificationService
+	escalations   *fakeEscalationService
+	maintenance   *fakeMaintenanceService
+}
+
+func newMonitorTest(server models.Server) *monitorTest {
+	t := &monitorTest{
+		serverRepo:    newFakeServerRepository(server),
+		historyRepo:   &fakeHistoryRepository{},
+		eventRepo:     &fakeHistoryEventRepository{},
+		windows:       &fakeMaintenanceRepository{windows: make(map[uuid.UUID]models.MaintenanceWindow)},
+		notifications: &fakeNotificationService{},
+		escalations:   &fakeEscalationService{},
+		maintenance:   &fakeMaintenanceService{},
+	}
+	onCall := &fakeOnCallService{}
+	t.history = NewHistoryService(t.historyRepo, t.eventRepo, t.windows, &fakeUserRepository{}, t.serverRepo, nil, t.notifications, onCall)
+	t.service = NewMonitorService(t.serverRepo, &fakeCheckResultRepository{}, nil, t.history, t.notifications, t.maintenance, t.escalations, onCall)
+	return t
+}
+
+// report reports a status of the server as stored, like the status endpoint does
+func (t *monitorTest) report(serverID uuid.UUID, status string, reportedBy uuid.UUID) (*models.Server, error) {
+	server, err := t.serverRepo.FindByID(serverID)
+	if err != nil {
+		return nil, err
+	}
+	err = t.service.ReportStatus(server, StatusReport{
+		Status:     status,
+		ReportedBy: reportedBy,
+		Source:     models.CheckSourceClient,
+	})
+	return server, err
+}
+
+// newTestServer returns an UP server with the default check policy
+func newTestServer() models.Server {
+	return models.Server{
+		ID:                   uuid.New(),
+		Name:                 "API Server",
+		URL:                  "https://api.company.com",
+		Status:               models.ServerStatusUp,
+		ConfirmReporters:     1,
+		ConfirmWindowSeconds: 300,
+		FailureThreshold:     1,
+		SuccessThreshold:     1,
+	}
+}
+
+func TestReportStatusDeduplicatesDownReports(t *testing.T) {
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name             string
+		confirmReporters int
+		reporters        []uuid.UUID
+		wantStatuses     []string
+		wantReporters    int
+		wantEvents       []string
+	}{
+		{
+			name:          "repeated reports of one user",
+			reporters:     []uuid.UUID{alice, alice, alice},
+			wantStatuses:  []string{models.ServerStatusDown, models.ServerStatusDown, models.ServerStatusDown},
+			wantReporters: 1,
+			wantEvents:    []string{models.HistoryEventOpened, models.HistoryEventReported, models.HistoryEventReported},
+		},
+		{
+			name:          "reports of several users",
+			reporters:     []uuid.UUID{alice, bob, carol, alice},
+			wantStatuses:  []string{models.ServerStatusDown, models.ServerStatusDown, models.ServerStatusDown, models.ServerStatusDown},
+			wantReporters: 3,
+			wantEvents:    []string{models.HistoryEventOpened, models.HistoryEventReported, models.HistoryEventReported, models.HistoryEventReported},
+		},
+		{
+			name:             "incident opened once confirmed by two users",
+			confirmReporters: 2,
+			reporters:        []uuid.UUID{alice, alice, bob, carol},
+			wantStatuses:     []string{models.ServerStatusSuspected, models.ServerStatusSuspected, models.ServerStatusDown, models.ServerStatusDown},
+			wantReporters:    3,
+			wantEvents:       []string{models.HistoryEventOpened, models.HistoryEventReported, models.HistoryEventReported},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer()
+			if tt.confirmReporters > 0 {
+				server.ConfirmReporters = tt.confirmReporters
+			}
+			mt := newMonitorTest(server)
+
+			for i, reporter := range tt.reporters {
+				reported, err := mt.report(server.ID, models.ServerStatusDown, reporter)
+				if err != nil {
+					t.Fatalf("report %d: %v", i+1, err)
+				}
+				if reported.Status != tt.wantStatuses[i] {
+					t.Errorf("report %d: status = %s, want %s", i+1, reported.Status, tt.wantStatuses[i])
+				}
+			}
+
+			if got := len(mt.historyRepo.histories); got != 1 {
+				t.Fatalf("got %d incidents, want 1", got)
+			}
+			incident := mt.historyRepo.histories[0]
+			if incident.ReporterCount != tt.wantReporters {
+				t.Errorf("reporter count = %d, want %d", incident.ReporterCount, tt.wantReporters)
+			}
+			if mt.notifications.down != 1 || len(mt.historyRepo.notifications) != 1 {
+				t.Errorf("DOWN notifications built %d times with %d queued, want once", mt.notifications.down, len(mt.historyRepo.notifications))
+			}
+			if mt.escalations.started != 1 {
+				t.Errorf("escalation started %d times, want once", mt.escalations.started)
+			}
+			if got := mt.eventRepo.eventTypes(); !slices.Equal(got, tt.wantEvents) {
+				t.Errorf("timeline = %v, want %v", got, tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestReportStatusUnknownKeepsIncidentOpen(t *testing.T) {
+	reporter := uuid.New()
+
+	tests := []struct {
+		name          string
+		reports       []string
+		wantStatuses  []string
+		wantIncidents int
+		wantResolved  int
+		wantDown      int
+		wantRecovered int
+	}{
+		{
+			name:          "DOWN again after UNKNOWN joins the open incident",
+			reports:       []string{models.ServerStatusDown, models.ServerStatusUnknown, models.ServerStatusDown},
+			wantStatuses:  []string{models.ServerStatusDown, models.ServerStatusDown, models.ServerStatusDown},
+			wantIncidents: 1,
+			wantDown:      1,
+		},
+		{
+			name:          "UP after UNKNOWN resolves the incident",
+			reports:       []string{models.ServerStatusDown, models.ServerStatusUnknown, models.ServerStatusUp},
+			wantStatuses:  []string{models.ServerStatusDown, models.ServerStatusDown, models.ServerStatusUp},
+			wantIncidents: 1,
+			wantResolved:  1,
+			wantDown:      1,
+			wantRecovered: 1,
+		},
+		{
+			name:         "UNKNOWN without incident",
+			reports:      []string{models.ServerStatusUnknown},
+			wantStatuses: []string{models.ServerStatusUnknown},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer()
+			mt := newMonitorTest(server)
+
+			for i, status := range tt.reports {
+				reported, err := mt.report(server.ID, status, reporter)
+				if err != nil {
+					t.Fatalf("report %d: %v", i+1, err)
+				}
+				if reported.Status != tt.wantStatuses[i] {
+					t.Errorf("report %d (%s): status = %s, want %s", i+1, status, reported.Status, tt.wantStatuses[i])
+				}
+			}
+
+			resolved := 0
+			for _, history := range mt.historyRepo.histories {
+				if history.ResolvedAt != nil {
+					resolved++
+				}
+			}
+			if len(mt.historyRepo.histories) != tt.wantIncidents || resolved != tt.wantResolved {
+				t.Errorf("got %d incidents with %d resolved, want %d with %d resolved", len(mt.historyRepo.histories), resolved, tt.wantIncidents, tt.wantResolved)
+			}
+			if mt.notifications.down != tt.wantDown || mt.notifications.recovered != tt.wantRecovered {
+				t.Errorf("got %d DOWN and %d recovery notifications, want %d and %d", mt.notifications.down, mt.notifications.recovered, tt.wantDown, tt.wantRecovered)
+			}
+		})
+	}
+}
+
+func TestReportStatusCountsConcurrentReports(t *testing.T) {
+	tests := []struct {
+		name          string
+		reports       []string
+		wantFailures  int
+		wantSuccesses int
+	}{
+		{name: "concurrent DOWN reports", reports: repeat(models.ServerStatusDown, 20), wantFailures: 20},
+		{name: "concurrent UP reports", reports: repeat(models.ServerStatusUp, 20), wantSuccesses: 20},
+		{name: "UNKNOWN reports do not count", reports: append(repeat(models.ServerStatusDown, 5), repeat(models.ServerStatusUnknown, 5)...), wantFailures: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer()
+			// High thresholds keep the server SUSPECTED/UP, only the counters are of interest
+			server.FailureThreshold = 100
+			server.SuccessThreshold = 100
+			mt := newMonitorTest(server)
+
+			// Every report starts from the same stale copy of the server, like concurrent requests
+			var wg sync.WaitGroup
+			for _, status := range tt.reports {
+				stale := server
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if err := mt.service.ReportStatus(&stale, StatusReport{Status: status, ReportedBy: uuid.New(), Source: models.CheckSourceClient}); err != nil {
+						t.Errorf("report failed: %v", err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			stored, _ := mt.serverRepo.FindByID(server.ID)
+			if stored.ConsecutiveFailures != tt.wantFailures || stored.ConsecutiveSuccesses != tt.wantSuccesses {
+				t.Errorf("counters = %d failures, %d successes, want %d and %d",
+					stored.ConsecutiveFailures, stored.ConsecutiveSuccesses, tt.wantFailures, tt.wantSuccesses)
+			}
+		})
+	}
+}
+
+// repeat returns a slice of n times the given status
+func repeat(status string, n int) []string {
+	statuses := make([]string, n)
+	for i := range statuses {
+		statuses[i] = status
+	}
+	return statuses
+}
+
+func TestReportStatusImpactedByParent(t *testing.T) {
+	reporter := uuid.New()
+	parent, child := newTestServer(), newTestServer()
+	parent.Name, child.Name = "Database", "API Server"
+	mt := newMonitorTest(parent)
+	mt.serverRepo.servers[child.ID] = child
+	mt.serverRepo.dependencies = []models.ServerDependency{{ServerID: child.ID, DependsOnID: parent.ID}}
+
+	find := func(serverID uuid.UUID) *models.ServerDownHistory {
+		for _, history := range mt.historyRepo.histories {
+			if history.ServerID == serverID {
+				return history
+			}
+		}
+		t.Fatalf("no history record of server %s", serverID)
+		return nil
+	}
+
+	// The parent's incident is the root incident
+	if _, err := mt.report(parent.ID, models.ServerStatusDown, reporter); err != nil {
+		t.Fatalf("report parent DOWN: %v", err)
+	}
+	root := find(parent.ID)
+
+	// The child DOWN while its parent is DOWN is impacted: no incident of its own, nobody notified
+	if _, err := mt.report(child.ID, models.ServerStatusDown, reporter); err != nil {
+		t.Fatalf("report child DOWN: %v", err)
+	}
+	impacted := find(child.ID)
+	if impacted.Status != models.HistoryStatusImpacted || impacted.ParentID == nil || *impacted.ParentID != root.ID {
+		t.Errorf("child record %s linked to %v, want IMPACTED by %s", impacted.Status, impacted.ParentID, root.ID)
+	}
+	if mt.notifications.down != 1 || mt.escalations.started != 1 {
+		t.Errorf("got %d DOWN notifications and %d escalations, want only the parent's", mt.notifications.down, mt.escalations.started)
+	}
+
+	// Further reports while the parent is DOWN stay attached to the impacted record
+	if _, err := mt.report(child.ID, models.ServerStatusDown, reporter); err != nil {
+		t.Fatalf("report child DOWN: %v", err)
+	}
+	if len(mt.historyRepo.histories) != 2 || impacted.Status != models.HistoryStatusImpacted {
+		t.Errorf("got %d records, child %s, want the impacted record kept", len(mt.historyRepo.histories), impacted.Status)
+	}
+
+	// The parent recovers but the child is still DOWN: the impacted record becomes an incident
+	if _, err := mt.report(parent.ID, models.ServerStatusUp, reporter); err != nil {
+		t.Fatalf("report parent UP: %v", err)
+	}
+	if root.ResolvedAt == nil {
+		t.Fatal("parent incident not resolved")
+	}
+	if _, err := mt.report(child.ID, models.ServerStatusDown, reporter); err != nil {
+		t.Fatalf("report child DOWN: %v", err)
+	}
+	if impacted.Status != models.HistoryStatusDown || impacted.ParentID != nil {
+		t.Errorf("child record %s linked to %v, want promoted to DOWN", impacted.Status, impacted.ParentID)
+	}
+	if len(mt.historyRepo.histories) != 2 {
+		t.Errorf("got %d records, want the impacted record promoted instead of a new one", len(mt.historyRepo.histories))
+	}
+	if mt.notifications.down != 2 || mt.escalations.started != 2 {
+		t.Errorf("got %d DOWN notifications and %d escalations, want the promoted incident notified and escalated", mt.notifications.down, mt.escalations.started)
+	}
+	if !slices.Contains(mt.eventRepo.eventTypes(), models.HistoryEventPromoted) {
+		t.Errorf("events = %v, want a PROMOTED event", mt.eventRepo.eventTypes())
+	}
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"NetGuardServer/dto"
+	"NetGuardServer/models"
+	"NetGuardServer/notifier"
+	"NetGuardServer/repository"
+	"NetGuardServer/utils"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationChannelService defines the interface for notification channel business logic
+type NotificationChannelService interface {
+	CreateChannel(userID uuid.UUID, req dto.CreateNotificationChannelRequest) (*models.NotificationChannel, error)
+	GetChannels(serverID *uuid.UUID) ([]models.NotificationChannel, error)
+	GetChannelByID(id uuid.UUID) (*models.NotificationChannel, error)
+	UpdateChannel(id, userID uuid.UUID, req dto.UpdateNotificationChannelRequest) (*models.NotificationChannel, error)
+	DeleteChannel(id, userID uuid.UUID) error
+	TestChannel(id, userID uuid.UUID) error
+}
+
+// notificationChannelService implements NotificationChannelService
+type notificationChannelService struct {
+	channelRepo repository.NotificationChannelRepository
+	serverRepo  repository.ServerRepository
+	userRepo    repository.UserRepository
+	dispatcher  *notifier.Dispatcher
+}
+
+// NewNotificationChannelService creates a new notification channel service instance
+func NewNotificationChannelService(channelRepo repository.NotificationChannelRepository, serverRepo repository.ServerRepository, userRepo repository.UserRepository, dispatcher *notifier.Dispatcher) NotificationChannelService {
+	return &notificationChannelService{
+		channelRepo: channelRepo,
+		serverRepo:  serverRepo,
+		userRepo:    userRepo,
+		dispatcher:  dispatcher,
+	}
+}
+
+// CreateChannel handles notification channel creation business logic.
+// Webhook channels get a signing secret unless one is given; the response
+// is the only one that contains it.
+func (s *notificationChannelService) CreateChannel(userID uuid.UUID, req dto.CreateNotificationChannelRequest) (*models.NotificationChannel, error) {
+	channel := &models.NotificationChannel{
+		Name:      req.Name,
+		Type:      req.Type,
+		URL:       req.URL,
+		Secret:    req.Secret,
+		EmailTo:   req.EmailTo,
+		Division:  strings.TrimSpace(req.Division),
+		CreatedBy: userID,
+	}
+
+	if channel.Type == models.ChannelTypeWebhook && channel.Secret == "" {
+		secret, err := utils.GenerateToken(32)
+		if err != nil {
+			return nil, errors.New("failed to generate webhook secret")
+		}
+		channel.Secret = secret
+	}
+
+	if err := s.dispatcher.Validate(channel); err != nil {
+		return nil, utils.ValidationError(err.Error())
+	}
+
+	serverIDs, err := resolveServerIDs(s.serverRepo, req.ServerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.channelRepo.Create(channel, serverIDs); err != nil {
+		return nil, errors.New("failed to create notification channel")
+	}
+
+	created, err := s.GetChannelByID(channel.ID)
+	if err != nil {
+		return nil, err
+	}
+	created.IssuedSecret = channel.Secret
+	return created, nil
+}
+
+// GetChannels gets all notification channels, optionally only those attached to a server
+func (s *notificationChannelService) GetChannels(serverID *uuid.UUID) ([]models.NotificationChannel, error) {
+	channels, err := s.channelRepo.FindAll(serverID)
+	if err != nil {
+		return nil, errors.New("failed to get notification channels")
+	}
+	return channels, nil
+}
+
+// GetChannelByID gets a notification channel by ID
+func (s *notificationChannelService) GetChannelByID(id uuid.UUID) (*models.NotificationChannel, error) {
+	channel, err := s.channelRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("notification channel not found")
+	}
+	return channel, nil
+}
+
+// UpdateChannel updates a notification channel. A new secret is returned in the response.
+// The redacted URL of a channel response is ignored, so sending it back keeps the URL.
+func (s *notificationChannelService) UpdateChannel(id, userID uuid.UUID, req dto.UpdateNotificationChannelRequest) (*models.NotificationChannel, error) {
+	channel, err := s.channelRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("notification channel not found")
+	}
+
+	if channel.CreatedBy != userID {
+		return nil, errors.New("access denied")
+	}
+
+	// Update fields if provided
+	if req.Name != "" {
+		channel.Name = req.Name
+	}
+	if req.URL != "" && req.URL != models.RedactURL(channel.URL) {
+		channel.URL = req.URL
+	}
+	if req.Secret != nil {
+		if *req.Secret != "" && len(*req.Secret) < 16 {
+			return nil, utils.ValidationError("secret must be at least 16 characters")
+		}
+		channel.Secret = *req.Secret
+	}
+	if req.EmailTo != nil {
+		channel.EmailTo = req.EmailTo
+	}
+	if req.Division != nil {
+		channel.Division = strings.TrimSpace(*req.Division)
+	}
+	if req.Disabled != nil {
+		channel.Disabled = *req.Disabled
+	}
+
+	if err := s.dispatcher.Validate(channel); err != nil {
+		return nil, utils.ValidationError(err.Error())
+	}
+
+	var serverIDs []uuid.UUID
+	if req.ServerIDs != nil {
+		if serverIDs, err = resolveServerIDs(s.serverRepo, req.ServerIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	channel.Servers = nil
+	if err := s.channelRepo.Update(channel, serverIDs); err != nil {
+		return nil, errors.New("failed to update notification channel")
+	}
+
+	updated, err := s.GetChannelByID(channel.ID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Secret != nil {
+		updated.IssuedSecret = channel.Secret
+	}
+	return updated, nil
+}
+
+// DeleteChannel deletes a notification channel
+func (s *notificationChannelService) DeleteChannel(id, userID uuid.UUID) error {
+	channel, err := s.channelRepo.FindByID(id)
+	if err != nil {
+		return errors.New("notification channel not found")
+	}
+
+	if channel.CreatedBy != userID {
+		return errors.New("access denied")
+	}
+
+	if err := s.channelRepo.Delete(id); err != nil {
+		return errors.New("failed to delete notification channel")
+	}
+
+	return nil
+}
+
+// TestChannel sends a test message over a channel, also when it is disabled.
+// Only the creator of the channel or an admin may test it.
+func (s *notificationChannelService) TestChannel(id, userID uuid.UUID) error {
+	channel, err := s.channelRepo.FindByID(id)
+	if err != nil {
+		return errors.New("notification channel not found")
+	}
+
+	if channel.CreatedBy != userID {
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil || user.Role != "ADMIN" {
+			return errors.New("access denied")
+		}
+	}
+
+	err = s.dispatcher.Send(context.Background(), channel, notifier.Message{
+		Event:     "TEST",
+		Title:     "NetGuard test notification",
+		Body:      fmt.Sprintf("Channel %s is set up correctly", channel.Name),
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("channel test failed: %w", err)
+	}
+	return nil
+}
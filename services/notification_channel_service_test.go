@@ -0,0 +1,110 @@
+package services
+
+import (
+	"NetGuardServer/dto"
+	"NetGuardServer/models"
+	"NetGuardServer/notifier"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// recordingSender records the messages sent over channels
+type recordingSender struct {
+	sent []notifier.Message
+}
+
+func (s *recordingSender) Send(ctx context.Context, channel *models.NotificationChannel, msg notifier.Message) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func (s *recordingSender) Validate(channel *models.NotificationChannel) error {
+	return nil
+}
+
+func newChannelTest(roles map[uuid.UUID]string) (NotificationChannelService, *recordingSender) {
+	sender := &recordingSender{}
+	dispatcher := notifier.NewDispatcher(map[string]notifier.Sender{models.ChannelTypeWebhook: sender}, 0)
+	channelRepo := &fakeNotificationChannelRepository{channels: make(map[uuid.UUID]models.NotificationChannel)}
+	return NewNotificationChannelService(channelRepo, newFakeServerRepository(), &fakeUserRepository{roles: roles}, dispatcher), sender
+}
+
+func TestTestChannel(t *testing.T) {
+	creator, admin, other := uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		userID  uuid.UUID
+		wantErr string
+	}{
+		{name: "creator", userID: creator},
+		{name: "admin", userID: admin},
+		{name: "other user", userID: other, wantErr: "access denied"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, sender := newChannelTest(map[uuid.UUID]string{admin: "ADMIN"})
+			channel, err := service.CreateChannel(creator, dto.CreateNotificationChannelRequest{
+				Name: "Ops webhook", Type: models.ChannelTypeWebhook, URL: "https://hooks.company.com/netguard",
+			})
+			if err != nil {
+				t.Fatalf("failed to create channel: %v", err)
+			}
+
+			err = service.TestChannel(channel.ID, tt.userID)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				if len(sender.sent) != 0 {
+					t.Error("test message sent without access")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(sender.sent) != 1 || sender.sent[0].Event != "TEST" {
+				t.Errorf("sent %v, want one TEST message", sender.sent)
+			}
+		})
+	}
+}
+
+func TestChannelSecret(t *testing.T) {
+	creator := uuid.New()
+	service, _ := newChannelTest(nil)
+
+	created, err := service.CreateChannel(creator, dto.CreateNotificationChannelRequest{
+		Name: "Ops webhook", Type: models.ChannelTypeWebhook, URL: "https://hooks.company.com/netguard",
+	})
+	if err != nil {
+		t.Fatalf("failed to create channel: %v", err)
+	}
+	if created.IssuedSecret == "" || created.IssuedSecret != created.Secret {
+		t.Fatal("create response does not contain the generated secret")
+	}
+
+	fetched, err := service.GetChannelByID(created.ID)
+	if err != nil {
+		t.Fatalf("failed to get channel: %v", err)
+	}
+	encoded, _ := json.Marshal(fetched)
+	if strings.Contains(string(encoded), created.Secret) {
+		t.Errorf("channel JSON %s contains the secret", encoded)
+	}
+
+	// Sending the redacted URL back keeps the channel URL
+	updated, err := service.UpdateChannel(created.ID, creator, dto.UpdateNotificationChannelRequest{URL: models.RedactURL(created.URL)})
+	if err != nil {
+		t.Fatalf("failed to update channel: %v", err)
+	}
+	if updated.URL != "https://hooks.company.com/netguard" || updated.IssuedSecret != "" {
+		t.Errorf("update changed the URL to %q or returned the secret", updated.URL)
+	}
+}
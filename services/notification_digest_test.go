@@ -0,0 +1,104 @@
+package services
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/models"
+	"NetGuardServer/notifier"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestDeliverRateLimitsBurst(t *testing.T) {
+	previous := config.AppConfig.Notification
+	t.Cleanup(func() { config.AppConfig.Notification = previous })
+	config.AppConfig.Notification.UserRateLimit = 3
+	config.AppConfig.Notification.ChannelRateLimit = 2
+	config.AppConfig.Notification.RateWindowSeconds = 60
+
+	alice, serverID := uuid.New(), uuid.New()
+	channel := models.NotificationChannel{ID: uuid.New(), Name: "Ops webhook", Type: models.ChannelTypeWebhook, URL: "https://hooks.company.com/netguard"}
+
+	tests := []struct {
+		name      string
+		target    string
+		limit     int
+		recipient string
+	}{
+		{name: "push to one user", target: models.OutboxTargetPush, limit: 3, recipient: models.UserRateKey(alice)},
+		{name: "one notification channel", target: models.OutboxTargetChannel, limit: 2, recipient: models.ChannelRateKey(channel.ID)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standIn := &fcmStandIn{received: make(map[string]int)}
+			service, _ := newPushTest(t, standIn, models.DeviceToken{UserID: alice, Token: "alice-phone"})
+			sender := &recordingSender{}
+			digestRepo := &fakeNotificationDigestRepository{counters: make(map[string]models.NotificationRateCounter)}
+			service.dispatcher = notifier.NewDispatcher(map[string]notifier.Sender{models.ChannelTypeWebhook: sender}, 0)
+			service.channelRepo = &fakeNotificationChannelRepository{channels: map[uuid.UUID]models.NotificationChannel{channel.ID: channel}}
+			service.outboxRepo = &fakeNotificationOutboxRepository{}
+			service.digestRepo = digestRepo
+			service.userRepo = &fakeUserRepository{}
+			service.templateService = NewNotificationTemplateService(&fakeNotificationTemplateRepository{})
+
+			// A burst of alerts within one rate limit window
+			const burst = 7
+			notifications := make([]models.NotificationOutbox, burst)
+			for i := range notifications {
+				notifications[i] = models.NotificationOutbox{
+					ID:       uuid.New(),
+					ServerID: &serverID,
+					Event:    models.NotificationEventDown,
+					Target:   tt.target,
+					Title:    fmt.Sprintf("Server DOWN %d", i+1),
+					Data:     map[string]string{"server_name": fmt.Sprintf("Server %d", i+1)},
+					Status:   models.OutboxStatusPending,
+				}
+				if tt.target == models.OutboxTargetPush {
+					notifications[i].UserIDs = []uuid.UUID{alice}
+				} else {
+					notifications[i].ChannelID = &channel.ID
+				}
+				service.deliver(context.Background(), &notifications[i])
+			}
+
+			delivered := standIn.received["alice-phone"] + len(sender.sent)
+			if delivered != tt.limit {
+				t.Errorf("delivered %d alerts, want the limit %d", delivered, tt.limit)
+			}
+			for i, notification := range notifications {
+				want := models.OutboxStatusSent
+				if i >= tt.limit {
+					want = models.OutboxStatusDigested
+				}
+				if notification.Status != want {
+					t.Errorf("notification %d: status = %s, want %s", i+1, notification.Status, want)
+				}
+			}
+
+			for _, item := range digestRepo.items {
+				if item.RecipientKey != tt.recipient {
+					t.Errorf("held back for %s, want %s", item.RecipientKey, tt.recipient)
+				}
+			}
+
+			service.FlushDigests(context.Background())
+			if len(digestRepo.digests) != 1 {
+				t.Fatalf("got %d digests, want 1", len(digestRepo.digests))
+			}
+			digest := digestRepo.digests[0]
+			if digest.Event != models.NotificationEventDigest || digest.Target != tt.target {
+				t.Errorf("digest is a %s %s notification, want a %s DIGEST", digest.Target, digest.Event, tt.target)
+			}
+			if want := fmt.Sprint(burst - tt.limit); digest.Data["total"] != want {
+				t.Errorf("digest of %s alerts, want %s", digest.Data["total"], want)
+			}
+			if len(digestRepo.items) != 0 {
+				t.Errorf("%d digest items left after the flush", len(digestRepo.items))
+			}
+		})
+	}
+}
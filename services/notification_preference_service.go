@@ -0,0 +1,118 @@
+package services
+
+import (
+	"NetGuardServer/dto"
+	"NetGuardServer/models"
+	"NetGuardServer/repository"
+	"NetGuardServer/utils"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NotificationPreferenceService defines the interface for notification preference business logic
+type NotificationPreferenceService interface {
+	GetPreferences(userID uuid.UUID) (*models.NotificationPreference, error)
+	UpdatePreferences(userID uuid.UUID, req dto.UpdateNotificationPreferenceRequest) (*models.NotificationPreference, error)
+}
+
+// notificationPreferenceService implements NotificationPreferenceService
+type notificationPreferenceService struct {
+	preferenceRepo repository.NotificationPreferenceRepository
+	serverRepo     repository.ServerRepository
+}
+
+// NewNotificationPreferenceService creates a new notification preference service instance
+func NewNotificationPreferenceService(preferenceRepo repository.NotificationPreferenceRepository, serverRepo repository.ServerRepository) NotificationPreferenceService {
+	return &notificationPreferenceService{
+		preferenceRepo: preferenceRepo,
+		serverRepo:     serverRepo,
+	}
+}
+
+// GetPreferences gets the notification preferences of a user, or the defaults if none were set
+func (s *notificationPreferenceService) GetPreferences(userID uuid.UUID) (*models.NotificationPreference, error) {
+	preference, err := s.preferenceRepo.FindByUserID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		defaults := models.DefaultNotificationPreference(userID)
+		return &defaults, nil
+	}
+	if err != nil {
+		return nil, errors.New("failed to get notification preferences")
+	}
+	return preference, nil
+}
+
+// UpdatePreferences updates the notification preferences of a user
+func (s *notificationPreferenceService) UpdatePreferences(userID uuid.UUID, req dto.UpdateNotificationPreferenceRequest) (*models.NotificationPreference, error) {
+	preference, err := s.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ServerIDs != nil {
+		if preference.ServerIDs, err = resolveServerIDs(s.serverRepo, req.ServerIDs); err != nil {
+			return nil, err
+		}
+	}
+	if req.Tags != nil {
+		preference.Tags = normalizeTags(req.Tags)
+	}
+	if req.Divisions != nil {
+		preference.Divisions = make([]string, 0, len(req.Divisions))
+		for _, division := range req.Divisions {
+			if division = strings.TrimSpace(division); division != "" {
+				preference.Divisions = append(preference.Divisions, division)
+			}
+		}
+	}
+	if req.Events != nil {
+		preference.Events = req.Events
+	}
+	if req.MinSeverity != "" {
+		preference.MinSeverity = req.MinSeverity
+	}
+	if req.Timezone != "" {
+		preference.Timezone = req.Timezone
+	}
+	if req.QuietHoursStart != nil {
+		preference.QuietHoursStart = *req.QuietHoursStart
+	}
+	if req.QuietHoursEnd != nil {
+		preference.QuietHoursEnd = *req.QuietHoursEnd
+	}
+
+	if err := validateQuietHours(preference.QuietHoursStart, preference.QuietHoursEnd); err != nil {
+		return nil, err
+	}
+
+	preference.UpdatedAt = time.Now()
+	if err := s.preferenceRepo.Save(preference); err != nil {
+		return nil, errors.New("failed to update notification preferences")
+	}
+
+	return preference, nil
+}
+
+// validateQuietHours checks that quiet hours are both set ("15:04") and differ, or both empty
+func validateQuietHours(start, end string) error {
+	if start == "" && end == "" {
+		return nil
+	}
+	if start == "" || end == "" {
+		return utils.ValidationError("quiet_hours_start and quiet_hours_end must be set together")
+	}
+	if _, err := time.Parse("15:04", start); err != nil {
+		return utils.ValidationError("quiet_hours_start must be a time (15:04)")
+	}
+	if _, err := time.Parse("15:04", end); err != nil {
+		return utils.ValidationError("quiet_hours_end must be a time (15:04)")
+	}
+	if start == end {
+		return utils.ValidationError("quiet_hours_start and quiet_hours_end must differ")
+	}
+	return nil
+}
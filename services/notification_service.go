@@ -2,80 +2,783 @@ package services
 
 import (
 	"NetGuardServer/config"
+	"NetGuardServer/models"
+	"NetGuardServer/notifier"
+	"NetGuardServer/repository"
+	"NetGuardServer/utils"
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	"firebase.google.com/go/v4/messaging"
 	fcm "github.com/appleboy/go-fcm"
 	"github.com/google/uuid"
 )
 
-// NotificationService defines the interface for notification business logic
+// NotificationService defines the interface for notification business logic.
+// Server alerts go to the given recipients (e.g. the on-call user), or to all active users
+// when there are none, except users whose notification preferences do not want them.
+// They are also delivered to the notification channels of the server.
+// Titles and bodies are rendered from the notification templates, push notifications
+// in the locale of each user and channel messages in the default locale.
+// Notifications are added to the outbox, one per channel and one for push notifications,
+// and delivered by DeliverDue with retries. Server alerts beyond the rate limit of a user or
+// channel are held back and sent together in a digest by FlushDigests.
 type NotificationService interface {
-	SendServerDownNotification(serverID uuid.UUID, serverName, serverURL string, reportedBy uuid.UUID) error
+	ServerDownNotifications(server *models.Server, reportedBy uuid.UUID, recipients []uuid.UUID) []models.NotificationOutbox
+	SendServerDownNotification(historyID uuid.UUID, server *models.Server, reportedBy uuid.UUID, recipients []uuid.UUID) error
+	SendServerRecoveredNotification(historyID uuid.UUID, server *models.Server, downtime time.Duration, recipients []uuid.UUID) error
+	SendCertificateExpiryNotification(server *models.Server, expiresAt time.Time, daysLeft int, recipients []uuid.UUID) error
+	SendServerFlappingNotification(server *models.Server, stateChanges int64, window time.Duration, recipients []uuid.UUID) error
+	SendServerStableNotification(server *models.Server, recipients []uuid.UUID) error
+	SendIncidentAcknowledgedNotification(history *models.ServerDownHistory, server *models.Server, acknowledgedBy *models.User, recipients []uuid.UUID) error
+	SendIncidentResolvedNotification(history *models.ServerDownHistory, server *models.Server, resolvedBy *models.User, recipients []uuid.UUID) error
+	SendIncidentAssignedNotification(history *models.ServerDownHistory, assignee *models.User, assignedBy string) error
+	SendEscalationNotification(history *models.ServerDownHistory, level int, userIDs []uuid.UUID) error
+	DeliverDue(ctx context.Context)
+	FlushDigests(ctx context.Context)
+	GetOutbox(status string, limit int) ([]models.NotificationOutbox, error)
+	RetryNotification(id uuid.UUID) (*models.NotificationOutbox, error)
 }
 
+// maxMulticastTokens is the number of device tokens FCM accepts per multicast message
+const maxMulticastTokens = 500
+
+// androidChannels maps notification events to the Android notification channels of the app,
+// so that users can set sound and importance per kind of notification
+var androidChannels = map[string]string{
+	models.NotificationEventDown:         "server_down",
+	models.NotificationEventRecovered:    "server_recovered",
+	models.NotificationEventCertExpiry:   "certificate_expiry",
+	models.NotificationEventFlapping:     "server_flapping",
+	models.NotificationEventAcknowledged: "incident_acknowledged",
+	models.NotificationEventResolved:     "incident_resolved",
+	models.NotificationEventAssigned:     "incident_assigned",
+	models.NotificationEventEscalated:    "incident_escalated",
+	models.NotificationEventDigest:       "notification_digest",
+}
+
+// urgentEvents are the notification events shown with high priority on Android
+var urgentEvents = map[string]bool{
+	models.NotificationEventDown:      true,
+	models.NotificationEventAssigned:  true,
+	models.NotificationEventEscalated: true,
+}
+
+// serverStatusTopic is the topic apps subscribe to before they register their device
+const serverStatusTopic = "serverdown"
+
 // notificationService implements NotificationService
 type notificationService struct {
-	fcmClient *fcm.Client
+	fcmClient       *fcm.Client
+	deviceTokenRepo repository.DeviceTokenRepository
+	preferenceRepo  repository.NotificationPreferenceRepository
+	userRepo        repository.UserRepository
+	channelRepo     repository.NotificationChannelRepository
+	outboxRepo      repository.NotificationOutboxRepository
+	digestRepo      repository.NotificationDigestRepository
+	eventRepo       repository.HistoryEventRepository
+	templateService NotificationTemplateService
+	dispatcher      *notifier.Dispatcher
 }
 
 // NewNotificationService creates a new notification service instance
-func NewNotificationService() NotificationService {
+func NewNotificationService(deviceTokenRepo repository.DeviceTokenRepository, preferenceRepo repository.NotificationPreferenceRepository, userRepo repository.UserRepository, channelRepo repository.NotificationChannelRepository, outboxRepo repository.NotificationOutboxRepository, digestRepo repository.NotificationDigestRepository, eventRepo repository.HistoryEventRepository, templateService NotificationTemplateService, dispatcher *notifier.Dispatcher) NotificationService {
+	service := &notificationService{
+		deviceTokenRepo: deviceTokenRepo,
+		preferenceRepo:  preferenceRepo,
+		userRepo:        userRepo,
+		channelRepo:     channelRepo,
+		outboxRepo:      outboxRepo,
+		digestRepo:      digestRepo,
+		eventRepo:       eventRepo,
+		templateService: templateService,
+		dispatcher:      dispatcher,
+	}
+
 	ctx := context.Background()
 	client, err := fcm.NewClient(
 		ctx,
 		fcm.WithCredentialsFile(config.AppConfig.FirebaseServiceAccountPath),
 	)
 	if err != nil {
-		log.Printf("Failed to create FCM client: %v", err)
-		return &notificationService{fcmClient: nil}
+		log.Printf("Failed to create FCM client, push notifications are disabled and only notification channels are used: %v", err)
+		return service
 	}
 
-	return &notificationService{
-		fcmClient: client,
+	service.fcmClient = client
+	return service
+}
+
+// ServerDownNotifications builds the notifications of a DOWN server without adding them
+// to the outbox, so that they can be stored in the transaction opening the incident
+func (s *notificationService) ServerDownNotifications(server *models.Server, reportedBy uuid.UUID, recipients []uuid.UUID) []models.NotificationOutbox {
+	return s.alertNotifications(models.NewAlert(models.NotificationEventDown, server), nil, recipients, map[string]string{
+		"server_id":   server.ID.String(),
+		"server_name": server.Name,
+		"server_url":  server.URL,
+		"status":      "DOWN",
+		"severity":    server.Severity,
+		"reported_by": reportedBy.String(),
+	})
+}
+
+// SendServerDownNotification queues the notifications of a DOWN server for an existing incident
+func (s *notificationService) SendServerDownNotification(historyID uuid.UUID, server *models.Server, reportedBy uuid.UUID, recipients []uuid.UUID) error {
+	notifications := s.ServerDownNotifications(server, reportedBy, recipients)
+	for i := range notifications {
+		notifications[i].HistoryID = &historyID
 	}
+	return s.outboxRepo.Create(notifications)
+}
+
+// SendServerRecoveredNotification queues notifications when a DOWN server is back UP
+func (s *notificationService) SendServerRecoveredNotification(historyID uuid.UUID, server *models.Server, downtime time.Duration, recipients []uuid.UUID) error {
+	return s.sendAlert(models.NewAlert(models.NotificationEventRecovered, server), &historyID, recipients, map[string]string{
+		"server_id":        server.ID.String(),
+		"server_name":      server.Name,
+		"server_url":       server.URL,
+		"status":           "UP",
+		"severity":         server.Severity,
+		"downtime_seconds": fmt.Sprintf("%d", int64(downtime.Seconds())),
+	})
+}
+
+// SendCertificateExpiryNotification queues notifications when a server's TLS certificate is about to expire
+func (s *notificationService) SendCertificateExpiryNotification(server *models.Server, expiresAt time.Time, daysLeft int, recipients []uuid.UUID) error {
+	return s.sendAlert(models.NewAlert(models.NotificationEventCertExpiry, server), nil, recipients, map[string]string{
+		"server_id":   server.ID.String(),
+		"server_name": server.Name,
+		"server_url":  server.URL,
+		"status":      "CERT_EXPIRING",
+		"severity":    server.Severity,
+		"expires_at":  expiresAt.Format(time.RFC3339),
+		"days_left":   fmt.Sprintf("%d", daysLeft),
+	})
+}
+
+// SendServerFlappingNotification queues one notification when a server starts flapping,
+// instead of a DOWN/UP notification per state change
+func (s *notificationService) SendServerFlappingNotification(server *models.Server, stateChanges int64, window time.Duration, recipients []uuid.UUID) error {
+	return s.sendAlert(models.NewAlert(models.NotificationEventFlapping, server), nil, recipients, map[string]string{
+		"server_id":      server.ID.String(),
+		"server_name":    server.Name,
+		"server_url":     server.URL,
+		"status":         "FLAPPING",
+		"severity":       server.Severity,
+		"state_changes":  fmt.Sprintf("%d", stateChanges),
+		"window_seconds": fmt.Sprintf("%d", int64(window.Seconds())),
+		"flapping":       "true",
+	})
+}
+
+// SendServerStableNotification queues notifications when a flapping server settles on a status
+func (s *notificationService) SendServerStableNotification(server *models.Server, recipients []uuid.UUID) error {
+	return s.sendAlert(models.NewAlert(models.NotificationEventFlapping, server), nil, recipients, map[string]string{
+		"server_id":   server.ID.String(),
+		"server_name": server.Name,
+		"server_url":  server.URL,
+		"status":      server.Status,
+		"severity":    server.Severity,
+		"flapping":    "false",
+	})
 }
 
-// SendServerDownNotification sends FCM notification when server is down
-func (s *notificationService) SendServerDownNotification(serverID uuid.UUID, serverName, serverURL string, reportedBy uuid.UUID) error {
+// SendIncidentAcknowledgedNotification queues notifications telling the recipients, or all
+// active users when there are none, that a user took ownership of an incident
+func (s *notificationService) SendIncidentAcknowledgedNotification(history *models.ServerDownHistory, server *models.Server, acknowledgedBy *models.User, recipients []uuid.UUID) error {
+	alert := models.NewAlert(models.NotificationEventAcknowledged, server)
+	alert.Actor = acknowledgedBy.ID
+
+	data := incidentData(history, models.HistoryStatusAcknowledged, time.Now())
+	data["acknowledged_by"] = acknowledgedBy.Name
+	data["severity"] = server.Severity
+
+	return s.sendAlert(alert, &history.ID, recipients, data)
+}
+
+// SendIncidentResolvedNotification queues notifications telling the recipients, or all active
+// users when there are none, that a user resolved an incident, with the resolve note
+func (s *notificationService) SendIncidentResolvedNotification(history *models.ServerDownHistory, server *models.Server, resolvedBy *models.User, recipients []uuid.UUID) error {
+	alert := models.NewAlert(models.NotificationEventResolved, server)
+	alert.Actor = resolvedBy.ID
+
+	data := incidentData(history, models.HistoryStatusResolved, time.Now())
+	data["resolved_by"] = resolvedBy.Name
+	data["resolve_note"] = history.ResolveNote
+	data["severity"] = server.Severity
+
+	return s.sendAlert(alert, &history.ID, recipients, data)
+}
+
+// SendIncidentAssignedNotification queues a push notification to the user an incident was
+// assigned to. It asks the user to act, so notification preferences do not apply.
+func (s *notificationService) SendIncidentAssignedNotification(history *models.ServerDownHistory, assignee *models.User, assignedBy string) error {
+	data := incidentData(history, "ASSIGNED", time.Now())
+	data["assigned_by"] = assignedBy
+
+	return s.sendPush(history.ID, models.NotificationEventAssigned, []uuid.UUID{assignee.ID}, assignee.Name, data)
+}
+
+// SendEscalationNotification queues a push notification to every user of an escalation level
+// about an incident nobody acknowledged yet. Notification preferences do not apply.
+func (s *notificationService) SendEscalationNotification(history *models.ServerDownHistory, level int, userIDs []uuid.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	data := incidentData(history, "ESCALATED", time.Now())
+	data["escalation_level"] = fmt.Sprintf("%d", level)
+
+	return s.sendPush(history.ID, models.NotificationEventEscalated, userIDs, fmt.Sprintf("level %d escalation (%d users)", level, len(userIDs)), data)
+}
+
+// incidentData returns the data payload shared by the notifications about an incident.
+// downtime_seconds is the downtime so far while the incident is open.
+func incidentData(history *models.ServerDownHistory, status string, now time.Time) map[string]string {
+	return map[string]string{
+		"history_id":       history.ID.String(),
+		"server_id":        history.ServerID.String(),
+		"server_name":      history.ServerName,
+		"server_url":       history.URL,
+		"status":           status,
+		"downtime_seconds": fmt.Sprintf("%d", int64(history.Downtime(now).Seconds())),
+	}
+}
+
+// sendPush queues a push notification to users, regardless of their notification preferences
+func (s *notificationService) sendPush(historyID uuid.UUID, event string, userIDs []uuid.UUID, recipient string, data map[string]string) error {
+	return s.outboxRepo.Create(s.pushNotifications(models.NotificationOutbox{
+		HistoryID: &historyID,
+		Event:     event,
+		Target:    models.OutboxTargetPush,
+		Recipient: recipient,
+		Data:      data,
+	}, userIDs))
+}
+
+// sendAlert queues the notifications of a server alert
+func (s *notificationService) sendAlert(alert models.Alert, historyID *uuid.UUID, recipients []uuid.UUID, data map[string]string) error {
+	return s.outboxRepo.Create(s.alertNotifications(alert, historyID, recipients, data))
+}
+
+// alertNotifications builds the notifications of a server alert: one for every notification
+// channel that receives it, and push notifications to the recipients, or to all active users
+// when there are none, leaving out users whose notification preferences do not want it.
+// An alert to all users is also broadcast once on the serverdown topic, for apps that
+// have not registered their device.
+func (s *notificationService) alertNotifications(alert models.Alert, historyID *uuid.UUID, recipients []uuid.UUID, data map[string]string) []models.NotificationOutbox {
+	var notifications []models.NotificationOutbox
+	notification := func(target, recipient string) models.NotificationOutbox {
+		return models.NotificationOutbox{
+			HistoryID: historyID,
+			ServerID:  &alert.ServerID,
+			Event:     alert.Event,
+			Target:    target,
+			Recipient: recipient,
+			Data:      data,
+		}
+	}
+
+	channels, err := s.channelRepo.FindEnabled()
+	if err != nil {
+		log.Printf("ERROR: Failed to get notification channels: %v", err)
+	}
+	for i := range channels {
+		channel := &channels[i]
+		if !channel.Receives(alert) {
+			continue
+		}
+		n := notification(models.OutboxTargetChannel, fmt.Sprintf("%s channel %s", channel.Type, channel.Name))
+		n.ChannelID = &channel.ID
+		n.Title, n.Body = s.templateService.Render(alert.Event, channel.Type, DefaultLocale(), data)
+		notifications = append(notifications, n)
+	}
+
+	// Without FCM client push notifications are disabled, queueing them would only fail
+	if s.fcmClient == nil {
+		return notifications
+	}
+
+	if len(recipients) > 0 {
+		alert.Personal = true
+	} else {
+		users, err := s.userRepo.FindActive()
+		if err != nil {
+			log.Printf("ERROR: Failed to get users to notify: %v", err)
+			return notifications
+		}
+		for _, user := range users {
+			recipients = append(recipients, user.ID)
+		}
+	}
+
+	preferences, err := s.preferenceRepo.FindByUserIDs(recipients)
+	if err != nil {
+		log.Printf("ERROR: Failed to get notification preferences, notifying all recipients: %v", err)
+	} else {
+		recipients = alert.WantedBy(recipients, preferences, time.Now())
+	}
+
+	if len(recipients) == 0 {
+		log.Printf("No user wants the %s alert of server %s", alert.Event, alert.ServerID)
+		return notifications
+	}
+
+	recipient := fmt.Sprintf("%d subscribed user(s)", len(recipients))
+	if alert.Personal {
+		recipient = "the on-call user"
+	}
+	notifications = append(notifications, s.pushNotifications(notification(models.OutboxTargetPush, recipient), recipients)...)
+
+	if !alert.Personal {
+		n := notification(models.OutboxTargetTopic, "the "+serverStatusTopic+" topic")
+		n.Title, n.Body = s.templateService.Render(alert.Event, models.TemplateChannelPush, DefaultLocale(), data)
+		notifications = append(notifications, n)
+	}
+	return notifications
+}
+
+// pushNotifications builds the push notifications of users from a notification without
+// title and body: one per locale of the users, rendered in that locale. Users without
+// locale get the default locale.
+func (s *notificationService) pushNotifications(base models.NotificationOutbox, userIDs []uuid.UUID) []models.NotificationOutbox {
+	locales := make(map[uuid.UUID]string, len(userIDs))
+	users, err := s.userRepo.FindByIDs(userIDs)
+	if err != nil {
+		log.Printf("ERROR: Failed to get user locales, notifying in the default locale: %v", err)
+	}
+	for _, user := range users {
+		locales[user.ID] = user.Locale
+	}
+
+	byLocale := make(map[string][]uuid.UUID)
+	for _, userID := range userIDs {
+		locale := locales[userID]
+		if !slices.Contains(models.Locales, locale) {
+			locale = DefaultLocale()
+		}
+		byLocale[locale] = append(byLocale[locale], userID)
+	}
+
+	var notifications []models.NotificationOutbox
+	for _, locale := range models.Locales {
+		if len(byLocale[locale]) == 0 {
+			continue
+		}
+		n := base
+		n.UserIDs = byLocale[locale]
+		n.Title, n.Body = s.templateService.Render(base.Event, models.TemplateChannelPush, locale, base.Data)
+		if len(byLocale) > 1 {
+			n.Recipient = fmt.Sprintf("%s (%s)", base.Recipient, locale)
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications
+}
+
+// outboxBatchSize is the number of notifications claimed from the outbox at once
+const outboxBatchSize = 50
+
+// outboxLease is how long a claimed notification is hidden from other workers while
+// it is being delivered; it is retried afterwards if its delivery never finished
+const outboxLease = 5 * time.Minute
+
+// DeliverDue delivers the due notifications of the outbox. A failed delivery is retried
+// with exponential backoff until OUTBOX_MAX_ATTEMPTS, then the notification is dead.
+// Every attempt is added to the delivery log of the notification.
+func (s *notificationService) DeliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		notifications, err := s.outboxRepo.ClaimDue(time.Now(), outboxLease, outboxBatchSize)
+		if err != nil {
+			log.Printf("ERROR: Failed to get due notifications: %v", err)
+			return
+		}
+
+		for i := range notifications {
+			if ctx.Err() != nil {
+				return
+			}
+			s.deliver(ctx, &notifications[i])
+		}
+
+		if len(notifications) < outboxBatchSize {
+			return
+		}
+	}
+}
+
+// deliver makes a delivery attempt of an outbox notification and records its outcome
+func (s *notificationService) deliver(ctx context.Context, notification *models.NotificationOutbox) {
+	cfg := config.AppConfig.Outbox
+	base := time.Duration(cfg.RetryBaseSeconds) * time.Second
+	if base <= 0 {
+		base = 30 * time.Second
+	}
+	maxDelay := max(time.Duration(cfg.RetryMaxSeconds)*time.Second, base)
+
+	if notification.RateLimited() && s.holdBack(notification) {
+		return
+	}
+
+	started := time.Now()
+	err := s.send(ctx, notification)
+	attempt := notification.RecordAttempt(err, started, time.Now(), max(cfg.MaxAttempts, 1), base, maxDelay)
+
+	if err := s.outboxRepo.SaveAttempt(notification, &attempt); err != nil {
+		log.Printf("ERROR: Failed to record delivery attempt of notification %s: %v", notification.ID, err)
+	}
+
+	switch notification.Status {
+	case models.OutboxStatusSent:
+		log.Printf("INFO: %s delivered (attempt %d)", notification.Describe(), notification.Attempts)
+		s.recordDelivery(notification, notification.Describe()+" delivered")
+	case models.OutboxStatusDead:
+		log.Printf("ERROR: %s failed after %d attempt(s), giving up: %v", notification.Describe(), notification.Attempts, err)
+		s.recordDelivery(notification, fmt.Sprintf("%s failed after %d attempt(s): %v", notification.Describe(), notification.Attempts, err))
+	default:
+		log.Printf("WARN: %s failed (attempt %d), retrying at %s: %v", notification.Describe(), notification.Attempts, notification.NextAttemptAt.Format(time.RFC3339), err)
+	}
+}
+
+// holdBack applies the rate limits of the recipients of a server alert. Recipients over their
+// limit get the alert in their next digest instead and are removed from the notification.
+// It tells whether no recipient is left, so that the notification is not delivered.
+func (s *notificationService) holdBack(notification *models.NotificationOutbox) bool {
+	cfg := config.AppConfig.Notification
+	window := time.Duration(cfg.RateWindowSeconds) * time.Second
+	if window <= 0 {
+		window = time.Minute
+	}
+
+	var keys []string
+	var limit int
+	switch {
+	case notification.Target == models.OutboxTargetPush && cfg.UserRateLimit > 0:
+		limit = cfg.UserRateLimit
+		for _, userID := range notification.UserIDs {
+			keys = append(keys, models.UserRateKey(userID))
+		}
+		slices.Sort(keys)
+		keys = slices.Compact(keys)
+	case notification.Target == models.OutboxTargetChannel && cfg.ChannelRateLimit > 0 && notification.ChannelID != nil:
+		limit = cfg.ChannelRateLimit
+		keys = []string{models.ChannelRateKey(*notification.ChannelID)}
+	default:
+		return false
+	}
+
+	counts, err := s.digestRepo.Hit(keys, time.Now(), window)
+	if err != nil {
+		log.Printf("ERROR: Failed to apply rate limits to %s, delivering it: %v", notification.Describe(), err)
+		return false
+	}
+
+	item := func(key string) models.NotificationDigestItem {
+		return models.NotificationDigestItem{
+			RecipientKey: key,
+			OutboxID:     notification.ID,
+			Event:        notification.Event,
+			ServerID:     notification.ServerID,
+			ServerName:   notification.Data["server_name"],
+		}
+	}
+
+	var items []models.NotificationDigestItem
+	remaining := notification.UserIDs
+	if notification.Target == models.OutboxTargetChannel {
+		if counts[keys[0]] > limit {
+			heldBack := item(keys[0])
+			heldBack.ChannelID = notification.ChannelID
+			items = append(items, heldBack)
+		}
+	} else {
+		remaining = nil
+		for _, userID := range notification.UserIDs {
+			key := models.UserRateKey(userID)
+			if counts[key] <= limit {
+				remaining = append(remaining, userID)
+				continue
+			}
+			heldBack := item(key)
+			heldBack.UserID = &userID
+			items = append(items, heldBack)
+		}
+	}
+	if len(items) == 0 {
+		return false
+	}
+
+	userIDs, status := notification.UserIDs, notification.Status
+	notification.UserIDs = remaining
+	if notification.Target == models.OutboxTargetChannel || len(remaining) == 0 {
+		notification.Status = models.OutboxStatusDigested
+	}
+	if err := s.digestRepo.Defer(notification, items); err != nil {
+		log.Printf("ERROR: Failed to hold back %s for a digest, delivering it: %v", notification.Describe(), err)
+		notification.UserIDs, notification.Status = userIDs, status
+		return false
+	}
+
+	log.Printf("INFO: %s held back from %d rate limited recipient(s) for a digest", notification.Describe(), len(items))
+	return notification.Status == models.OutboxStatusDigested
+}
+
+// FlushDigests queues a digest notification for every user and notification channel whose
+// oldest held back alert is NOTIFICATION_DIGEST_INTERVAL_SECONDS old, e.g.
+// "7 server(s) down, 2 recovered"
+func (s *notificationService) FlushDigests(ctx context.Context) {
+	interval := time.Duration(config.AppConfig.Notification.DigestIntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	items, err := s.digestRepo.FindDue(time.Now().Add(-interval))
+	if err != nil {
+		log.Printf("ERROR: Failed to get due digests: %v", err)
+		return
+	}
+	if len(items) == 0 {
+		return
+	}
+
+	var keys []string
+	groups := make(map[string][]models.NotificationDigestItem)
+	var userIDs []uuid.UUID
+	for _, item := range items {
+		if _, ok := groups[item.RecipientKey]; !ok {
+			keys = append(keys, item.RecipientKey)
+			if item.UserID != nil {
+				userIDs = append(userIDs, *item.UserID)
+			}
+		}
+		groups[item.RecipientKey] = append(groups[item.RecipientKey], item)
+	}
+
+	users := make(map[uuid.UUID]models.User, len(userIDs))
+	found, err := s.userRepo.FindByIDs(userIDs)
+	if err != nil {
+		log.Printf("ERROR: Failed to get digest recipients, using the default locale: %v", err)
+	}
+	for _, user := range found {
+		users[user.ID] = user
+	}
+
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			return
+		}
+
+		digest := s.digestNotification(groups[key], users)
+		flushed, err := s.digestRepo.Flush(groups[key], digest)
+		switch {
+		case err != nil:
+			log.Printf("ERROR: Failed to queue digest of %s: %v", key, err)
+		case flushed && digest != nil:
+			log.Printf("INFO: Queued digest of %d notification(s) to %s", len(groups[key]), digest.Recipient)
+		}
+	}
+}
+
+// digestNotification builds the digest notification of the alerts held back from a user or
+// notification channel. It returns nil if the channel no longer exists or is disabled.
+func (s *notificationService) digestNotification(items []models.NotificationDigestItem, users map[uuid.UUID]models.User) *models.NotificationOutbox {
+	notification := &models.NotificationOutbox{Event: models.NotificationEventDigest}
+	channel, locale := models.TemplateChannelPush, DefaultLocale()
+
+	first := items[0]
+	switch {
+	case first.ChannelID != nil:
+		ch, err := s.channelRepo.FindByID(*first.ChannelID)
+		if err != nil || ch.Disabled {
+			log.Printf("WARN: Dropping digest of %d notification(s) to unavailable channel %s", len(items), first.ChannelID)
+			return nil
+		}
+		notification.Target = models.OutboxTargetChannel
+		notification.ChannelID = &ch.ID
+		notification.Recipient = fmt.Sprintf("%s channel %s", ch.Type, ch.Name)
+		channel = ch.Type
+	case first.UserID != nil:
+		notification.Target = models.OutboxTargetPush
+		notification.UserIDs = []uuid.UUID{*first.UserID}
+		notification.Recipient = first.UserID.String()
+		if user, ok := users[*first.UserID]; ok {
+			notification.Recipient = user.Name
+			if slices.Contains(models.Locales, user.Locale) {
+				locale = user.Locale
+			}
+		}
+	default:
+		return nil
+	}
+
+	digest := models.NewDigest(items)
+	notification.Data = map[string]string{
+		"status":  "DIGEST",
+		"total":   fmt.Sprintf("%d", digest.Total),
+		"summary": notifier.DigestSummary(locale, digest.Counts),
+		"servers": digestServers(digest.Servers),
+	}
+	notification.Title, notification.Body = s.templateService.Render(models.NotificationEventDigest, channel, locale, notification.Data)
+	return notification
+}
+
+// maxDigestServers is the number of server names listed in a digest
+const maxDigestServers = 10
+
+// digestServers lists the servers of a digest, e.g. "API Server, Database, +3"
+func digestServers(servers []string) string {
+	if len(servers) <= maxDigestServers {
+		return strings.Join(servers, ", ")
+	}
+	return fmt.Sprintf("%s, +%d", strings.Join(servers[:maxDigestServers], ", "), len(servers)-maxDigestServers)
+}
+
+// send delivers an outbox notification to its target
+func (s *notificationService) send(ctx context.Context, notification *models.NotificationOutbox) error {
+	data := notificationData(notification)
+
+	switch notification.Target {
+	case models.OutboxTargetPush:
+		return s.sendToUsers(notification, data)
+	case models.OutboxTargetTopic:
+		data["title"] = notification.Title
+		data["body"] = notification.Body
+		return s.sendToTopic(notification.Event, serverStatusTopic, notification.Title, notification.Body, data)
+	case models.OutboxTargetChannel:
+		if notification.ChannelID == nil {
+			return errors.New("notification channel missing")
+		}
+		channel, err := s.channelRepo.FindByID(*notification.ChannelID)
+		if err != nil {
+			return errors.New("notification channel not found")
+		}
+		if channel.Disabled {
+			return errors.New("notification channel disabled")
+		}
+		return s.dispatcher.Send(ctx, channel, notifier.Message{
+			Event:     notification.Event,
+			Title:     notification.Title,
+			Body:      notification.Body,
+			Data:      data,
+			Timestamp: notification.CreatedAt,
+		})
+	default:
+		return fmt.Errorf("unsupported notification target %q", notification.Target)
+	}
+}
+
+// notificationData returns the data payload of an outbox notification. Every payload tells its
+// event in "type" and, when it is about an incident, the incident in "history_id", so that
+// clients can render each kind of notification and link to the incident.
+func notificationData(notification *models.NotificationOutbox) map[string]string {
+	data := maps.Clone(notification.Data)
+	if data == nil {
+		data = make(map[string]string)
+	}
+	data["type"] = notification.Event
+	if notification.HistoryID != nil {
+		data["history_id"] = notification.HistoryID.String()
+	}
+	return data
+}
+
+// recordDelivery adds the outcome of a notification about an incident to its timeline
+func (s *notificationService) recordDelivery(notification *models.NotificationOutbox, message string) {
+	if notification.HistoryID == nil {
+		return
+	}
+
+	event := &models.HistoryEvent{
+		HistoryID: *notification.HistoryID,
+		Type:      models.HistoryEventNotification,
+		AuthorID:  models.SystemUserID,
+		Message:   message,
+	}
+	if err := s.eventRepo.Create(event); err != nil {
+		log.Printf("ERROR: Failed to record notification event for history %s: %v", *notification.HistoryID, err)
+	}
+}
+
+// GetOutbox gets the latest outbox notifications, optionally of a single status
+func (s *notificationService) GetOutbox(status string, limit int) ([]models.NotificationOutbox, error) {
+	switch status {
+	case "", models.OutboxStatusPending, models.OutboxStatusSent, models.OutboxStatusDigested, models.OutboxStatusDead:
+	default:
+		return nil, utils.ValidationError("status must be PENDING, SENT, DIGESTED or DEAD")
+	}
+
+	notifications, err := s.outboxRepo.FindAll(status, limit)
+	if err != nil {
+		return nil, errors.New("failed to get notifications")
+	}
+	return notifications, nil
+}
+
+// RetryNotification schedules a dead notification for another delivery attempt
+func (s *notificationService) RetryNotification(id uuid.UUID) (*models.NotificationOutbox, error) {
+	requeued, err := s.outboxRepo.Requeue(id, time.Now())
+	if err != nil {
+		return nil, errors.New("failed to retry notification")
+	}
+
+	notification, err := s.outboxRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("notification not found")
+	}
+	if !requeued {
+		return nil, errors.New("only dead notifications can be retried")
+	}
+
+	return notification, nil
+}
+
+// sendToUsers sends a push notification to the registered devices of its recipients. The devices
+// it failed to reach are left in Pending, a retry only sends to those. The title and body are
+// added to data.
+func (s *notificationService) sendToUsers(notification *models.NotificationOutbox, data map[string]string) error {
+	title, body := notification.Title, notification.Body
+	data["title"] = title
+	data["body"] = body
+
+	tokens := notification.Pending
+	if len(tokens) == 0 {
+		devices, err := s.deviceTokenRepo.FindByUserIDs(notification.UserIDs)
+		if err != nil {
+			return fmt.Errorf("failed to get device tokens: %w", err)
+		}
+		for _, device := range devices {
+			tokens = append(tokens, device.Token)
+		}
+	}
+	if len(tokens) == 0 {
+		return errors.New("no registered device to deliver to")
+	}
+
+	failed, err := s.sendToTokens(notification.Event, tokens, title, body, data)
+	notification.Pending = failed
+	return err
+}
+
+// sendToTopic sends a high priority message to a topic -
+// all users subscribed to this topic will receive the notification
+func (s *notificationService) sendToTopic(event, topic, title, body string, data map[string]string) error {
 	if s.fcmClient == nil {
 		return fmt.Errorf("FCM client not initialized")
 	}
 
 	ctx := context.Background()
 
-	// Send to topic "serverdown" - all users subscribed to this topic will receive the notification
 	message := &messaging.Message{
-		Data: map[string]string{
-			"title":       fmt.Sprintf("Server DOWN: %s", serverName),
-			"body":        serverURL,
-			"server_id":   serverID.String(),
-			"server_name": serverName,
-			"server_url":  serverURL,
-			"status":      "DOWN",
-			"reported_by": reportedBy.String(),
-		},
-		Android: &messaging.AndroidConfig{
-			Notification: &messaging.AndroidNotification{
-				Title:     fmt.Sprintf("Server DOWN: %s", serverName),
-				Body:      serverURL,
-				ChannelID: "server_status",
-				Priority:  messaging.AndroidNotificationPriority(messaging.PriorityHigh),
-			},
-		},
-		APNS: &messaging.APNSConfig{
-			Headers: map[string]string{
-				"apns-priority": "10", // silent notification
-			},
-			Payload: &messaging.APNSPayload{
-				Aps: &messaging.Aps{
-					ContentAvailable: true, // required for silent push
-				},
-			},
-		},
-		Topic: "serverdown",
+		Data:    data,
+		Android: androidConfig(event, title, body),
+		APNS:    apnsConfig(),
+		Topic:   topic,
 	}
 
 	// Send the message
@@ -93,3 +796,108 @@ func (s *notificationService) SendServerDownNotification(serverID uuid.UUID, ser
 
 	return nil
 }
+
+// sendToTokens sends a high priority message to device tokens, in batches of
+// maxMulticastTokens. Tokens FCM reports as unregistered or invalid are removed.
+// It returns the tokens worth retrying, the message did not reach them.
+func (s *notificationService) sendToTokens(event string, tokens []string, title, body string, data map[string]string) ([]string, error) {
+	if s.fcmClient == nil {
+		return tokens, fmt.Errorf("FCM client not initialized")
+	}
+
+	ctx := context.Background()
+
+	var failed []string
+	var errs []error
+	for start := 0; start < len(tokens); start += maxMulticastTokens {
+		batch := tokens[start:min(start+maxMulticastTokens, len(tokens))]
+
+		resp, err := s.fcmClient.SendMulticast(ctx, &messaging.MulticastMessage{
+			Tokens:  batch,
+			Data:    data,
+			Android: androidConfig(event, title, body),
+			APNS:    apnsConfig(),
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to send FCM multicast message: %w", err))
+			failed = append(failed, batch...)
+			continue
+		}
+
+		var invalid []string
+		for i, result := range resp.Responses {
+			switch {
+			case result.Success:
+			case isInvalidToken(result.Error, resp.SuccessCount > 0):
+				invalid = append(invalid, batch[i])
+			default:
+				failed = append(failed, batch[i])
+			}
+		}
+		if len(invalid) > 0 {
+			if err := s.deviceTokenRepo.DeleteTokens(invalid); err != nil {
+				log.Printf("ERROR: Failed to remove %d invalid device token(s): %v", len(invalid), err)
+			} else {
+				log.Printf("Removed %d invalid device token(s)", len(invalid))
+			}
+		}
+
+		log.Printf("FCM multicast notification sent. Success: %d, Failure: %d",
+			resp.SuccessCount, resp.FailureCount)
+
+		// Removed tokens are not worth retrying, other failures are
+		if failures := resp.FailureCount - len(invalid); failures > 0 {
+			errs = append(errs, fmt.Errorf("FCM multicast notification partially failed: %d success, %d failure", resp.SuccessCount, failures))
+		}
+	}
+	return failed, errors.Join(errs...)
+}
+
+// isInvalidToken tells whether a send error means the device token will never work again.
+// INVALID_ARGUMENT also covers a malformed message, so it only counts as an invalid
+// token when the same message reached other devices.
+func isInvalidToken(err error, messageDelivered bool) bool {
+	if err == nil {
+		return false
+	}
+	if messaging.IsUnregistered(err) || messaging.IsSenderIDMismatch(err) {
+		return true
+	}
+	return messageDelivered && messaging.IsInvalidArgument(err)
+}
+
+// androidConfig returns the Android options of a notification about an event:
+// its notification channel, and high priority for events that ask users to act
+func androidConfig(event, title, body string) *messaging.AndroidConfig {
+	channelID, ok := androidChannels[event]
+	if !ok {
+		channelID = "server_status"
+	}
+	priority := messaging.PriorityDefault
+	if urgentEvents[event] {
+		priority = messaging.PriorityHigh
+	}
+
+	return &messaging.AndroidConfig{
+		Notification: &messaging.AndroidNotification{
+			Title:     title,
+			Body:      body,
+			ChannelID: channelID,
+			Priority:  messaging.AndroidNotificationPriority(priority),
+		},
+	}
+}
+
+// apnsConfig returns the APNs options of a server status notification
+func apnsConfig() *messaging.APNSConfig {
+	return &messaging.APNSConfig{
+		Headers: map[string]string{
+			"apns-priority": "10", // silent notification
+		},
+		Payload: &messaging.APNSPayload{
+			Aps: &messaging.Aps{
+				ContentAvailable: true, // required for silent push
+			},
+		},
+	}
+}
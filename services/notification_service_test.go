@@ -0,0 +1,247 @@
+package services
+
+import (
+	"NetGuardServer/models"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	fcm "github.com/appleboy/go-fcm"
+	"github.com/google/uuid"
+	"google.golang.org/api/option"
+)
+
+// fcmStandIn is a local FCM endpoint that records the devices and topics messages were sent to.
+// Tokens in fail are rejected until fail is cleared, tokens in unregistered always are.
+type fcmStandIn struct {
+	mu           sync.Mutex
+	received     map[string]int
+	fail         map[string]bool
+	unregistered map[string]bool
+}
+
+func (f *fcmStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Message struct {
+			Token string `json:"token"`
+			Topic string `json:"topic"`
+		} `json:"message"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	target := req.Message.Token
+	if target == "" {
+		target = "/topics/" + req.Message.Topic
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case f.unregistered[target]:
+		fcmError(w, http.StatusNotFound, "NOT_FOUND", "UNREGISTERED")
+	case f.fail[target]:
+		fcmError(w, http.StatusUnauthorized, "UNAUTHENTICATED", "THIRD_PARTY_AUTH_ERROR")
+	default:
+		f.received[target]++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name": "projects/test/messages/1"}`))
+	}
+}
+
+// fcmError writes an FCM v1 error response
+func fcmError(w http.ResponseWriter, code int, status, errorCode string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{
+		"code":    code,
+		"status":  status,
+		"message": errorCode,
+		"details": []map[string]string{{"@type": "type.googleapis.com/google.firebase.fcm.v1.FcmError", "errorCode": errorCode}},
+	}})
+}
+
+// newPushTest returns a notification service sending push notifications to the FCM stand-in
+func newPushTest(t *testing.T, standIn *fcmStandIn, devices ...models.DeviceToken) (*notificationService, *fakeDeviceTokenRepository) {
+	t.Helper()
+	server := httptest.NewServer(standIn)
+	t.Cleanup(server.Close)
+
+	client, err := fcm.NewClient(context.Background(),
+		fcm.WithProjectID("test"),
+		fcm.WithEndpoint(server.URL),
+		fcm.WithCustomClientOption(option.WithoutAuthentication()),
+	)
+	if err != nil {
+		t.Fatalf("failed to create FCM client: %v", err)
+	}
+	deviceRepo := &fakeDeviceTokenRepository{devices: slices.Clone(devices)}
+	return &notificationService{fcmClient: client, deviceTokenRepo: deviceRepo}, deviceRepo
+}
+
+func TestSendToUsers(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	serverID := uuid.New()
+	devices := []models.DeviceToken{
+		{UserID: alice, Token: "alice-phone"},
+		{UserID: alice, Token: "alice-tablet"},
+		{UserID: alice, Token: "alice-old-phone"},
+	}
+	tests := []struct {
+		name         string
+		notification models.NotificationOutbox
+		fail         []string
+		wantPending  []string
+		wantFirst    map[string]int
+		wantRetry    map[string]int
+	}{
+		{
+			name:         "partial failure retries only the failed device",
+			notification: models.NotificationOutbox{Event: models.NotificationEventDown, ServerID: &serverID, UserIDs: []uuid.UUID{alice}},
+			fail:         []string{"alice-tablet"},
+			wantPending:  []string{"alice-tablet"},
+			wantFirst:    map[string]int{"alice-phone": 1},
+			wantRetry:    map[string]int{"alice-phone": 1, "alice-tablet": 1},
+		},
+		{
+			name:         "server alert only reaches registered devices",
+			notification: models.NotificationOutbox{Event: models.NotificationEventDown, ServerID: &serverID, UserIDs: []uuid.UUID{alice, bob}},
+			fail:         []string{"alice-phone"},
+			wantPending:  []string{"alice-phone"},
+			wantFirst:    map[string]int{"alice-tablet": 1},
+			wantRetry:    map[string]int{"alice-phone": 1, "alice-tablet": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standIn := &fcmStandIn{
+				received:     make(map[string]int),
+				fail:         make(map[string]bool),
+				unregistered: map[string]bool{"alice-old-phone": true},
+			}
+			for _, target := range tt.fail {
+				standIn.fail[target] = true
+			}
+			service, deviceRepo := newPushTest(t, standIn, devices...)
+			notification := tt.notification
+
+			if err := service.sendToUsers(&notification, map[string]string{}); err == nil {
+				t.Fatal("expected the first attempt to fail")
+			}
+			if !slices.Equal(notification.Pending, tt.wantPending) {
+				t.Errorf("pending = %v, want %v", notification.Pending, tt.wantPending)
+			}
+			if len(deviceRepo.devices) != 2 {
+				t.Errorf("got %d devices, want the unregistered one removed", len(deviceRepo.devices))
+			}
+
+			standIn.fail = make(map[string]bool)
+			if err := service.sendToUsers(&notification, map[string]string{}); err != nil {
+				t.Fatalf("retry failed: %v", err)
+			}
+			if len(notification.Pending) != 0 {
+				t.Errorf("pending = %v after the retry, want none", notification.Pending)
+			}
+			for target, want := range tt.wantRetry {
+				if standIn.received[target] != want {
+					t.Errorf("%s received %d messages, want %d", target, standIn.received[target], want)
+				}
+			}
+			if len(standIn.received) != len(tt.wantRetry) {
+				t.Errorf("received = %v, want %v", standIn.received, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func TestSendToUsersWithoutDevice(t *testing.T) {
+	bob := uuid.New()
+	standIn := &fcmStandIn{received: make(map[string]int)}
+	service, _ := newPushTest(t, standIn)
+
+	// Push notifications to users are never broadcast on the shared topic
+	serverID := uuid.New()
+	notification := models.NotificationOutbox{Event: models.NotificationEventDown, ServerID: &serverID, UserIDs: []uuid.UUID{bob}}
+	if err := service.sendToUsers(&notification, map[string]string{}); err == nil {
+		t.Error("expected an error without a registered device")
+	}
+	if len(standIn.received) != 0 {
+		t.Errorf("received = %v, want nothing sent", standIn.received)
+	}
+}
+
+func TestAlertNotificationsTopicBroadcast(t *testing.T) {
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	server := &models.Server{ID: uuid.New(), Name: "API Server", Severity: models.SeverityHigh}
+	quiet := models.DefaultNotificationPreference(carol)
+	quiet.Events = []string{models.NotificationEventRecovered}
+
+	tests := []struct {
+		name       string
+		recipients []uuid.UUID
+		active     []uuid.UUID
+		wantUsers  []uuid.UUID
+		wantTopic  int
+	}{
+		{
+			name:      "alert to all users is broadcast once",
+			active:    []uuid.UUID{alice, bob, carol},
+			wantUsers: []uuid.UUID{alice, bob},
+			wantTopic: 1,
+		},
+		{
+			name:       "alert to the on-call user is not broadcast",
+			recipients: []uuid.UUID{alice},
+			active:     []uuid.UUID{alice, bob, carol},
+			wantUsers:  []uuid.UUID{alice},
+		},
+		{
+			name:   "alert nobody wants is not broadcast",
+			active: []uuid.UUID{carol},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &fakeUserRepository{}
+			for _, id := range tt.active {
+				userRepo.active = append(userRepo.active, models.User{ID: id})
+			}
+			service := &notificationService{
+				fcmClient:       &fcm.Client{},
+				userRepo:        userRepo,
+				preferenceRepo:  &fakeNotificationPreferenceRepository{preferences: []models.NotificationPreference{quiet}},
+				channelRepo:     &fakeNotificationChannelRepository{},
+				templateService: NewNotificationTemplateService(&fakeNotificationTemplateRepository{}),
+			}
+
+			notifications := service.alertNotifications(models.NewAlert(models.NotificationEventDown, server), nil, tt.recipients, map[string]string{"server_name": server.Name})
+
+			var users []uuid.UUID
+			topic := 0
+			for _, n := range notifications {
+				switch n.Target {
+				case models.OutboxTargetPush:
+					users = append(users, n.UserIDs...)
+				case models.OutboxTargetTopic:
+					topic++
+					if n.Title == "" {
+						t.Error("topic broadcast has no title")
+					}
+				}
+			}
+			if !slices.Equal(users, tt.wantUsers) {
+				t.Errorf("push recipients = %v, want %v", users, tt.wantUsers)
+			}
+			if topic != tt.wantTopic {
+				t.Errorf("got %d topic broadcasts, want %d", topic, tt.wantTopic)
+			}
+		})
+	}
+}
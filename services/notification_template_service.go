@@ -0,0 +1,289 @@
+package services
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/dto"
+	"NetGuardServer/models"
+	"NetGuardServer/notifier"
+	"NetGuardServer/repository"
+	"NetGuardServer/utils"
+	"errors"
+	"log"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationTemplateService defines the interface for notification template business logic.
+// Every event has built-in templates in each locale; admins can override them per channel
+// and locale in the database.
+type NotificationTemplateService interface {
+	GetTemplates(event, channel, locale string) ([]dto.NotificationTemplateDTO, error)
+	UpdateTemplate(event, channel, locale string, userID uuid.UUID, req dto.UpdateNotificationTemplateRequest) (*dto.NotificationTemplateDTO, error)
+	DeleteTemplate(event, channel, locale string) error
+	PreviewTemplate(req dto.PreviewNotificationTemplateRequest) (*dto.NotificationPreviewDTO, error)
+	Render(event, channel, locale string, data map[string]string) (title, body string)
+}
+
+// notificationTemplateService implements NotificationTemplateService
+type notificationTemplateService struct {
+	templateRepo repository.NotificationTemplateRepository
+}
+
+// NewNotificationTemplateService creates a new notification template service instance
+func NewNotificationTemplateService(templateRepo repository.NotificationTemplateRepository) NotificationTemplateService {
+	return &notificationTemplateService{
+		templateRepo: templateRepo,
+	}
+}
+
+// DefaultLocale returns the configured locale of channel messages and of users without locale
+func DefaultLocale() string {
+	if slices.Contains(models.Locales, config.AppConfig.Notification.DefaultLocale) {
+		return config.AppConfig.Notification.DefaultLocale
+	}
+	return models.LocaleEnglish
+}
+
+// GetTemplates gets the current template of every event, channel and locale, optionally
+// filtered by event, channel or locale
+func (s *notificationTemplateService) GetTemplates(event, channel, locale string) ([]dto.NotificationTemplateDTO, error) {
+	stored, err := s.templateRepo.FindAll()
+	if err != nil {
+		return nil, errors.New("failed to get notification templates")
+	}
+
+	custom := make(map[[3]string]models.NotificationTemplate, len(stored))
+	for _, tmpl := range stored {
+		custom[[3]string{tmpl.Event, tmpl.Channel, tmpl.Locale}] = tmpl
+	}
+
+	var templates []dto.NotificationTemplateDTO
+	for _, e := range models.TemplateEvents {
+		if event != "" && e != event {
+			continue
+		}
+		for _, c := range models.TemplateChannels {
+			if channel != "" && c != channel {
+				continue
+			}
+			for _, l := range models.Locales {
+				if locale != "" && l != locale {
+					continue
+				}
+				if tmpl, ok := custom[[3]string{e, c, l}]; ok {
+					templates = append(templates, toTemplateDTO(&tmpl))
+					continue
+				}
+				builtIn, _ := notifier.DefaultTemplate(e, l)
+				templates = append(templates, dto.NotificationTemplateDTO{
+					Event:   e,
+					Channel: c,
+					Locale:  l,
+					Title:   builtIn.Title,
+					Body:    builtIn.Body,
+				})
+			}
+		}
+	}
+	return templates, nil
+}
+
+// UpdateTemplate stores the template of an event for a channel and locale.
+// The template must render the sample incident of the event.
+func (s *notificationTemplateService) UpdateTemplate(event, channel, locale string, userID uuid.UUID, req dto.UpdateNotificationTemplateRequest) (*dto.NotificationTemplateDTO, error) {
+	if err := validateTemplateKey(event, channel, locale); err != nil {
+		return nil, err
+	}
+
+	tmpl := notifier.Template{Title: req.Title, Body: req.Body}
+	if err := tmpl.Validate(); err != nil {
+		return nil, utils.ValidationError(err.Error())
+	}
+	if _, _, err := tmpl.Render(sampleIncidentData(event)); err != nil {
+		return nil, utils.ValidationError(err.Error())
+	}
+
+	err := s.templateRepo.Save(&models.NotificationTemplate{
+		Event:     event,
+		Channel:   channel,
+		Locale:    locale,
+		Title:     req.Title,
+		Body:      req.Body,
+		UpdatedBy: userID,
+		UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, errors.New("failed to save notification template")
+	}
+
+	stored, err := s.templateRepo.Find(event, channel, locale)
+	if err != nil {
+		return nil, errors.New("failed to save notification template")
+	}
+	result := toTemplateDTO(stored)
+	return &result, nil
+}
+
+// DeleteTemplate deletes the stored template of an event for a channel and locale,
+// so that the built-in template is used again
+func (s *notificationTemplateService) DeleteTemplate(event, channel, locale string) error {
+	if err := validateTemplateKey(event, channel, locale); err != nil {
+		return err
+	}
+
+	deleted, err := s.templateRepo.Delete(event, channel, locale)
+	if err != nil {
+		return errors.New("failed to delete notification template")
+	}
+	if !deleted {
+		return errors.New("notification template not found")
+	}
+	return nil
+}
+
+// PreviewTemplate renders a template against the sample incident of its event: the given
+// title and body, or the current template of the event, channel and locale
+func (s *notificationTemplateService) PreviewTemplate(req dto.PreviewNotificationTemplateRequest) (*dto.NotificationPreviewDTO, error) {
+	if req.Channel == "" {
+		req.Channel = models.TemplateChannelPush
+	}
+	if req.Locale == "" {
+		req.Locale = DefaultLocale()
+	}
+	if err := validateTemplateKey(req.Event, req.Channel, req.Locale); err != nil {
+		return nil, err
+	}
+
+	data := sampleIncidentData(req.Event)
+	maps.Copy(data, req.Data)
+
+	tmpl := s.template(req.Event, req.Channel, req.Locale)
+	if req.Title != "" {
+		tmpl.Title = req.Title
+	}
+	if req.Body != "" {
+		tmpl.Body = req.Body
+	}
+
+	title, body, err := tmpl.Render(data)
+	if err != nil {
+		return nil, utils.ValidationError(err.Error())
+	}
+
+	return &dto.NotificationPreviewDTO{
+		Title: title,
+		Body:  body,
+		Data:  data,
+	}, nil
+}
+
+// Render renders the title and body of a notification about an event for a channel and
+// locale. A stored template that fails to render falls back to the built-in one.
+func (s *notificationTemplateService) Render(event, channel, locale string, data map[string]string) (string, string) {
+	tmpl := s.template(event, channel, locale)
+	title, body, err := tmpl.Render(data)
+	if err == nil {
+		return title, body
+	}
+	log.Printf("ERROR: Failed to render %s template of %s (%s): %v", channel, event, locale, err)
+
+	builtIn, _ := notifier.DefaultTemplate(event, locale)
+	if title, body, err = builtIn.Render(data); err != nil {
+		log.Printf("ERROR: Failed to render built-in template of %s (%s): %v", event, locale, err)
+		return event, data["server_name"]
+	}
+	return title, body
+}
+
+// template returns the current template of an event for a channel and locale:
+// the stored one, or the built-in one of the locale
+func (s *notificationTemplateService) template(event, channel, locale string) notifier.Template {
+	if stored, err := s.templateRepo.Find(event, channel, locale); err == nil {
+		return notifier.Template{Title: stored.Title, Body: stored.Body}
+	}
+	builtIn, _ := notifier.DefaultTemplate(event, locale)
+	return builtIn
+}
+
+// validateTemplateKey checks the event, channel and locale of a template
+func validateTemplateKey(event, channel, locale string) error {
+	if !slices.Contains(models.TemplateEvents, event) {
+		return utils.ValidationError("unknown notification event " + event)
+	}
+	if !slices.Contains(models.TemplateChannels, channel) {
+		return utils.ValidationError("channel must be PUSH, WEBHOOK, EMAIL or SLACK")
+	}
+	if !slices.Contains(models.Locales, locale) {
+		return utils.ValidationError("locale must be en or id")
+	}
+	return nil
+}
+
+// sampleIncidentData returns the data payload of a notification about a sample incident
+func sampleIncidentData(event string) map[string]string {
+	data := map[string]string{
+		"type":             event,
+		"history_id":       "00000000-0000-0000-0000-000000000001",
+		"server_id":        "00000000-0000-0000-0000-000000000002",
+		"server_name":      "API Server",
+		"server_url":       "https://api.company.com",
+		"status":           models.ServerStatusDown,
+		"severity":         models.SeverityHigh,
+		"downtime_seconds": "1800",
+	}
+
+	switch event {
+	case models.NotificationEventDown:
+		data["reported_by"] = "00000000-0000-0000-0000-000000000003"
+	case models.NotificationEventRecovered:
+		data["status"] = models.ServerStatusUp
+	case models.NotificationEventCertExpiry:
+		data["status"] = "CERT_EXPIRING"
+		data["expires_at"] = time.Date(2030, time.January, 31, 23, 59, 0, 0, time.UTC).Format(time.RFC3339)
+		data["days_left"] = "7"
+	case models.NotificationEventFlapping:
+		data["status"] = "FLAPPING"
+		data["state_changes"] = "6"
+		data["window_seconds"] = "1800"
+		data["flapping"] = "true"
+	case models.NotificationEventAcknowledged:
+		data["status"] = models.HistoryStatusAcknowledged
+		data["acknowledged_by"] = "Alice Johnson"
+	case models.NotificationEventResolved:
+		data["status"] = models.HistoryStatusResolved
+		data["resolved_by"] = "Alice Johnson"
+		data["resolve_note"] = "Restarted the load balancer"
+	case models.NotificationEventAssigned:
+		data["status"] = "ASSIGNED"
+		data["assigned_by"] = "John Doe"
+	case models.NotificationEventEscalated:
+		data["status"] = "ESCALATED"
+		data["escalation_level"] = "2"
+	case models.NotificationEventDigest:
+		delete(data, "history_id")
+		delete(data, "downtime_seconds")
+		data["status"] = "DIGEST"
+		data["total"] = "9"
+		data["summary"] = "7 server(s) down, 2 recovered"
+		data["servers"] = "API Server, Database, Payment Gateway"
+	}
+	return data
+}
+
+// toTemplateDTO converts a stored template
+func toTemplateDTO(tmpl *models.NotificationTemplate) dto.NotificationTemplateDTO {
+	updatedAt := tmpl.UpdatedAt
+	return dto.NotificationTemplateDTO{
+		Event:     tmpl.Event,
+		Channel:   tmpl.Channel,
+		Locale:    tmpl.Locale,
+		Title:     tmpl.Title,
+		Body:      tmpl.Body,
+		Custom:    true,
+		UpdatedBy: tmpl.UpdatedBy.String(),
+		UpdatedAt: &updatedAt,
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"NetGuardServer/dto"
+	"NetGuardServer/models"
+	"NetGuardServer/notifier"
+	"NetGuardServer/utils"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestDefaultTemplatesRender(t *testing.T) {
+	stable := sampleIncidentData(models.NotificationEventFlapping)
+	stable["status"] = models.ServerStatusUp
+	stable["flapping"] = "false"
+	delete(stable, "state_changes")
+	delete(stable, "window_seconds")
+
+	tests := []struct {
+		event     string
+		locale    string
+		data      map[string]string // sample incident of the event if nil
+		wantTitle string
+		wantBody  string
+	}{
+		{event: models.NotificationEventDown, locale: models.LocaleEnglish, wantTitle: "Server DOWN: API Server", wantBody: "https://api.company.com"},
+		{event: models.NotificationEventDown, locale: models.LocaleIndonesian, wantTitle: "Server DOWN: API Server", wantBody: "https://api.company.com tidak dapat dijangkau"},
+		{event: models.NotificationEventRecovered, locale: models.LocaleEnglish, wantTitle: "Server UP: API Server", wantBody: "https://api.company.com is back online after 30m0s"},
+		{event: models.NotificationEventRecovered, locale: models.LocaleIndonesian, wantTitle: "Server UP: API Server", wantBody: "https://api.company.com kembali online setelah down selama 30m0s"},
+		{event: models.NotificationEventCertExpiry, locale: models.LocaleEnglish, wantTitle: "Certificate expiring: API Server", wantBody: "TLS certificate of https://api.company.com expires in 7 day(s) (2030-01-31 23:59 UTC)"},
+		{event: models.NotificationEventCertExpiry, locale: models.LocaleIndonesian, wantTitle: "Sertifikat segera kedaluwarsa: API Server", wantBody: "Sertifikat TLS https://api.company.com kedaluwarsa dalam 7 hari (2030-01-31 23:59 UTC)"},
+		{event: models.NotificationEventFlapping, locale: models.LocaleEnglish, wantTitle: "Server flapping: API Server", wantBody: "https://api.company.com changed state 6 times in the last 30m0s"},
+		{event: models.NotificationEventFlapping, locale: models.LocaleIndonesian, wantTitle: "Server flapping: API Server", wantBody: "https://api.company.com berganti status 6 kali dalam 30m0s terakhir"},
+		{event: models.NotificationEventFlapping, locale: models.LocaleEnglish, data: stable, wantTitle: "Server stable: API Server", wantBody: "https://api.company.com stopped flapping and is UP"},
+		{event: models.NotificationEventFlapping, locale: models.LocaleIndonesian, data: stable, wantTitle: "Server stabil: API Server", wantBody: "https://api.company.com berhenti flapping dan berstatus UP"},
+		{event: models.NotificationEventAcknowledged, locale: models.LocaleEnglish, wantTitle: "Incident acknowledged: API Server", wantBody: "Alice Johnson is working on the incident of https://api.company.com"},
+		{event: models.NotificationEventAcknowledged, locale: models.LocaleIndonesian, wantTitle: "Incident di-acknowledge: API Server", wantBody: "Alice Johnson sedang menangani incident https://api.company.com"},
+		{event: models.NotificationEventResolved, locale: models.LocaleEnglish, wantTitle: "Incident resolved: API Server", wantBody: "Alice Johnson resolved the incident of https://api.company.com: Restarted the load balancer"},
+		{event: models.NotificationEventResolved, locale: models.LocaleIndonesian, wantTitle: "Incident selesai: API Server", wantBody: "Alice Johnson menyelesaikan incident https://api.company.com: Restarted the load balancer"},
+		{event: models.NotificationEventAssigned, locale: models.LocaleEnglish, wantTitle: "Incident assigned: API Server", wantBody: "John Doe assigned you the incident of https://api.company.com"},
+		{event: models.NotificationEventAssigned, locale: models.LocaleIndonesian, wantTitle: "Incident ditugaskan: API Server", wantBody: "John Doe menugaskan incident https://api.company.com kepada Anda"},
+		{event: models.NotificationEventEscalated, locale: models.LocaleEnglish, wantTitle: "Escalation level 2: API Server", wantBody: "https://api.company.com is DOWN and nobody acknowledged the incident yet"},
+		{event: models.NotificationEventEscalated, locale: models.LocaleIndonesian, wantTitle: "Eskalasi level 2: API Server", wantBody: "https://api.company.com DOWN dan belum ada yang meng-acknowledge incident"},
+		{event: models.NotificationEventDigest, locale: models.LocaleEnglish, wantTitle: "NetGuard digest: 7 server(s) down, 2 recovered", wantBody: "Servers: API Server, Database, Payment Gateway"},
+		{event: models.NotificationEventDigest, locale: models.LocaleIndonesian, wantTitle: "Ringkasan NetGuard: 7 server(s) down, 2 recovered", wantBody: "Server: API Server, Database, Payment Gateway"},
+	}
+
+	covered := make(map[[2]string]bool)
+	for _, tt := range tests {
+		covered[[2]string{tt.event, tt.locale}] = true
+		t.Run(tt.event+" "+tt.locale+" "+tt.wantTitle, func(t *testing.T) {
+			tmpl, ok := notifier.DefaultTemplate(tt.event, tt.locale)
+			if !ok {
+				t.Fatalf("no built-in template of %s", tt.event)
+			}
+			data := tt.data
+			if data == nil {
+				data = sampleIncidentData(tt.event)
+			}
+
+			title, body, err := tmpl.Render(data)
+			if err != nil {
+				t.Fatalf("render failed: %v", err)
+			}
+			if title != tt.wantTitle || body != tt.wantBody {
+				t.Errorf("rendered %q / %q, want %q / %q", title, body, tt.wantTitle, tt.wantBody)
+			}
+		})
+	}
+
+	for _, event := range models.TemplateEvents {
+		for _, locale := range models.Locales {
+			if !covered[[2]string{event, locale}] {
+				t.Errorf("no test of the %s template of %s", locale, event)
+			}
+		}
+	}
+}
+
+func TestUpdateTemplateRejectsMalformedTemplate(t *testing.T) {
+	service := NewNotificationTemplateService(&fakeNotificationTemplateRepository{})
+
+	tests := []struct {
+		name  string
+		title string
+		body  string
+	}{
+		{name: "unclosed action", title: "Server DOWN: {{.server_name", body: "{{.server_url}}"},
+		{name: "unknown function", title: "Server DOWN: {{.server_name}}", body: "{{shout .server_url}}"},
+		// flapping is a boolean in templates, it cannot be compared with a string
+		{name: "fails to render the sample incident", title: `{{if eq .flapping "false"}}Server stable{{end}}`, body: "{{.server_url}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.UpdateTemplate(models.NotificationEventFlapping, models.TemplateChannelPush, models.LocaleEnglish, uuid.New(), dto.UpdateNotificationTemplateRequest{
+				Title: tt.title,
+				Body:  tt.body,
+			})
+			if appErr, ok := err.(utils.AppError); !ok || appErr.Code != "VALIDATION_ERROR" {
+				t.Errorf("err = %v, want a validation error", err)
+			}
+		})
+	}
+}
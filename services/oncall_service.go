@@ -0,0 +1,375 @@
+package services
+
+import (
+	"NetGuardServer/dto"
+	"NetGuardServer/models"
+	"NetGuardServer/repository"
+	"NetGuardServer/utils"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OnCallService defines the interface for on-call schedule business logic
+type OnCallService interface {
+	CreateSchedule(userID uuid.UUID, req dto.CreateOnCallScheduleRequest) (*models.OnCallSchedule, error)
+	GetSchedules() ([]models.OnCallSchedule, error)
+	GetScheduleByID(id uuid.UUID) (*models.OnCallSchedule, error)
+	UpdateSchedule(id, userID uuid.UUID, req dto.UpdateOnCallScheduleRequest) (*models.OnCallSchedule, error)
+	DeleteSchedule(id, userID uuid.UUID) error
+	CreateOverride(scheduleID, userID uuid.UUID, req dto.CreateOnCallOverrideRequest) (*models.OnCallOverride, error)
+	DeleteOverride(scheduleID, overrideID, userID uuid.UUID) error
+	GetOnCall(scheduleID uuid.UUID, at time.Time) (*dto.OnCallDTO, error)
+	GetAllOnCall(at time.Time) ([]dto.OnCallDTO, error)
+	OnCallUsers(scheduleIDs []uuid.UUID, at time.Time) ([]uuid.UUID, error)
+	ServerRecipients(server *models.Server) []uuid.UUID
+}
+
+// onCallService implements OnCallService
+type onCallService struct {
+	onCallRepo repository.OnCallRepository
+	userRepo   repository.UserRepository
+}
+
+// NewOnCallService creates a new on-call schedule service instance
+func NewOnCallService(onCallRepo repository.OnCallRepository, userRepo repository.UserRepository) OnCallService {
+	return &onCallService{
+		onCallRepo: onCallRepo,
+		userRepo:   userRepo,
+	}
+}
+
+// CreateSchedule handles on-call schedule creation business logic
+func (s *onCallService) CreateSchedule(userID uuid.UUID, req dto.CreateOnCallScheduleRequest) (*models.OnCallSchedule, error) {
+	schedule := &models.OnCallSchedule{
+		Name:        req.Name,
+		Description: req.Description,
+		Timezone:    req.Timezone,
+		CreatedBy:   userID,
+	}
+	if schedule.Timezone == "" {
+		schedule.Timezone = "UTC"
+	}
+
+	layers, err := s.toLayers(req.Layers, schedule.Location())
+	if err != nil {
+		return nil, err
+	}
+	schedule.Layers = layers
+
+	if err := s.onCallRepo.Create(schedule); err != nil {
+		return nil, errors.New("failed to create on-call schedule")
+	}
+
+	return s.GetScheduleByID(schedule.ID)
+}
+
+// GetSchedules gets all on-call schedules
+func (s *onCallService) GetSchedules() ([]models.OnCallSchedule, error) {
+	schedules, err := s.onCallRepo.FindAll()
+	if err != nil {
+		return nil, errors.New("failed to get on-call schedules")
+	}
+	return schedules, nil
+}
+
+// GetScheduleByID gets an on-call schedule by ID
+func (s *onCallService) GetScheduleByID(id uuid.UUID) (*models.OnCallSchedule, error) {
+	schedule, err := s.onCallRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("on-call schedule not found")
+	}
+	return schedule, nil
+}
+
+// UpdateSchedule updates an on-call schedule. New layer times given as local
+// time are read in the (new) time zone of the schedule.
+func (s *onCallService) UpdateSchedule(id, userID uuid.UUID, req dto.UpdateOnCallScheduleRequest) (*models.OnCallSchedule, error) {
+	schedule, err := s.onCallRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("on-call schedule not found")
+	}
+
+	if schedule.CreatedBy != userID {
+		return nil, errors.New("access denied")
+	}
+
+	// Update fields if provided
+	if req.Name != "" {
+		schedule.Name = req.Name
+	}
+	if req.Description != nil {
+		schedule.Description = *req.Description
+	}
+	if req.Timezone != "" {
+		schedule.Timezone = req.Timezone
+	}
+
+	var layers []models.OnCallLayer
+	if req.Layers != nil {
+		if layers, err = s.toLayers(req.Layers, schedule.Location()); err != nil {
+			return nil, err
+		}
+	}
+
+	schedule.Layers = nil
+	schedule.Overrides = nil
+	if err := s.onCallRepo.Update(schedule, layers); err != nil {
+		return nil, errors.New("failed to update on-call schedule")
+	}
+
+	return s.GetScheduleByID(schedule.ID)
+}
+
+// DeleteSchedule deletes an on-call schedule; its servers notify everyone again
+func (s *onCallService) DeleteSchedule(id, userID uuid.UUID) error {
+	schedule, err := s.onCallRepo.FindByID(id)
+	if err != nil {
+		return errors.New("on-call schedule not found")
+	}
+
+	if schedule.CreatedBy != userID {
+		return errors.New("access denied")
+	}
+
+	if err := s.onCallRepo.Delete(id); err != nil {
+		return errors.New("failed to delete on-call schedule")
+	}
+
+	return nil
+}
+
+// CreateOverride puts a user on call for a period, taking precedence over the layers.
+// Any user can add an override, e.g. to take over a shift.
+func (s *onCallService) CreateOverride(scheduleID, userID uuid.UUID, req dto.CreateOnCallOverrideRequest) (*models.OnCallOverride, error) {
+	schedule, err := s.onCallRepo.FindByID(scheduleID)
+	if err != nil {
+		return nil, errors.New("on-call schedule not found")
+	}
+
+	loc := schedule.Location()
+	startsAt, err := parseMaintenanceTime(req.StartsAt, loc)
+	if err != nil {
+		return nil, utils.ValidationError("starts_at must be RFC3339 or local time (2006-01-02T15:04:05)")
+	}
+	endsAt, err := parseMaintenanceTime(req.EndsAt, loc)
+	if err != nil {
+		return nil, utils.ValidationError("ends_at must be RFC3339 or local time (2006-01-02T15:04:05)")
+	}
+	if !endsAt.After(startsAt) {
+		return nil, utils.ValidationError("ends_at must be after starts_at")
+	}
+
+	onCallUserID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, utils.ValidationError("invalid user ID")
+	}
+	if _, err := s.userRepo.FindByID(onCallUserID); err != nil {
+		return nil, utils.ValidationError("user not found")
+	}
+
+	override := &models.OnCallOverride{
+		ScheduleID: schedule.ID,
+		UserID:     onCallUserID,
+		StartsAt:   startsAt,
+		EndsAt:     endsAt,
+		CreatedBy:  userID,
+	}
+	if err := s.onCallRepo.CreateOverride(override); err != nil {
+		return nil, errors.New("failed to create on-call override")
+	}
+
+	return override, nil
+}
+
+// DeleteOverride deletes an override; only its creator and the schedule creator may do so
+func (s *onCallService) DeleteOverride(scheduleID, overrideID, userID uuid.UUID) error {
+	schedule, err := s.onCallRepo.FindByID(scheduleID)
+	if err != nil {
+		return errors.New("on-call schedule not found")
+	}
+
+	override, err := s.onCallRepo.FindOverrideByID(overrideID)
+	if err != nil || override.ScheduleID != schedule.ID {
+		return errors.New("on-call override not found")
+	}
+
+	if override.CreatedBy != userID && schedule.CreatedBy != userID {
+		return errors.New("access denied")
+	}
+
+	if err := s.onCallRepo.DeleteOverride(overrideID); err != nil {
+		return errors.New("failed to delete on-call override")
+	}
+
+	return nil
+}
+
+// GetOnCall tells who is on call for a schedule at a time
+func (s *onCallService) GetOnCall(scheduleID uuid.UUID, at time.Time) (*dto.OnCallDTO, error) {
+	schedule, err := s.onCallRepo.FindByID(scheduleID)
+	if err != nil {
+		return nil, errors.New("on-call schedule not found")
+	}
+
+	onCall := s.toOnCallDTO(schedule, at)
+	return &onCall, nil
+}
+
+// GetAllOnCall tells who is on call for every schedule at a time
+func (s *onCallService) GetAllOnCall(at time.Time) ([]dto.OnCallDTO, error) {
+	schedules, err := s.onCallRepo.FindAll()
+	if err != nil {
+		return nil, errors.New("failed to get on-call schedules")
+	}
+
+	result := make([]dto.OnCallDTO, len(schedules))
+	for i := range schedules {
+		result[i] = s.toOnCallDTO(&schedules[i], at)
+	}
+	return result, nil
+}
+
+// OnCallUsers returns the distinct users on call at a time for the given schedules
+func (s *onCallService) OnCallUsers(scheduleIDs []uuid.UUID, at time.Time) ([]uuid.UUID, error) {
+	schedules, err := s.onCallRepo.FindByIDs(scheduleIDs)
+	if err != nil {
+		return nil, errors.New("failed to get on-call schedules")
+	}
+
+	seen := make(map[uuid.UUID]bool, len(schedules))
+	userIDs := make([]uuid.UUID, 0, len(schedules))
+	for i := range schedules {
+		if entry := schedules[i].OnCallAt(at); entry != nil && !seen[entry.UserID] {
+			seen[entry.UserID] = true
+			userIDs = append(userIDs, entry.UserID)
+		}
+	}
+	return userIDs, nil
+}
+
+// toOnCallDTO converts who is on call for a schedule at a time, with the user name
+func (s *onCallService) toOnCallDTO(schedule *models.OnCallSchedule, at time.Time) dto.OnCallDTO {
+	onCall := dto.OnCallDTO{
+		ScheduleID:   schedule.ID.String(),
+		ScheduleName: schedule.Name,
+		At:           at.In(schedule.Location()).Format(time.RFC3339),
+	}
+
+	entry := schedule.OnCallAt(at)
+	if entry == nil {
+		return onCall
+	}
+
+	userID := entry.UserID.String()
+	onCall.UserID = &userID
+	onCall.Source = entry.Source
+	if user, err := s.userRepo.FindByID(entry.UserID); err == nil {
+		onCall.UserName = user.Name
+	} else {
+		onCall.UserName = "Unknown User"
+	}
+	if entry.LayerID != nil {
+		for _, layer := range schedule.Layers {
+			if layer.ID == *entry.LayerID {
+				onCall.LayerName = layer.Name
+			}
+		}
+	}
+	return onCall
+}
+
+// toLayers converts the layers of a request into the model, numbered in order
+// (later layers take precedence), and checks their times and users
+func (s *onCallService) toLayers(req []dto.OnCallLayerRequest, loc *time.Location) ([]models.OnCallLayer, error) {
+	layers := make([]models.OnCallLayer, len(req))
+	for i, layerReq := range req {
+		if layerReq.Rotation != models.OnCallRotationDaily && layerReq.Rotation != models.OnCallRotationWeekly {
+			return nil, utils.ValidationError(fmt.Sprintf("layer %d: rotation must be %s or %s", i+1, models.OnCallRotationDaily, models.OnCallRotationWeekly))
+		}
+
+		startsAt, err := parseMaintenanceTime(layerReq.StartsAt, loc)
+		if err != nil {
+			return nil, utils.ValidationError(fmt.Sprintf("layer %d: starts_at must be RFC3339 or local time (2006-01-02T15:04:05)", i+1))
+		}
+
+		var endsAt *time.Time
+		if layerReq.EndsAt != "" {
+			end, err := parseMaintenanceTime(layerReq.EndsAt, loc)
+			if err != nil {
+				return nil, utils.ValidationError(fmt.Sprintf("layer %d: ends_at must be RFC3339 or local time (2006-01-02T15:04:05)", i+1))
+			}
+			if !end.After(startsAt) {
+				return nil, utils.ValidationError(fmt.Sprintf("layer %d: ends_at must be after starts_at", i+1))
+			}
+			endsAt = &end
+		}
+
+		if (layerReq.RestrictFrom == "") != (layerReq.RestrictTo == "") {
+			return nil, utils.ValidationError(fmt.Sprintf("layer %d: restrict_from and restrict_to must be set together", i+1))
+		}
+
+		userIDs := make([]uuid.UUID, 0, len(layerReq.UserIDs))
+		for _, id := range layerReq.UserIDs {
+			userID, err := uuid.Parse(id)
+			if err != nil {
+				return nil, utils.ValidationError("invalid user ID " + id)
+			}
+			userIDs = append(userIDs, userID)
+		}
+		if err := s.checkUsers(userIDs); err != nil {
+			return nil, err
+		}
+
+		layers[i] = models.OnCallLayer{
+			Level:        i + 1,
+			Name:         layerReq.Name,
+			StartsAt:     startsAt,
+			EndsAt:       endsAt,
+			Rotation:     layerReq.Rotation,
+			UserIDs:      userIDs,
+			RestrictFrom: layerReq.RestrictFrom,
+			RestrictTo:   layerReq.RestrictTo,
+		}
+	}
+	return layers, nil
+}
+
+// checkUsers checks that every user exists (a user may appear more than once in a rotation)
+func (s *onCallService) checkUsers(userIDs []uuid.UUID) error {
+	users, err := s.userRepo.FindByIDs(userIDs)
+	if err != nil {
+		return errors.New("failed to get users")
+	}
+	found := make(map[uuid.UUID]bool, len(users))
+	for _, user := range users {
+		found[user.ID] = true
+	}
+	for _, userID := range userIDs {
+		if !found[userID] {
+			return utils.ValidationError("user " + userID.String() + " not found")
+		}
+	}
+	return nil
+}
+
+// ServerRecipients returns who is notified about a server: the user currently on call for its
+// on-call schedule, or nobody in particular (all users) without schedule or on-call user
+func (s *onCallService) ServerRecipients(server *models.Server) []uuid.UUID {
+	if server.OnCallScheduleID == nil {
+		return nil
+	}
+
+	userIDs, err := s.OnCallUsers([]uuid.UUID{*server.OnCallScheduleID}, time.Now())
+	if err != nil {
+		log.Printf("ERROR: Failed to get on-call user of server %s, notifying subscribed users: %v", server.ID, err)
+		return nil
+	}
+	if len(userIDs) == 0 {
+		log.Printf("WARN: Nobody on call for server %s, notifying subscribed users", server.Name)
+	}
+	return userIDs
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"NetGuardServer/dto"
+	"NetGuardServer/models"
+	"NetGuardServer/utils"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestServerRecipients(t *testing.T) {
+	alice := uuid.New()
+	now := time.Now()
+	onCall := models.OnCallSchedule{ID: uuid.New(), Timezone: "UTC", Layers: []models.OnCallLayer{
+		{ID: uuid.New(), Level: 1, StartsAt: now.Add(-time.Hour), Rotation: models.OnCallRotationDaily, UserIDs: []uuid.UUID{alice}},
+	}}
+	nobody := models.OnCallSchedule{ID: uuid.New(), Timezone: "UTC", Layers: []models.OnCallLayer{
+		{ID: uuid.New(), Level: 1, StartsAt: now.Add(time.Hour), Rotation: models.OnCallRotationDaily, UserIDs: []uuid.UUID{alice}},
+	}}
+	deleted := uuid.New()
+
+	tests := []struct {
+		name       string
+		scheduleID *uuid.UUID
+		want       []uuid.UUID // none notifies all users
+	}{
+		{name: "user on call", scheduleID: &onCall.ID, want: []uuid.UUID{alice}},
+		{name: "all users without schedule"},
+		{name: "all users when nobody is on call", scheduleID: &nobody.ID},
+		{name: "all users when the schedule is gone", scheduleID: &deleted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeOnCallRepository{schedules: map[uuid.UUID]models.OnCallSchedule{onCall.ID: onCall, nobody.ID: nobody}}
+			service := NewOnCallService(repo, &fakeUserRepository{})
+
+			got := service.ServerRecipients(&models.Server{ID: uuid.New(), Name: "API Server", OnCallScheduleID: tt.scheduleID})
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("recipients = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateScheduleRotation(t *testing.T) {
+	tests := []struct {
+		rotation string
+		wantErr  bool
+	}{
+		{rotation: models.OnCallRotationDaily},
+		{rotation: models.OnCallRotationWeekly},
+		{rotation: models.RecurrenceMonthly, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rotation, func(t *testing.T) {
+			repo := &fakeOnCallRepository{schedules: make(map[uuid.UUID]models.OnCallSchedule)}
+			service := NewOnCallService(repo, &fakeUserRepository{})
+
+			_, err := service.CreateSchedule(uuid.New(), dto.CreateOnCallScheduleRequest{
+				Name: "Ops",
+				Layers: []dto.OnCallLayerRequest{{
+					StartsAt: "2026-03-02T09:00:00Z",
+					Rotation: tt.rotation,
+					UserIDs:  []string{uuid.NewString()},
+				}},
+			})
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if appErr, ok := err.(utils.AppError); !ok || appErr.Code != "VALIDATION_ERROR" {
+				t.Errorf("err = %v, want a validation error", err)
+			}
+		})
+	}
+}
@@ -1,53 +1,113 @@
 package services
 
 import (
+	"NetGuardServer/checker"
+	"NetGuardServer/dto"
 	"NetGuardServer/models"
 	"NetGuardServer/repository"
+	"NetGuardServer/utils"
 	"errors"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 // ServerService defines the interface for server business logic
 type ServerService interface {
-	CreateServer(userID uuid.UUID, name, url string) (*models.Server, error)
+	CreateServer(userID uuid.UUID, req dto.CreateServerRequest) (*models.Server, error)
 	GetAllServers() ([]models.Server, error)
 	GetServersByUserID(userID uuid.UUID) ([]models.Server, error)
 	GetServerByID(id uuid.UUID) (*models.Server, error)
-	UpdateServer(id, userID uuid.UUID, name, url string) (*models.Server, error)
+	UpdateServer(id, userID uuid.UUID, req dto.UpdateServerRequest) (*models.Server, error)
+	RotateHeartbeatToken(id, userID uuid.UUID) (*models.Server, error)
 	DeleteServer(id, userID uuid.UUID) error
+	GetDependencyGraph() (*dto.DependencyGraphDTO, error)
 }
 
 // serverService implements ServerService
 type serverService struct {
-	serverRepo repository.ServerRepository
+	serverRepo     repository.ServerRepository
+	escalationRepo repository.EscalationRepository
+	onCallRepo     repository.OnCallRepository
 }
 
 // NewServerService creates a new server service instance
-func NewServerService(serverRepo repository.ServerRepository) ServerService {
+func NewServerService(serverRepo repository.ServerRepository, escalationRepo repository.EscalationRepository, onCallRepo repository.OnCallRepository) ServerService {
 	return &serverService{
-		serverRepo: serverRepo,
+		serverRepo:     serverRepo,
+		escalationRepo: escalationRepo,
+		onCallRepo:     onCallRepo,
 	}
 }
 
 // CreateServer handles server creation business logic
-func (s *serverService) CreateServer(userID uuid.UUID, name, url string) (*models.Server, error) {
+func (s *serverService) CreateServer(userID uuid.UUID, req dto.CreateServerRequest) (*models.Server, error) {
 	server := &models.Server{
-		Name:      name,
-		URL:       url,
-		CreatedBy: userID,
+		Name:                   req.Name,
+		URL:                    req.URL,
+		CheckType:              req.CheckType,
+		Host:                   req.Host,
+		Port:                   req.Port,
+		DNSRecordType:          req.DNSRecordType,
+		DNSExpected:            req.DNSExpected,
+		TimeoutSeconds:         req.TimeoutSeconds,
+		Assertions:             toHTTPAssertions(req.Assertions),
+		HeartbeatPeriodSeconds: req.HeartbeatPeriod,
+		HeartbeatGraceSeconds:  req.HeartbeatGrace,
+		ConfirmReporters:       req.ConfirmReporters,
+		ConfirmWindowSeconds:   req.ConfirmWindowSeconds,
+		FailureThreshold:       req.FailureThreshold,
+		SuccessThreshold:       req.SuccessThreshold,
+		Tags:                   normalizeTags(req.Tags),
+		Division:               strings.TrimSpace(req.Division),
+		Severity:               req.Severity,
+		CreatedBy:              userID,
+	}
+
+	if err := prepareCheckSettings(server); err != nil {
+		return nil, err
+	}
+
+	policyID, err := s.resolveEscalationPolicy(req.EscalationPolicyID)
+	if err != nil {
+		return nil, err
+	}
+	server.EscalationPolicyID = policyID
+
+	scheduleID, err := s.resolveOnCallSchedule(req.OnCallScheduleID)
+	if err != nil {
+		return nil, err
+	}
+	server.OnCallScheduleID = scheduleID
+
+	dependsOn, err := s.resolveDependencies(server.ID, req.DependsOn)
+	if err != nil {
+		return nil, err
 	}
 
 	if err := s.serverRepo.Create(server); err != nil {
 		return nil, errors.New("failed to create server")
 	}
 
+	if len(dependsOn) > 0 {
+		if err := s.serverRepo.ReplaceDependencies(server.ID, dependsOn); err != nil {
+			return nil, errors.New("failed to save server dependencies")
+		}
+	}
+	server.DependsOn = dependsOn
+
 	return server, nil
 }
 
 // GetAllServers gets all servers from all users
 func (s *serverService) GetAllServers() ([]models.Server, error) {
-	return s.serverRepo.GetAllServers()
+	servers, err := s.serverRepo.GetAllServers()
+	if err != nil {
+		return nil, err
+	}
+	return servers, s.fillDependencies(servers)
 }
 
 // GetServersByUserID gets all servers for a user
@@ -56,6 +116,9 @@ func (s *serverService) GetServersByUserID(userID uuid.UUID) ([]models.Server, e
 	if err != nil {
 		return nil, errors.New("failed to get servers")
 	}
+	if err := s.fillDependencies(servers); err != nil {
+		return nil, errors.New("failed to get servers")
+	}
 	return servers, nil
 }
 
@@ -65,11 +128,49 @@ func (s *serverService) GetServerByID(id uuid.UUID) (*models.Server, error) {
 	if err != nil {
 		return nil, errors.New("server not found")
 	}
+
+	server.DependsOn, err = s.serverRepo.FindDependencies(id)
+	if err != nil {
+		return nil, errors.New("failed to get server dependencies")
+	}
 	return server, nil
 }
 
+// GetDependencyGraph gets all servers and the dependencies between them
+func (s *serverService) GetDependencyGraph() (*dto.DependencyGraphDTO, error) {
+	servers, err := s.serverRepo.GetAllServers()
+	if err != nil {
+		return nil, errors.New("failed to get servers")
+	}
+	dependencies, err := s.serverRepo.FindAllDependencies()
+	if err != nil {
+		return nil, errors.New("failed to get server dependencies")
+	}
+
+	graph := &dto.DependencyGraphDTO{
+		Nodes: make([]dto.DependencyNodeDTO, len(servers)),
+		Edges: make([]dto.DependencyEdgeDTO, len(dependencies)),
+	}
+	for i, server := range servers {
+		graph.Nodes[i] = dto.DependencyNodeDTO{
+			ID:     server.ID.String(),
+			Name:   server.Name,
+			URL:    server.URL,
+			Status: server.Status,
+		}
+	}
+	for i, dependency := range dependencies {
+		graph.Edges[i] = dto.DependencyEdgeDTO{
+			ServerID:    dependency.ServerID.String(),
+			DependsOnID: dependency.DependsOnID.String(),
+		}
+	}
+
+	return graph, nil
+}
+
 // UpdateServer updates server information
-func (s *serverService) UpdateServer(id, userID uuid.UUID, name, url string) (*models.Server, error) {
+func (s *serverService) UpdateServer(id, userID uuid.UUID, req dto.UpdateServerRequest) (*models.Server, error) {
 	// Check if server exists and belongs to user
 	server, err := s.serverRepo.FindByID(id)
 	if err != nil {
@@ -80,21 +181,322 @@ func (s *serverService) UpdateServer(id, userID uuid.UUID, name, url string) (*m
 		return nil, errors.New("access denied")
 	}
 
+	// Other check types store their check target as URL, which an HTTP check cannot use
+	if req.CheckType == models.CheckTypeHTTP && server.CheckType != models.CheckTypeHTTP && req.URL == "" {
+		return nil, utils.ValidationError("url is required when changing the check type to HTTP")
+	}
+
 	// Update fields if provided
-	if name != "" {
-		server.Name = name
+	if req.Name != "" {
+		server.Name = req.Name
+	}
+	if req.URL != "" {
+		server.URL = req.URL
+	}
+	if req.ConfirmReporters != nil {
+		server.ConfirmReporters = *req.ConfirmReporters
 	}
-	if url != "" {
-		server.URL = url
+	if req.ConfirmWindowSeconds != nil {
+		server.ConfirmWindowSeconds = *req.ConfirmWindowSeconds
+	}
+	if req.FailureThreshold != nil {
+		server.FailureThreshold = *req.FailureThreshold
+	}
+	if req.SuccessThreshold != nil {
+		server.SuccessThreshold = *req.SuccessThreshold
+	}
+	if req.CheckType != "" {
+		server.CheckType = req.CheckType
+	}
+	if req.Host != "" {
+		server.Host = req.Host
+	}
+	if req.Port != nil {
+		server.Port = *req.Port
+	}
+	if req.DNSRecordType != "" {
+		server.DNSRecordType = req.DNSRecordType
+	}
+	if req.DNSExpected != nil {
+		server.DNSExpected = *req.DNSExpected
+	}
+	if req.TimeoutSeconds != nil {
+		server.TimeoutSeconds = *req.TimeoutSeconds
+	}
+	if req.Assertions != nil {
+		server.Assertions = toHTTPAssertions(req.Assertions)
+	}
+	if req.HeartbeatPeriod != nil {
+		server.HeartbeatPeriodSeconds = *req.HeartbeatPeriod
+	}
+	if req.HeartbeatGrace != nil {
+		server.HeartbeatGraceSeconds = *req.HeartbeatGrace
+	}
+	if req.Tags != nil {
+		server.Tags = normalizeTags(req.Tags)
+	}
+	if req.Division != nil {
+		server.Division = strings.TrimSpace(*req.Division)
+	}
+	if req.Severity != "" {
+		server.Severity = req.Severity
+	}
+
+	if err := prepareCheckSettings(server); err != nil {
+		return nil, err
+	}
+
+	if req.EscalationPolicyID != nil {
+		if server.EscalationPolicyID, err = s.resolveEscalationPolicy(*req.EscalationPolicyID); err != nil {
+			return nil, err
+		}
+	}
+	if req.OnCallScheduleID != nil {
+		if server.OnCallScheduleID, err = s.resolveOnCallSchedule(*req.OnCallScheduleID); err != nil {
+			return nil, err
+		}
+	}
+
+	var dependsOn []uuid.UUID
+	if req.DependsOn != nil {
+		if dependsOn, err = s.resolveDependencies(server.ID, req.DependsOn); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := s.serverRepo.Update(server); err != nil {
 		return nil, errors.New("failed to update server")
 	}
 
+	if req.DependsOn != nil {
+		if err := s.serverRepo.ReplaceDependencies(server.ID, dependsOn); err != nil {
+			return nil, errors.New("failed to save server dependencies")
+		}
+		server.DependsOn = dependsOn
+	} else if server.DependsOn, err = s.serverRepo.FindDependencies(server.ID); err != nil {
+		return nil, errors.New("failed to get server dependencies")
+	}
+
 	return server, nil
 }
 
+// resolveDependencies parses the parent servers of a server and checks that they exist
+// and that depending on them would not create a cycle in the dependency graph
+func (s *serverService) resolveDependencies(serverID uuid.UUID, ids []string) ([]uuid.UUID, error) {
+	dependsOn := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		parentID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, utils.ValidationError("invalid dependency ID " + id)
+		}
+		if parentID == serverID {
+			return nil, utils.ValidationError("server cannot depend on itself")
+		}
+		if !seen[parentID] {
+			seen[parentID] = true
+			dependsOn = append(dependsOn, parentID)
+		}
+	}
+	if len(dependsOn) == 0 {
+		return dependsOn, nil
+	}
+
+	parents, err := s.serverRepo.FindByIDs(dependsOn)
+	if err != nil {
+		return nil, errors.New("failed to get servers")
+	}
+	if len(parents) != len(dependsOn) {
+		return nil, utils.ValidationError("dependency server not found")
+	}
+
+	dependencies, err := s.serverRepo.FindAllDependencies()
+	if err != nil {
+		return nil, errors.New("failed to get server dependencies")
+	}
+
+	// Graph with the server's new dependencies: a cycle exists if the server is reachable from them
+	graph := make(map[uuid.UUID][]uuid.UUID)
+	for _, dependency := range dependencies {
+		if dependency.ServerID != serverID {
+			graph[dependency.ServerID] = append(graph[dependency.ServerID], dependency.DependsOnID)
+		}
+	}
+	visited := make(map[uuid.UUID]bool)
+	stack := append([]uuid.UUID(nil), dependsOn...)
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if current == serverID {
+			return nil, utils.ValidationError("dependencies would create a cycle")
+		}
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		stack = append(stack, graph[current]...)
+	}
+
+	return dependsOn, nil
+}
+
+// resolveEscalationPolicy parses the escalation policy of a server and checks that it exists.
+// An empty ID means no policy.
+func (s *serverService) resolveEscalationPolicy(id string) (*uuid.UUID, error) {
+	if id == "" {
+		return nil, nil
+	}
+
+	policyID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, utils.ValidationError("invalid escalation policy ID")
+	}
+	if _, err := s.escalationRepo.FindByID(policyID); err != nil {
+		return nil, utils.ValidationError("escalation policy not found")
+	}
+	return &policyID, nil
+}
+
+// resolveOnCallSchedule parses the on-call schedule of a server and checks that it exists.
+// An empty ID means no schedule.
+func (s *serverService) resolveOnCallSchedule(id string) (*uuid.UUID, error) {
+	if id == "" {
+		return nil, nil
+	}
+
+	scheduleID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, utils.ValidationError("invalid on-call schedule ID")
+	}
+	if _, err := s.onCallRepo.FindByID(scheduleID); err != nil {
+		return nil, utils.ValidationError("on-call schedule not found")
+	}
+	return &scheduleID, nil
+}
+
+// fillDependencies sets the parent servers of every server in the list
+func (s *serverService) fillDependencies(servers []models.Server) error {
+	dependencies, err := s.serverRepo.FindAllDependencies()
+	if err != nil {
+		return err
+	}
+
+	dependsOn := make(map[uuid.UUID][]uuid.UUID)
+	for _, dependency := range dependencies {
+		dependsOn[dependency.ServerID] = append(dependsOn[dependency.ServerID], dependency.DependsOnID)
+	}
+	for i := range servers {
+		servers[i].DependsOn = dependsOn[servers[i].ID]
+	}
+	return nil
+}
+
+// normalizeTags trims and lower-cases tags and drops empty and duplicate ones
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// toHTTPAssertions converts assertions from a request into the model
+func toHTTPAssertions(req *dto.HTTPAssertionsRequest) models.HTTPAssertions {
+	if req == nil {
+		return models.HTTPAssertions{}
+	}
+	return models.HTTPAssertions{
+		StatusCodes:       req.StatusCodes,
+		BodyContains:      req.BodyContains,
+		BodyNotContains:   req.BodyNotContains,
+		BodyRegex:         req.BodyRegex,
+		JSONPath:          req.JSONPath,
+		JSONPathValue:     req.JSONPathValue,
+		Headers:           req.Headers,
+		MaxResponseTimeMs: req.MaxResponseTimeMs,
+	}
+}
+
+// prepareCheckSettings validates the check settings of a server and, for non-HTTP
+// checks, replaces the URL shown in history and notifications with the check target.
+// Heartbeat servers get their ping token and next ping deadline here.
+func prepareCheckSettings(server *models.Server) error {
+	if server.CheckType == "" {
+		server.CheckType = models.CheckTypeHTTP
+	}
+	if server.CheckType != models.CheckTypeHTTP {
+		server.URL = checker.Target(server)
+		server.Assertions = models.HTTPAssertions{}
+	}
+
+	if err := checker.Validate(server); err != nil {
+		return utils.ValidationError(err.Error())
+	}
+
+	if server.CheckType != models.CheckTypeHeartbeat {
+		server.HeartbeatToken = nil
+		server.HeartbeatExpectedBy = nil
+		return nil
+	}
+
+	if server.HeartbeatToken == nil {
+		if err := issueHeartbeatToken(server); err != nil {
+			return err
+		}
+	}
+	// Only the backend reports on heartbeat servers, so there is nobody to confirm a DOWN
+	server.ConfirmReporters = 1
+
+	from := time.Now()
+	if server.LastHeartbeatAt != nil {
+		from = *server.LastHeartbeatAt
+	}
+	server.ScheduleHeartbeat(from)
+	return nil
+}
+
+// RotateHeartbeatToken replaces the ping token of a heartbeat server, so pings with the
+// previous token are rejected. The new token is returned to the creator once.
+func (s *serverService) RotateHeartbeatToken(id, userID uuid.UUID) (*models.Server, error) {
+	server, err := s.serverRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("server not found")
+	}
+
+	if server.CreatedBy != userID {
+		return nil, errors.New("access denied")
+	}
+	if server.CheckType != models.CheckTypeHeartbeat {
+		return nil, utils.ValidationError("server is not a heartbeat monitor")
+	}
+
+	if err := issueHeartbeatToken(server); err != nil {
+		return nil, err
+	}
+	if err := s.serverRepo.Update(server); err != nil {
+		return nil, errors.New("failed to update server")
+	}
+
+	if server.DependsOn, err = s.serverRepo.FindDependencies(server.ID); err != nil {
+		return nil, errors.New("failed to get server dependencies")
+	}
+	return server, nil
+}
+
+// issueHeartbeatToken generates a new ping token for a heartbeat server and sets it
+// as IssuedHeartbeatToken, the only field the token is returned in
+func issueHeartbeatToken(server *models.Server) error {
+	token, err := utils.GenerateToken(24)
+	if err != nil {
+		return errors.New("failed to generate heartbeat token")
+	}
+	server.HeartbeatToken = &token
+	server.IssuedHeartbeatToken = token
+	return nil
+}
 
 // DeleteServer deletes a server
 func (s *serverService) DeleteServer(id, userID uuid.UUID) error {
@@ -113,4 +515,4 @@ func (s *serverService) DeleteServer(id, userID uuid.UUID) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
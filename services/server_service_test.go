@@ -0,0 +1,192 @@
+package services
+
+import (
+	"NetGuardServer/dto"
+	"NetGuardServer/models"
+	"NetGuardServer/utils"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestUpdateServerCheckType(t *testing.T) {
+	owner := uuid.New()
+	port := 5432
+
+	tests := []struct {
+		name      string
+		server    models.Server
+		req       dto.UpdateServerRequest
+		wantError bool
+		wantURL   string
+	}{
+		{
+			name:    "TCP server shows its target as URL",
+			server:  models.Server{CheckType: models.CheckTypeHTTP, URL: "https://db.company.com"},
+			req:     dto.UpdateServerRequest{CheckType: models.CheckTypeTCP, Host: "db.company.com", Port: &port},
+			wantURL: "tcp://db.company.com:5432",
+		},
+		{
+			name:      "switching to HTTP requires a URL",
+			server:    models.Server{CheckType: models.CheckTypeTCP, URL: "tcp://db.company.com:5432", Host: "db.company.com", Port: 5432},
+			req:       dto.UpdateServerRequest{CheckType: models.CheckTypeHTTP},
+			wantError: true,
+		},
+		{
+			name:    "switching to HTTP with a URL",
+			server:  models.Server{CheckType: models.CheckTypeTCP, URL: "tcp://db.company.com:5432", Host: "db.company.com", Port: 5432},
+			req:     dto.UpdateServerRequest{CheckType: models.CheckTypeHTTP, URL: "https://db.company.com/health"},
+			wantURL: "https://db.company.com/health",
+		},
+		{
+			name:    "HTTP server keeps its URL",
+			server:  models.Server{CheckType: models.CheckTypeHTTP, URL: "https://api.company.com"},
+			req:     dto.UpdateServerRequest{CheckType: models.CheckTypeHTTP, Name: "API"},
+			wantURL: "https://api.company.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.server.ID = uuid.New()
+			tt.server.Name = "Server"
+			tt.server.CreatedBy = owner
+			repo := newFakeServerRepository(tt.server)
+			service := NewServerService(repo, nil, nil)
+
+			updated, err := service.UpdateServer(tt.server.ID, owner, tt.req)
+			if tt.wantError {
+				if appErr, ok := err.(utils.AppError); !ok || appErr.Code != "VALIDATION_ERROR" {
+					t.Fatalf("error = %v, want a validation error", err)
+				}
+				stored, _ := repo.FindByID(tt.server.ID)
+				if stored.CheckType != tt.server.CheckType || stored.URL != tt.server.URL {
+					t.Errorf("rejected update changed the server to %s %s", stored.CheckType, stored.URL)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if updated.URL != tt.wantURL {
+				t.Errorf("URL = %s, want %s", updated.URL, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestHeartbeatToken(t *testing.T) {
+	owner, other := uuid.New(), uuid.New()
+	repo := newFakeServerRepository()
+	service := NewServerService(repo, nil, nil)
+
+	created, err := service.CreateServer(owner, dto.CreateServerRequest{
+		Name:            "Nightly Backup",
+		CheckType:       models.CheckTypeHeartbeat,
+		HeartbeatPeriod: 86400,
+	})
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	if created.IssuedHeartbeatToken == "" || created.HeartbeatToken == nil || *created.HeartbeatToken != created.IssuedHeartbeatToken {
+		t.Fatalf("created server did not return its heartbeat token")
+	}
+	firstToken := created.IssuedHeartbeatToken
+
+	tests := []struct {
+		name      string
+		userID    uuid.UUID
+		rotate    bool
+		wantError string
+		wantToken bool
+	}{
+		{name: "reading a server hides the token"},
+		{name: "others cannot rotate the token", userID: other, rotate: true, wantError: "access denied"},
+		{name: "creator rotates the token", userID: owner, rotate: true, wantToken: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, err := service.GetServerByID(created.ID)
+			if tt.rotate {
+				server, err = service.RotateHeartbeatToken(created.ID, tt.userID)
+			}
+			if tt.wantError != "" {
+				if err == nil || err.Error() != tt.wantError {
+					t.Fatalf("error = %v, want %s", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			body, err := json.Marshal(server)
+			if err != nil {
+				t.Fatalf("failed to marshal server: %v", err)
+			}
+			stored, _ := repo.FindByID(created.ID)
+			leaked := strings.Contains(string(body), *stored.HeartbeatToken) || strings.Contains(string(body), firstToken)
+			switch {
+			case tt.wantToken && server.IssuedHeartbeatToken != *stored.HeartbeatToken:
+				t.Errorf("rotated token was not returned")
+			case tt.wantToken && server.IssuedHeartbeatToken == firstToken:
+				t.Errorf("token was not replaced")
+			case !tt.wantToken && leaked:
+				t.Errorf("response leaks the heartbeat token: %s", body)
+			}
+		})
+	}
+}
+
+func TestResolveDependencies(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	// c depends on b, which depends on a
+	dependencies := []models.ServerDependency{{ServerID: b, DependsOnID: a}, {ServerID: c, DependsOnID: b}}
+
+	tests := []struct {
+		name     string
+		serverID uuid.UUID
+		ids      []uuid.UUID
+		want     []uuid.UUID
+		wantErr  string
+	}{
+		{name: "self-dependency", serverID: a, ids: []uuid.UUID{a}, wantErr: "server cannot depend on itself"},
+		{name: "direct cycle", serverID: a, ids: []uuid.UUID{b}, wantErr: "dependencies would create a cycle"},
+		{name: "indirect cycle", serverID: a, ids: []uuid.UUID{c}, wantErr: "dependencies would create a cycle"},
+		{name: "unknown server", serverID: c, ids: []uuid.UUID{uuid.New()}, wantErr: "dependency server not found"},
+		{name: "server replacing its own dependencies", serverID: b, ids: []uuid.UUID{a}, want: []uuid.UUID{a}},
+		{name: "duplicates collapse", serverID: c, ids: []uuid.UUID{a, b, a}, want: []uuid.UUID{a, b}},
+		{name: "no dependencies", serverID: a, want: []uuid.UUID{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeServerRepository(models.Server{ID: a}, models.Server{ID: b}, models.Server{ID: c})
+			repo.dependencies = dependencies
+			service := &serverService{serverRepo: repo}
+
+			ids := make([]string, len(tt.ids))
+			for i, id := range tt.ids {
+				ids[i] = id.String()
+			}
+			got, err := service.resolveDependencies(tt.serverID, ids)
+			if tt.wantErr != "" {
+				appErr, ok := err.(utils.AppError)
+				if !ok || appErr.Message != tt.wantErr {
+					t.Errorf("err = %v, want validation error %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("depends on %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
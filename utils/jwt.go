@@ -6,15 +6,18 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-// GenerateJWT generates a JWT token for the user
-func GenerateJWT(user models.User) (string, error) {
+// GenerateJWT generates an access token for the user with the given token ID (jti),
+// by which it can be revoked before it expires
+func GenerateJWT(user models.User, tokenID uuid.UUID, expiresAt time.Time) (string, error) {
 	claims := jwt.MapClaims{
+		"jti":     tokenID.String(),
 		"user_id": user.ID,
 		"email":   user.Email,
 		"name":    user.Name,
-		"exp":     time.Now().Add(time.Hour * 24 * time.Duration(config.AppConfig.JWT.AccessTokenExpDays)).Unix(),
+		"exp":     expiresAt.Unix(),
 		"iat":     time.Now().Unix(),
 	}
 
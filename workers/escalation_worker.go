@@ -0,0 +1,44 @@
+package workers
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/services"
+	"context"
+	"time"
+)
+
+// EscalationWorker periodically re-notifies unacknowledged incidents along their escalation policy.
+// Its schedule is stored on the incidents, so pending escalations continue after a restart.
+type EscalationWorker struct {
+	escalationService services.EscalationService
+	interval          time.Duration
+	loop              periodic
+}
+
+// NewEscalationWorker creates a new escalation worker instance
+func NewEscalationWorker(escalationService services.EscalationService) *EscalationWorker {
+	interval := time.Duration(config.AppConfig.Escalation.CheckIntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	return &EscalationWorker{
+		escalationService: escalationService,
+		interval:          interval,
+	}
+}
+
+// Start runs the worker loop in the background until Stop is called
+func (w *EscalationWorker) Start() {
+	w.loop.start("Escalation worker", w.interval, w.RunOnce)
+}
+
+// Stop waits for the current run to finish and stops the loop
+func (w *EscalationWorker) Stop() {
+	w.loop.stop("Escalation worker")
+}
+
+// RunOnce notifies the next level of every due escalation
+func (w *EscalationWorker) RunOnce(ctx context.Context) {
+	w.escalationService.RunDue(ctx)
+}
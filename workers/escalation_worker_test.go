@@ -0,0 +1,31 @@
+package workers
+
+import (
+	"NetGuardServer/services"
+	"context"
+	"testing"
+)
+
+// runDueEscalationService records the contexts RunDue is called with
+type runDueEscalationService struct {
+	services.EscalationService
+	runs []context.Context
+}
+
+func (s *runDueEscalationService) RunDue(ctx context.Context) {
+	s.runs = append(s.runs, ctx)
+}
+
+func TestEscalationWorkerRunOnce(t *testing.T) {
+	service := &runDueEscalationService{}
+	worker := &EscalationWorker{escalationService: service}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	worker.RunOnce(ctx)
+
+	// The worker's context is passed on, so Stop interrupts a long run between incidents
+	if len(service.runs) != 1 || service.runs[0] != ctx {
+		t.Fatalf("RunDue called %d times, want once with the worker's context", len(service.runs))
+	}
+}
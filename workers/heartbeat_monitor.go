@@ -0,0 +1,43 @@
+package workers
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/services"
+	"context"
+	"time"
+)
+
+// HeartbeatMonitor periodically looks for heartbeat servers whose ping is overdue
+type HeartbeatMonitor struct {
+	heartbeatService services.HeartbeatService
+	interval         time.Duration
+	loop             periodic
+}
+
+// NewHeartbeatMonitor creates a new heartbeat monitor instance
+func NewHeartbeatMonitor(heartbeatService services.HeartbeatService) *HeartbeatMonitor {
+	interval := time.Duration(config.AppConfig.Heartbeat.CheckIntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	return &HeartbeatMonitor{
+		heartbeatService: heartbeatService,
+		interval:         interval,
+	}
+}
+
+// Start runs the monitor loop in the background until Stop is called
+func (m *HeartbeatMonitor) Start() {
+	m.loop.start("Heartbeat monitor", m.interval, m.RunOnce)
+}
+
+// Stop waits for the current run to finish and stops the loop
+func (m *HeartbeatMonitor) Stop() {
+	m.loop.stop("Heartbeat monitor")
+}
+
+// RunOnce reports every overdue heartbeat server as DOWN
+func (m *HeartbeatMonitor) RunOnce(ctx context.Context) {
+	m.heartbeatService.CheckMissed(ctx)
+}
@@ -0,0 +1,88 @@
+package workers
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/models"
+	"NetGuardServer/repository"
+	"context"
+	"log"
+	"time"
+)
+
+// MetricsJob periodically rolls raw check results up into hourly and daily
+// buckets and prunes raw check results older than the retention period
+type MetricsJob struct {
+	checkResultRepo repository.CheckResultRepository
+	interval        time.Duration
+	retention       time.Duration
+	lastRun         time.Time
+	loop            periodic
+}
+
+// NewMetricsJob creates a new metrics rollup and retention job
+func NewMetricsJob(checkResultRepo repository.CheckResultRepository) *MetricsJob {
+	cfg := config.AppConfig.Metrics
+
+	interval := time.Duration(cfg.RollupIntervalMinutes) * time.Minute
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	// Raw rows must outlive at least one full daily bucket before they are pruned
+	retention := time.Duration(cfg.RawRetentionDays) * 24 * time.Hour
+	if retention < 2*24*time.Hour {
+		retention = 2 * 24 * time.Hour
+	}
+
+	return &MetricsJob{
+		checkResultRepo: checkResultRepo,
+		interval:        interval,
+		retention:       retention,
+	}
+}
+
+// Start runs the rollup loop in the background until Stop is called
+func (j *MetricsJob) Start() {
+	j.loop.start("Metrics job", j.interval, j.RunOnce)
+}
+
+// Stop waits for the current rollup to finish and stops the loop
+func (j *MetricsJob) Stop() {
+	j.loop.stop("Metrics job")
+}
+
+// RunOnce recomputes every bucket touched since the previous run and prunes expired raw rows.
+// The first run backfills the whole retention period so restarts never leave gaps; the oldest
+// bucket is only partially retained, so its stored rollup is kept rather than recomputed.
+func (j *MetricsJob) RunOnce(ctx context.Context) {
+	now := time.Now()
+	cutoff := now.Add(-j.retention)
+	since := j.lastRun
+	if since.IsZero() {
+		since = cutoff
+	}
+
+	if err := j.checkResultRepo.Rollup(models.ResolutionHour, since, now, cutoff); err != nil {
+		log.Printf("ERROR: Failed to compute hourly rollups: %v", err)
+		return
+	}
+
+	if err := j.checkResultRepo.Rollup(models.ResolutionDay, since, now, cutoff); err != nil {
+		log.Printf("ERROR: Failed to compute daily rollups: %v", err)
+		return
+	}
+
+	j.lastRun = now
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	deleted, err := j.checkResultRepo.DeleteOlderThan(cutoff)
+	if err != nil {
+		log.Printf("ERROR: Failed to prune check results: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("INFO: Pruned %d check results older than %s", deleted, j.retention)
+	}
+}
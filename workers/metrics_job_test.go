@@ -0,0 +1,70 @@
+package workers
+
+import (
+	"NetGuardServer/repository"
+	"context"
+	"testing"
+	"time"
+)
+
+// rollupCall records the arguments of a Rollup call
+type rollupCall struct {
+	resolution         string
+	from, to, prunedBy time.Time
+}
+
+// metricsCheckResultRepository records the rollups and prunes of the metrics job
+type metricsCheckResultRepository struct {
+	repository.CheckResultRepository
+	rollups []rollupCall
+	pruned  []time.Time
+}
+
+func (r *metricsCheckResultRepository) Rollup(resolution string, from, to, prunedBefore time.Time) error {
+	r.rollups = append(r.rollups, rollupCall{resolution, from, to, prunedBefore})
+	return nil
+}
+
+func (r *metricsCheckResultRepository) DeleteOlderThan(before time.Time) (int64, error) {
+	r.pruned = append(r.pruned, before)
+	return 0, nil
+}
+
+func TestMetricsJobRunOnce(t *testing.T) {
+	repo := &metricsCheckResultRepository{}
+	job := &MetricsJob{checkResultRepo: repo, retention: 48 * time.Hour}
+
+	job.RunOnce(context.Background())
+	firstRun := job.lastRun
+	job.RunOnce(context.Background())
+
+	if len(repo.rollups) != 4 || len(repo.pruned) != 2 {
+		t.Fatalf("got %d rollups and %d prunes, want 4 and 2", len(repo.rollups), len(repo.pruned))
+	}
+
+	tests := []struct {
+		name     string
+		call     rollupCall
+		wantFrom time.Time
+		pruned   time.Time
+	}{
+		{name: "first run backfills the retention period", call: repo.rollups[0], wantFrom: repo.pruned[0], pruned: repo.pruned[0]},
+		{name: "first run daily backfill", call: repo.rollups[1], wantFrom: repo.pruned[0], pruned: repo.pruned[0]},
+		{name: "next run starts at the previous run", call: repo.rollups[2], wantFrom: firstRun, pruned: repo.pruned[1]},
+		{name: "next run daily rollup", call: repo.rollups[3], wantFrom: firstRun, pruned: repo.pruned[1]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.call.from.Equal(tt.wantFrom) {
+				t.Errorf("from = %s, want %s", tt.call.from, tt.wantFrom)
+			}
+			// Buckets before the prune cutoff must never be overwritten
+			if !tt.call.prunedBy.Equal(tt.pruned) {
+				t.Errorf("prunedBefore = %s, want the prune cutoff %s", tt.call.prunedBy, tt.pruned)
+			}
+			if got := tt.call.to.Sub(tt.call.prunedBy); got != 48*time.Hour {
+				t.Errorf("prunedBefore is %s before the run, want the retention period", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package workers
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/services"
+	"context"
+	"time"
+)
+
+// NotificationWorker periodically delivers the notifications of the outbox and queues the
+// digests of rate limited recipients. Pending notifications are stored, so deliveries
+// continue after a restart.
+type NotificationWorker struct {
+	notificationService services.NotificationService
+	interval            time.Duration
+	loop                periodic
+}
+
+// NewNotificationWorker creates a new notification worker instance
+func NewNotificationWorker(notificationService services.NotificationService) *NotificationWorker {
+	interval := time.Duration(config.AppConfig.Outbox.PollIntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	return &NotificationWorker{
+		notificationService: notificationService,
+		interval:            interval,
+	}
+}
+
+// Start runs the worker loop in the background until Stop is called
+func (w *NotificationWorker) Start() {
+	w.loop.start("Notification worker", w.interval, w.RunOnce)
+}
+
+// Stop waits for the current run to finish and stops the loop
+func (w *NotificationWorker) Stop() {
+	w.loop.stop("Notification worker")
+}
+
+// RunOnce queues the due digests and delivers the due notifications of the outbox
+func (w *NotificationWorker) RunOnce(ctx context.Context) {
+	w.notificationService.FlushDigests(ctx)
+	w.notificationService.DeliverDue(ctx)
+}
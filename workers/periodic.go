@@ -0,0 +1,65 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// periodic runs a job immediately and then on every interval until stopped.
+// It is embedded by the background workers to share their Start/Stop lifecycle.
+type periodic struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// start launches the job loop; calling it while already running is a no-op
+func (p *periodic) start(name string, interval time.Duration, job func(ctx context.Context)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		log.Printf("INFO: %s started (interval %s)", name, interval)
+		for {
+			job(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// stop cancels the running job and waits for the loop to exit.
+// It reports whether the loop was running.
+func (p *periodic) stop(name string) bool {
+	p.mu.Lock()
+	cancel := p.cancel
+	p.cancel = nil
+	p.mu.Unlock()
+
+	if cancel == nil {
+		return false
+	}
+
+	cancel()
+	p.wg.Wait()
+	log.Printf("INFO: %s stopped", name)
+	return true
+}
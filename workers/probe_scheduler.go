@@ -0,0 +1,99 @@
+package workers
+
+import (
+	"NetGuardServer/config"
+	"NetGuardServer/models"
+	"NetGuardServer/repository"
+	"NetGuardServer/services"
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// ProbeScheduler periodically checks every server from the backend itself,
+// so servers are monitored even when no mobile client is open
+type ProbeScheduler struct {
+	serverRepo     repository.ServerRepository
+	monitorService services.MonitorService
+	enabled        bool
+	interval       time.Duration
+	concurrency    int
+	loop           periodic
+}
+
+// NewProbeScheduler creates a new probe scheduler instance
+func NewProbeScheduler(serverRepo repository.ServerRepository, monitorService services.MonitorService) *ProbeScheduler {
+	cfg := config.AppConfig.Probe
+
+	interval := time.Duration(cfg.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = 10
+	}
+
+	return &ProbeScheduler{
+		serverRepo:     serverRepo,
+		monitorService: monitorService,
+		enabled:        cfg.Enabled,
+		interval:       interval,
+		concurrency:    concurrency,
+	}
+}
+
+// Start runs the probe loop in the background until Stop is called
+func (s *ProbeScheduler) Start() {
+	if !s.enabled {
+		log.Println("INFO: Probe scheduler disabled")
+		return
+	}
+	s.loop.start("Probe scheduler", s.interval, s.RunOnce)
+}
+
+// Stop cancels in-flight probes and waits for the probe loop to exit
+func (s *ProbeScheduler) Stop() {
+	s.loop.stop("Probe scheduler")
+}
+
+// RunOnce checks every server once and reports the results.
+// Heartbeat servers are skipped, they ping the backend themselves.
+func (s *ProbeScheduler) RunOnce(ctx context.Context) {
+	servers, err := s.serverRepo.GetAllServers()
+	if err != nil {
+		log.Printf("ERROR: Probe scheduler failed to load servers: %v", err)
+		return
+	}
+
+	sem := make(chan struct{}, s.concurrency)
+	var wg sync.WaitGroup
+
+	for i := range servers {
+		server := &servers[i]
+		if server.CheckType == models.CheckTypeHeartbeat {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			_, err := s.monitorService.RunCheck(ctx, server, models.SystemUserID)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("ERROR: Failed to report probe result for server %s: %v", server.ID, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
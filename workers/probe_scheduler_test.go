@@ -0,0 +1,125 @@
+package workers
+
+import (
+	"NetGuardServer/checker"
+	"NetGuardServer/models"
+	"NetGuardServer/repository"
+	"NetGuardServer/services"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// probeServerRepository serves a fixed list of servers to the probe scheduler
+type probeServerRepository struct {
+	repository.ServerRepository
+	servers []models.Server
+}
+
+func (r *probeServerRepository) GetAllServers() ([]models.Server, error) {
+	return r.servers, nil
+}
+
+// probeMonitorService runs the real checks and records the result reported for each server
+type probeMonitorService struct {
+	runner *checker.Runner
+
+	mu      sync.Mutex
+	results map[uuid.UUID]checker.Result
+}
+
+func (s *probeMonitorService) RunCheck(ctx context.Context, server *models.Server, requestedBy uuid.UUID) (checker.Result, error) {
+	result := s.runner.Check(ctx, server)
+	s.mu.Lock()
+	s.results[server.ID] = result
+	s.mu.Unlock()
+	return result, nil
+}
+
+func (s *probeMonitorService) ReportStatus(server *models.Server, report services.StatusReport) error {
+	return nil
+}
+
+func TestProbeSchedulerRunOnce(t *testing.T) {
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/error", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	defer close(release)
+
+	tests := []struct {
+		name      string
+		server    models.Server
+		want      string
+		skipped   bool
+		wantError bool
+	}{
+		{name: "2xx is UP", server: models.Server{CheckType: models.CheckTypeHTTP, URL: ts.URL + "/ok"}, want: models.ServerStatusUp},
+		{name: "5xx is DOWN", server: models.Server{CheckType: models.CheckTypeHTTP, URL: ts.URL + "/error"}, want: models.ServerStatusDown, wantError: true},
+		{name: "timeout is DOWN", server: models.Server{CheckType: models.CheckTypeHTTP, URL: ts.URL + "/slow"}, want: models.ServerStatusDown, wantError: true},
+		{name: "heartbeat is skipped", server: models.Server{CheckType: models.CheckTypeHeartbeat, URL: ts.URL + "/ok"}, skipped: true},
+	}
+
+	servers := make([]models.Server, len(tests))
+	for i := range tests {
+		tests[i].server.ID = uuid.New()
+		tests[i].server.Name = tests[i].name
+		servers[i] = tests[i].server
+	}
+
+	monitor := &probeMonitorService{
+		runner: checker.NewRunner(map[string]checker.Checker{
+			models.CheckTypeHTTP: checker.NewHTTPChecker(),
+		}, 200*time.Millisecond),
+		results: make(map[uuid.UUID]checker.Result),
+	}
+	scheduler := &ProbeScheduler{
+		serverRepo:     &probeServerRepository{servers: servers},
+		monitorService: monitor,
+		concurrency:    2,
+	}
+
+	start := time.Now()
+	scheduler.RunOnce(context.Background())
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("RunOnce took %s, the slow server was not timed out", elapsed)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, checked := monitor.results[tt.server.ID]
+			if tt.skipped {
+				if checked {
+					t.Fatalf("server was checked, want skipped")
+				}
+				return
+			}
+			if !checked {
+				t.Fatalf("server was not checked")
+			}
+			if result.Status != tt.want {
+				t.Errorf("status = %s, want %s (%s)", result.Status, tt.want, result.Message)
+			}
+			if tt.wantError && result.Message == "" {
+				t.Errorf("DOWN result has no reason")
+			}
+		})
+	}
+}